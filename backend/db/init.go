@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"reflect"
+	"sort"
 	"time"
 
 	"mini_CMS_Desktop_App/models" // models paketini içe aktardığınızdan emin olun
@@ -83,11 +85,20 @@ func Init() error {
 		(*models.ActivityCode)(nil),
 		(*models.CrewDocument)(nil),
 		(*models.OffDayTable)(nil),
+		(*models.OffDayCompliance)(nil),
 		(*models.CrewInfo)(nil),
 		(*models.Penalty)(nil),
 		(*models.AircraftCrewNeed)(nil),
 		(*models.Trip)(nil),
 		(*models.BriefDebriefRule)(nil),
+		(*models.FTLRuleSet)(nil),
+		(*models.AircraftType)(nil),
+		(*models.CrewPosition)(nil),
+		(*models.CommanderDiscretion)(nil),
+		(*models.CrewBaseAirport)(nil),
+		(*models.Airport)(nil),
+		(*models.RegulationAssignment)(nil),
+		(*models.FTLRecalcJob)(nil),
 		(*models.UserPreference)(nil),
 		// ✅ Yeni eklenen: Kullanıcılar tablosu için model
 		(*models.User)(nil),
@@ -99,6 +110,9 @@ func Init() error {
 			// Tablo oluşturma hatasında detaylı bilgi ver
 			return fmt.Errorf("'%T' tablosu oluşturulamadı: %w", model, err)
 		}
+		if err := addMissingColumns(context.Background(), DB, model); err != nil {
+			return fmt.Errorf("'%T' tablosunun eksik sütunları eklenemedi: %w", model, err)
+		}
 		log.Printf("✔️ Tablo oluşturuldu/kontrol edildi: %T", model)
 	}
 
@@ -107,6 +121,293 @@ func Init() error {
 		log.Printf("❌ Brief/Debrief kuralları başlatılamadı: %v", err)
 	}
 
+	// 📦 FTL kural setlerini başlat (tablo boşsa yerleşik limitlerle versiyon 1 oluştur)
+	if err := initializeFTLRuleSets(context.Background(), DB); err != nil {
+		log.Printf("❌ FTL kural setleri başlatılamadı: %v", err)
+	}
+
+	// 📦 Yedek/rezerv aktivite kodu tanımlarını yükle
+	if err := LoadStandbyActivityCodes(context.Background()); err != nil {
+		log.Printf("❌ Yedek aktivite kodları yüklenemedi: %v", err)
+	}
+
+	// 📦 Uçak tiplerini başlat ve önbelleğe al (tablo boşsa varsayılan CMS tipi haritası eklenir)
+	if err := initializeAircraftTypes(context.Background(), DB); err != nil {
+		log.Printf("❌ Uçak tipi tanımları başlatılamadı: %v", err)
+	}
+	if err := LoadAircraftTypes(context.Background()); err != nil {
+		log.Printf("❌ Uçak tipi tanımları yüklenemedi: %v", err)
+	}
+
+	// 📦 Ekip pozisyonlarını başlat ve önbelleğe al (tablo boşsa varsayılan pozisyon tanımları eklenir)
+	if err := initializeCrewPositions(context.Background(), DB); err != nil {
+		log.Printf("❌ Ekip pozisyonu tanımları başlatılamadı: %v", err)
+	}
+	if err := LoadCrewPositions(context.Background()); err != nil {
+		log.Printf("❌ Ekip pozisyonu tanımları yüklenemedi: %v", err)
+	}
+
+	// 📦 Ana üs meydanlarını başlat (tablo boşsa mevcut ana üsler eklenir)
+	if err := initializeCrewBaseAirports(context.Background(), DB); err != nil {
+		log.Printf("❌ Ana üs meydanları başlatılamadı: %v", err)
+	}
+
+	// 📦 Meydan referans verisini başlat ve yükle (tablo boşsa varsayılan zaman dilimi haritası eklenir)
+	if err := initializeAirports(context.Background(), DB); err != nil {
+		log.Printf("❌ Meydan referans verisi başlatılamadı: %v", err)
+	}
+	if err := syncBaseAirportFlags(context.Background(), DB); err != nil {
+		log.Printf("❌ Ana üs bayrakları eşitlenemedi: %v", err)
+	}
+	if err := LoadAirports(context.Background()); err != nil {
+		log.Printf("❌ Meydan referans verisi yüklenemedi: %v", err)
+	}
+
+	return nil
+}
+
+// addMissingColumns, modelde olup mevcut tabloda bulunmayan sütunları ekler.
+// CREATE TABLE IF NOT EXISTS var olan tabloya yeni alan eklemediği için, modele sonradan
+// eklenen alanlar (ör. trips.rule_set_version) bu fonksiyonla tabloya yansıtılır.
+// Varsayılanı olan notnull alanlar mevcut satırlar varsayılanla doldurularak NOT NULL eklenir.
+func addMissingColumns(ctx context.Context, db *bun.DB, model interface{}) error {
+	table := db.Table(reflect.TypeOf(model))
+	for _, field := range table.Fields {
+		if field.IsPK {
+			continue
+		}
+		query := "ALTER TABLE ? ADD COLUMN IF NOT EXISTS ? " + field.CreateTableSQLType
+		if field.SQLDefault != "" {
+			if field.NotNull {
+				query += " NOT NULL"
+			}
+			query += " DEFAULT " + field.SQLDefault
+		}
+		if _, err := db.ExecContext(ctx, query, bun.Ident(table.Name), bun.Ident(field.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initializeFTLRuleSets, ftl_rule_sets tablosu boşsa yerleşik SHT-FTL limitlerini
+// aktif versiyon 1 olarak ekler.
+func initializeFTLRuleSets(ctx context.Context, db *bun.DB) error {
+	count, err := db.NewSelect().Model((*models.FTLRuleSet)(nil)).Count(ctx)
+	if err != nil {
+		return fmt.Errorf("ftl_rule_sets sayılırken hata: %w", err)
+	}
+	if count > 0 {
+		log.Println("Bilgi: ftl_rule_sets tablosunda zaten veri var, başlatma atlandı.")
+		return nil
+	}
+
+	now := time.Now()
+	ruleSet := models.DefaultFTLRuleSet()
+	ruleSet.Version = 1
+	ruleSet.Name = "SHT-FTL"
+	ruleSet.Description = "Başlangıç kural seti (koddaki sabit limitlerden aktarıldı)"
+	ruleSet.IsActive = true
+	ruleSet.ActivatedAt = &now
+
+	if _, err := db.NewInsert().Model(&ruleSet).Exec(ctx); err != nil {
+		return fmt.Errorf("FTL kural seti başlangıç verisi eklenirken hata: %w", err)
+	}
+	log.Println("Bilgi: ftl_rule_sets tablosuna başlangıç kural seti eklendi.")
+	return nil
+}
+
+// LoadStandbyActivityCodes, activity_codes tablosunda standby_type tanımlı kodları okuyup
+// models.GetDutyTypeFromActual ve FTL hesaplamalarında kullanılmak üzere kaydeder.
+func LoadStandbyActivityCodes(ctx context.Context) error {
+	var activityCodes []models.ActivityCode
+	err := DB.NewSelect().
+		Model(&activityCodes).
+		Column("activity_code", "standby_type").
+		Where("COALESCE(standby_type, '') <> ''").
+		Scan(ctx)
+	if err != nil {
+		return fmt.Errorf("yedek aktivite kodları okunamadı: %w", err)
+	}
+
+	codes := make(map[string]string, len(activityCodes))
+	for _, ac := range activityCodes {
+		codes[ac.ActivityCode] = ac.StandbyType
+	}
+	models.RegisterStandbyActivityCodes(codes)
+	log.Printf("Bilgi: %d yedek/rezerv aktivite kodu tanımı yüklendi.", len(codes))
+	return nil
+}
+
+// initializeAircraftTypes, aircraft_types tablosu boşsa daha önce kodda sabit olan CMS tipi → gövde sınıfı
+// haritasını ekler. Eski aircraft_rest_facilities tablosu varsa oradaki dinlenme tesisi sınıfları aktarılır;
+// yoksa tüm tipler dinlenme tesissiz eklenir ve sınıflar API veya import ile açıkça girilir.
+func initializeAircraftTypes(ctx context.Context, db *bun.DB) error {
+	count, err := db.NewSelect().Model((*models.AircraftType)(nil)).Count(ctx)
+	if err != nil {
+		return fmt.Errorf("aircraft_types sayılırken hata: %w", err)
+	}
+	if count > 0 {
+		log.Println("Bilgi: aircraft_types tablosunda zaten veri var, başlatma atlandı.")
+		return nil
+	}
+
+	types := models.DefaultAircraftTypes()
+	legacyMigrated := false
+
+	var legacyTable sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('aircraft_rest_facilities')::text").Scan(&legacyTable); err == nil && legacyTable.Valid {
+		var legacy []struct {
+			CmsType           string `bun:"cms_type"`
+			RestFacilityClass int    `bun:"rest_facility_class"`
+		}
+		if err := db.NewSelect().Table("aircraft_rest_facilities").Column("cms_type", "rest_facility_class").Scan(ctx, &legacy); err != nil {
+			log.Printf("⚠️ aircraft_rest_facilities okunamadı, tipler dinlenme tesissiz ekleniyor: %v", err)
+		} else if len(legacy) > 0 {
+			classes := make(map[string]int, len(legacy))
+			for _, l := range legacy {
+				classes[l.CmsType] = l.RestFacilityClass
+			}
+			for i := range types {
+				types[i].RestFacilityClass = classes[types[i].CmsType]
+			}
+			legacyMigrated = true
+			log.Printf("Bilgi: aircraft_rest_facilities tablosundan %d dinlenme tesisi sınıfı aktarıldı.", len(legacy))
+		}
+	}
+
+	if _, err := db.NewInsert().Model(&types).Exec(ctx); err != nil {
+		return fmt.Errorf("uçak tipi başlangıç verileri eklenirken hata: %w", err)
+	}
+	log.Printf("Bilgi: aircraft_types tablosuna %d CMS tipi eklendi.", len(types))
+	if !legacyMigrated {
+		log.Println("⚠️ Dinlenme tesisi sınıfları tanımlı değil; uzatılmış ekip limitleri için sınıflar API veya import ile girilmelidir.")
+	}
+	return nil
+}
+
+// LoadAircraftTypes, aircraft_types tablosunu okuyup models.GetAircraftTypeFromCmsType ve dinlenme tesisi
+// sorgularında kullanılmak üzere önbelleğe alır.
+func LoadAircraftTypes(ctx context.Context) error {
+	var types []models.AircraftType
+	if err := DB.NewSelect().Model(&types).Scan(ctx); err != nil {
+		return fmt.Errorf("uçak tipleri okunamadı: %w", err)
+	}
+	models.RegisterAircraftTypes(types)
+	log.Printf("Bilgi: %d CMS uçak tipi tanımı yüklendi.", len(types))
+	return nil
+}
+
+// initializeCrewPositions, crew_positions tablosu boşsa daha önce kodda sabit olan kokpit ve kabin pozisyonu
+// haritalarını ekler.
+func initializeCrewPositions(ctx context.Context, db *bun.DB) error {
+	count, err := db.NewSelect().Model((*models.CrewPosition)(nil)).Count(ctx)
+	if err != nil {
+		return fmt.Errorf("crew_positions sayılırken hata: %w", err)
+	}
+	if count > 0 {
+		log.Println("Bilgi: crew_positions tablosunda zaten veri var, başlatma atlandı.")
+		return nil
+	}
+
+	positions := models.DefaultCrewPositions()
+	if _, err := db.NewInsert().Model(&positions).Exec(ctx); err != nil {
+		return fmt.Errorf("ekip pozisyonu başlangıç verileri eklenirken hata: %w", err)
+	}
+	log.Printf("Bilgi: crew_positions tablosuna %d pozisyon eklendi.", len(positions))
+	return nil
+}
+
+// LoadCrewPositions, crew_positions tablosunu okuyup models.GetCrewTypeFromFlightPosition ve açık trip ihtiyaç
+// hesabında kullanılmak üzere önbelleğe alır.
+func LoadCrewPositions(ctx context.Context) error {
+	var positions []models.CrewPosition
+	if err := DB.NewSelect().Model(&positions).Scan(ctx); err != nil {
+		return fmt.Errorf("ekip pozisyonları okunamadı: %w", err)
+	}
+	models.RegisterCrewPositions(positions)
+	log.Printf("Bilgi: %d ekip pozisyonu tanımı yüklendi.", len(positions))
+	return nil
+}
+
+// initializeCrewBaseAirports, crew_base_airports tablosu boşsa daha önce kodda sabit olan İstanbul
+// meydanlarını (IST, SAW, ISL) tek bir ana üs grubu olarak ekler. Diğer ana üsler tabloya veri olarak eklenir.
+func initializeCrewBaseAirports(ctx context.Context, db *bun.DB) error {
+	count, err := db.NewSelect().Model((*models.CrewBaseAirport)(nil)).Count(ctx)
+	if err != nil {
+		return fmt.Errorf("crew_base_airports sayılırken hata: %w", err)
+	}
+	if count > 0 {
+		log.Println("Bilgi: crew_base_airports tablosunda zaten veri var, başlatma atlandı.")
+		return nil
+	}
+
+	airports := []models.CrewBaseAirport{
+		{AirportCode: "IST", BaseCode: "IST"},
+		{AirportCode: "SAW", BaseCode: "IST"},
+		{AirportCode: "ISL", BaseCode: "IST"},
+	}
+	if _, err := db.NewInsert().Model(&airports).Exec(ctx); err != nil {
+		return fmt.Errorf("ana üs meydanı başlangıç verileri eklenirken hata: %w", err)
+	}
+	log.Printf("Bilgi: crew_base_airports tablosuna %d meydan eklendi.", len(airports))
+	return nil
+}
+
+// initializeAirports, airports tablosu boşsa daha önce kodda sabit olan meydan → zaman dilimi haritasını
+// ekler. Kodlar, koordinatlar ve ülke bilgisi import ile tamamlanır.
+func initializeAirports(ctx context.Context, db *bun.DB) error {
+	count, err := db.NewSelect().Model((*models.Airport)(nil)).Count(ctx)
+	if err != nil {
+		return fmt.Errorf("airports sayılırken hata: %w", err)
+	}
+	if count > 0 {
+		log.Println("Bilgi: airports tablosunda zaten veri var, başlatma atlandı.")
+		return nil
+	}
+
+	zones := models.DefaultAirportTimeZones()
+	airports := make([]models.Airport, 0, len(zones))
+	for code, tz := range zones {
+		airports = append(airports, models.Airport{IATACode: code, TimeZone: tz})
+	}
+	sort.Slice(airports, func(i, j int) bool { return airports[i].IATACode < airports[j].IATACode })
+
+	if _, err := db.NewInsert().Model(&airports).Exec(ctx); err != nil {
+		return fmt.Errorf("meydan başlangıç verileri eklenirken hata: %w", err)
+	}
+	log.Printf("Bilgi: airports tablosuna %d meydan eklendi.", len(airports))
+	return nil
+}
+
+// syncBaseAirportFlags, crew_base_airports tablosunda grubu tanımlı olup airports tablosunda ana üs olarak
+// işaretlenmemiş meydanları işaretler. Ana üs tespiti airports.is_base üzerinden yapıldığından, bayrak
+// sütunundan önce eklenmiş veya başlangıç verisiyle gelen grup eşlemeleri bu şekilde etkin kalır.
+func syncBaseAirportFlags(ctx context.Context, db *bun.DB) error {
+	res, err := db.NewUpdate().
+		Model((*models.Airport)(nil)).
+		Set("is_base = TRUE").
+		Where("is_base = FALSE").
+		Where("iata_code IN (SELECT airport_code FROM crew_base_airports)").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("airports.is_base güncellenirken hata: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		log.Printf("Bilgi: %d meydan crew_base_airports tanımına göre ana üs olarak işaretlendi.", affected)
+	}
+	return nil
+}
+
+// LoadAirports, airports tablosundaki meydanları okuyup yerel saat hesaplarında kullanılmak üzere kaydeder
+// (models.GetAirportLocation).
+func LoadAirports(ctx context.Context) error {
+	var airports []models.Airport
+	if err := DB.NewSelect().Model(&airports).Scan(ctx); err != nil {
+		return fmt.Errorf("meydanlar okunamadı: %w", err)
+	}
+	models.RegisterAirports(airports)
+	log.Printf("Bilgi: %d meydan tanımı yüklendi.", len(airports))
 	return nil
 }
 
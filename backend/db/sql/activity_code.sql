@@ -4,4 +4,7 @@ CREATE TABLE
         activity_code TEXT UNIQUE,                          
         activity_group_code TEXT,
         activity_code_explanation TEXT
-    );
\ No newline at end of file
+    );
+
+-- Yedek/rezerv tipi (airport_standby, home_standby, reserve); boş = yedek değil
+ALTER TABLE activity_codes ADD COLUMN IF NOT EXISTS standby_type TEXT;
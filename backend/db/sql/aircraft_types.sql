@@ -0,0 +1,12 @@
+-- aircraft_types.sql
+CREATE TABLE
+    IF NOT EXISTS aircraft_types (
+        data_id SERIAL PRIMARY KEY,
+        cms_type VARCHAR(10) NOT NULL UNIQUE, -- CMS uçak tipi (actuals.plane_cms_type)
+        body_class VARCHAR(20) NOT NULL, -- "DAR GÖVDE", "GENİŞ GÖVDE"
+        icao_type VARCHAR(4), -- Örn: "B77W"
+        is_cargo BOOLEAN NOT NULL DEFAULT FALSE,
+        rest_facility_class INTEGER NOT NULL DEFAULT 0, -- 0 = yok, 1-3 = CS FTL.1.205(c) sınıfı
+        description TEXT,
+        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
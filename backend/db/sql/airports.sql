@@ -0,0 +1,15 @@
+-- airports.sql
+CREATE TABLE
+    IF NOT EXISTS airports (
+        data_id SERIAL PRIMARY KEY,
+        iata_code VARCHAR(3) NOT NULL UNIQUE, -- Örn: "SAW"
+        icao_code VARCHAR(4), -- Örn: "LTFJ"
+        name TEXT,
+        city TEXT,
+        country VARCHAR(2), -- ISO 3166-1 alpha-2
+        time_zone VARCHAR(64) NOT NULL, -- IANA, örn: "Europe/Istanbul"
+        latitude DOUBLE PRECISION,
+        longitude DOUBLE PRECISION,
+        is_base BOOLEAN NOT NULL DEFAULT FALSE, -- Ekip ana üssü olarak kullanılan meydan
+        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
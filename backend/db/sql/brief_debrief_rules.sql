@@ -288,4 +288,8 @@ VALUES
         80
     );
 
--- Brief: 01:00 [cite: 895], Debrief: 00:15 [cite: 1145]
\ No newline at end of file
+-- Brief: 01:00 [cite: 895], Debrief: 00:15 [cite: 1145]
+-- Kural versiyonlarının geçerlilik aralığı (görev tarihine göre, iki uç dahil; NULL = sınırsız)
+ALTER TABLE brief_debrief_rules ADD COLUMN IF NOT EXISTS valid_from DATE;
+ALTER TABLE brief_debrief_rules ADD COLUMN IF NOT EXISTS valid_to DATE;
+ALTER TABLE brief_debrief_rules ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,13 @@
+-- commander_discretions.sql
+CREATE TABLE
+    IF NOT EXISTS commander_discretions (
+        data_id SERIAL PRIMARY KEY,
+        trip_id VARCHAR(255) NOT NULL, -- trips.trip_id
+        extension_min INTEGER NOT NULL, -- Kaptanın onayladığı UGS uzatması (dakika)
+        reason TEXT NOT NULL,
+        commander_person_id VARCHAR(255) NOT NULL,
+        recorded_by_user_id BIGINT,
+        created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_commander_discretions_trip_id ON commander_discretions (trip_id);
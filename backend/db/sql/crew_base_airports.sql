@@ -0,0 +1,16 @@
+-- crew_base_airports.sql
+CREATE TABLE
+    IF NOT EXISTS crew_base_airports (
+        data_id SERIAL PRIMARY KEY,
+        airport_code VARCHAR(10) NOT NULL UNIQUE, -- IATA meydan kodu
+        base_code VARCHAR(10) NOT NULL, -- Ana üs grubu (crew_info.base_location ile eşleşir)
+        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
+
+INSERT INTO crew_base_airports (airport_code, base_code)
+VALUES ('IST', 'IST'), ('SAW', 'IST'), ('ISL', 'IST')
+ON CONFLICT (airport_code) DO NOTHING;
+
+-- Grubu tanımlı meydanlar airports tablosunda ana üs olarak işaretlenir (ana üs tespiti airports.is_base ile yapılır)
+UPDATE airports SET is_base = TRUE
+WHERE is_base = FALSE AND iata_code IN (SELECT airport_code FROM crew_base_airports);
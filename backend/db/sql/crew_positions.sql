@@ -0,0 +1,11 @@
+-- crew_positions.sql
+CREATE TABLE
+    IF NOT EXISTS crew_positions (
+        data_id SERIAL PRIMARY KEY,
+        position_code VARCHAR(5) NOT NULL UNIQUE, -- actuals.flight_position, örn: "C1", "L"
+        crew_type VARCHAR(30) NOT NULL, -- "Uçuş Ekibi", "Kabin Ekibi"
+        rank_order INTEGER NOT NULL DEFAULT 0, -- Küçük değer daha kıdemli
+        crew_need_column VARCHAR(2), -- aircraft_crew_need sütunu: C/P/J/EF/A/S/L/EC/T
+        description TEXT,
+        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
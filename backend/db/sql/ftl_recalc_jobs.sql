@@ -0,0 +1,18 @@
+-- ftl_recalc_jobs.sql
+CREATE TABLE
+    IF NOT EXISTS ftl_recalc_jobs (
+        job_id VARCHAR(64) PRIMARY KEY, -- WebSocket process_id ile aynı
+        scope VARCHAR(16) NOT NULL, -- 'period', 'base', 'fleet', 'flagged'
+        scope_value VARCHAR(64) NOT NULL,
+        status VARCHAR(16) NOT NULL, -- 'running', 'completed', 'cancelled', 'failed'
+        workers INTEGER NOT NULL DEFAULT 0,
+        total_crew INTEGER NOT NULL DEFAULT 0,
+        crew_processed INTEGER NOT NULL DEFAULT 0,
+        trips_saved INTEGER NOT NULL DEFAULT 0,
+        violations_found INTEGER NOT NULL DEFAULT 0,
+        error_count INTEGER NOT NULL DEFAULT 0,
+        errors JSONB,
+        created_by_user_id BIGINT,
+        started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+        finished_at TIMESTAMP WITH TIME ZONE
+    );
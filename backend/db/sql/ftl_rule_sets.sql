@@ -0,0 +1,26 @@
+-- ftl_rule_sets.sql
+CREATE TABLE
+    IF NOT EXISTS ftl_rule_sets (
+        data_id SERIAL PRIMARY KEY,
+        version INTEGER NOT NULL UNIQUE, -- Otomatik artan versiyon numarası
+        name VARCHAR(255),
+        description TEXT,
+        effective_from TIMESTAMP WITH TIME ZONE NOT NULL, -- Yürürlük başlangıcı (dahil)
+        effective_to TIMESTAMP WITH TIME ZONE, -- Yürürlük bitişi (hariç), NULL = süresiz
+        is_active BOOLEAN NOT NULL DEFAULT FALSE,
+        max_duty_7_days_min INTEGER NOT NULL,
+        max_duty_14_days_min INTEGER NOT NULL,
+        max_duty_28_days_min INTEGER NOT NULL,
+        max_duty_year_min INTEGER NOT NULL,
+        max_flight_28_days_min INTEGER NOT NULL,
+        max_flight_12_months_min INTEGER NOT NULL,
+        max_flight_year_min INTEGER NOT NULL,
+        min_rest_home_base_min INTEGER NOT NULL,
+        min_rest_away_min INTEGER NOT NULL,
+        activated_at TIMESTAMP WITH TIME ZONE,
+        created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Düzensiz görev limitleri
+ALTER TABLE ftl_rule_sets ADD COLUMN IF NOT EXISTS max_consecutive_night_duties INTEGER NOT NULL DEFAULT 3;
+ALTER TABLE ftl_rule_sets ADD COLUMN IF NOT EXISTS max_disruptive_duties_per_cycle INTEGER NOT NULL DEFAULT 4;
@@ -0,0 +1,22 @@
+-- off_day_compliance.sql
+CREATE TABLE
+    IF NOT EXISTS off_day_compliance (
+        data_id SERIAL PRIMARY KEY,
+        crew_member_id VARCHAR(50) NOT NULL,
+        period_month VARCHAR(7) NOT NULL, -- "YYYY-MM"
+        period_start TIMESTAMP WITH TIME ZONE NOT NULL, -- Takvim ayı başlangıcı (ana üs yerel saati)
+        period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+        base_location VARCHAR(10),
+        worked_days INTEGER,
+        off_days INTEGER,
+        entitlement_work_days INTEGER, -- Eşleşen off_day_table satırının work_days değeri
+        off_day_entitlement INTEGER,
+        distribution TEXT,
+        required_blocks JSONB, -- Dağılımdan okunan ardışık boş gün blokları
+        off_day_blocks JSONB, -- Dönemdeki ardışık boş gün blokları
+        shortfall_days INTEGER,
+        distribution_met BOOLEAN,
+        violations JSONB, -- FTL ihlalleriyle aynı yapıda (OffDayEntitlementShortfall, OffDayDistributionNotMet)
+        checked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (crew_member_id, period_month)
+    );
@@ -0,0 +1,11 @@
+-- regulation_assignments.sql
+CREATE TABLE
+    IF NOT EXISTS regulation_assignments (
+        data_id SERIAL PRIMARY KEY,
+        subject_type VARCHAR(20) NOT NULL, -- "crew" (person_id) veya "registration" (uçak tescili)
+        subject_value VARCHAR(50) NOT NULL,
+        regulation VARCHAR(20) NOT NULL, -- "SHT-FTL", "EASA-ORO.FTL", "FAA-117"
+        note TEXT,
+        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (subject_type, subject_value)
+    );
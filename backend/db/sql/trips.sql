@@ -32,7 +32,7 @@ CREATE TABLE
         WITH
             TIME ZONE, -- Dinlenme bitişi, NULL olabilir
             calculated_rest_period_duration_min INTEGER, -- Dinlenme süresi, NULL olabilir
-            ftl_violations JSONB, -- []string tipini JSONB olarak saklamak için, NULL olabilir
+            ftl_violations JSONB, -- []FTLViolation (rule_code, severity, window_start/end, measured_value, limit, unit, contributing_trip_ids), NULL olabilir
             last_calculated_at TIMESTAMP
         WITH
             TIME ZONE DEFAULT CURRENT_TIMESTAMP,
@@ -46,4 +46,67 @@ CREATE TABLE
 
 -- FTL hesaplamaları için crew_member_id ve zaman bazında hızlı arama için indeksler
 -- Bu indeks, bir ekip üyesinin geçmiş görevlerini hızlıca çekmek için kritik olacaktır.
-CREATE INDEX IF NOT EXISTS idx_trips_crew_member_id_departure_time ON trips (crew_member_id, first_leg_departure_time);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_trips_crew_member_id_departure_time ON trips (crew_member_id, first_leg_departure_time);
+
+-- İhlalleri üreten kural seti versiyonu
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS rule_set_version INTEGER NOT NULL DEFAULT 0;
+
+-- Aklimatizasyon durumu ve UGS tablosunda kullanılan referans zaman dilimi
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS acclimatisation_state VARCHAR(50);
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS acclimatisation_reference_tz VARCHAR(64);
+
+-- Uzatılmış ekip bilgisi
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS flight_crew_complement INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS rest_facility_class INTEGER NOT NULL DEFAULT 0;
+
+-- Bölünmüş görev (split duty) değerlendirmesi
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS split_duty JSONB;
+
+-- Yedek/rezerv görev bilgisi ve kümülatif pencerelere sayılan görev süresi
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS standby_type VARCHAR(50);
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS standby_duration_min INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS standby_called_out BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS standby_fdp_reduction_min INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS counted_duty_min INTEGER NOT NULL DEFAULT 0;
+
+-- Düzensiz görev işaretleri (ana üs yerel saatine göre)
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS early_start BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS late_finish BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS night_duty BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS encroaches_wocl BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Kaptan takdiriyle karşılanan süre
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS commander_discretion_min INTEGER NOT NULL DEFAULT 0;
+
+-- Uygulanan azami UGS limiti (tablo satırı, düzeltmeler ve son limit)
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS applied_fdp_limit JSONB;
+
+-- Dinlenme yeri ve uygulanan minimum dinlenme
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS rest_location VARCHAR(32);
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS min_rest_required_min INTEGER NOT NULL DEFAULT 0;
+
+-- Saat dilimi geçişli rotasyon sonrası dinlenme
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS max_time_zone_diff_min INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS time_away_from_base_min INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS required_recovery_nights INTEGER NOT NULL DEFAULT 0;
+
+-- Biyomatematik yorgunluk skoru
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS fatigue_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS fatigue_peak_at TIMESTAMP WITH TIME ZONE;
+
+-- Blok süresine dayalı uçuş süresi (DH hariç FLT sektörleri)
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS block_time_min INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS sector_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS sectors JSONB;
+
+-- Uygulanan mevzuat çerçevesi
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS regulation VARCHAR(20);
+
+-- Denetim izi: eşleşen brief/debrief kuralları ve kümülatif pencere toplamları
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS brief_rule_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS debrief_rule_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS cumulative_windows JSONB;
+
+-- Brief/debrief kuralı değiştikten sonra yeniden hesaplanması gereken tripler
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS recalc_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS recalc_flagged_at TIMESTAMP WITH TIME ZONE;
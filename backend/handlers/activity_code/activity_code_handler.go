@@ -36,6 +36,8 @@ func ImportActivityCodeData(c *fiber.Ctx) error { // Fonksiyon adı güncellendi
 	var activityCodes []models.ActivityCode
 	recordCount := 0
 	lineNum := 0 // Başlık satırından sonraki satırları takip etmek için
+	// Başlıkta 4. sütun (yedek tipi) varsa boş değerler de dahil olmak üzere kayıtlı standby_type'ın yerine geçer
+	hasStandbyColumn := false
 
 	// Dosya uzantısına göre okuma stratejisi belirle
 	fileExtension := strings.ToLower(filepath.Ext(fileHeader.Filename))
@@ -59,6 +61,7 @@ func ImportActivityCodeData(c *fiber.Ctx) error { // Fonksiyon adı güncellendi
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("CSV başlık satırı okunamadı: %v", err)})
 		}
 		log.Printf("📌 CSV Header: %s\n", strings.Join(header, ","))
+		hasStandbyColumn = len(header) > 3
 
 		for {
 			lineNum++
@@ -86,6 +89,14 @@ func ImportActivityCodeData(c *fiber.Ctx) error { // Fonksiyon adı güncellendi
 				ActivityGroupCode:       strings.TrimSpace(record[1]),
 				ActivityCodeExplanation: strings.TrimSpace(record[2]),
 			}
+			// Opsiyonel 4. sütun: yedek/rezerv tipi (airport_standby, home_standby, reserve)
+			if len(record) > 3 {
+				activityCode.StandbyType = strings.ToLower(strings.TrimSpace(record[3]))
+				if !models.IsValidStandbyType(activityCode.StandbyType) {
+					log.Printf("⚠️ Satır %d: Geçersiz yedek tipi '%s', boş bırakıldı.\n", lineNum+1, activityCode.StandbyType)
+					activityCode.StandbyType = ""
+				}
+			}
 			activityCodes = append(activityCodes, activityCode)
 			recordCount++
 		}
@@ -123,6 +134,7 @@ func ImportActivityCodeData(c *fiber.Ctx) error { // Fonksiyon adı güncellendi
 		// Başlık satırını atla (rows[0])
 		header := rows[0]
 		log.Printf("📌 XLSX Header: %s\n", strings.Join(header, ","))
+		hasStandbyColumn = len(header) > 3
 
 		for i, row := range rows {
 			if i == 0 { // Başlık satırını atla
@@ -146,6 +158,14 @@ func ImportActivityCodeData(c *fiber.Ctx) error { // Fonksiyon adı güncellendi
 				ActivityGroupCode:       strings.TrimSpace(row[1]),
 				ActivityCodeExplanation: strings.TrimSpace(row[2]),
 			}
+			// Opsiyonel 4. sütun: yedek/rezerv tipi (airport_standby, home_standby, reserve)
+			if len(row) > 3 {
+				activityCode.StandbyType = strings.ToLower(strings.TrimSpace(row[3]))
+				if !models.IsValidStandbyType(activityCode.StandbyType) {
+					log.Printf("⚠️ Satır %d: Geçersiz yedek tipi '%s', boş bırakıldı.\n", lineNum, activityCode.StandbyType)
+					activityCode.StandbyType = ""
+				}
+			}
 			activityCodes = append(activityCodes, activityCode)
 			recordCount++
 		}
@@ -176,17 +196,26 @@ func ImportActivityCodeData(c *fiber.Ctx) error { // Fonksiyon adı güncellendi
 		log.Println("✅ Mevcut aktivite kodları başarıyla temizlendi.")
 	}
 
-	_, err = db.DB.NewInsert().
+	query := db.DB.NewInsert().
 		Model(&activityCodes).
 		On("CONFLICT (activity_code) DO UPDATE").
 		Set("activity_group_code = EXCLUDED.activity_group_code").
-		Set("activity_code_explanation = EXCLUDED.activity_code_explanation").
-		Exec(context.Background())
+		Set("activity_code_explanation = EXCLUDED.activity_code_explanation")
+	if hasStandbyColumn {
+		query = query.Set("standby_type = EXCLUDED.standby_type")
+	}
+	_, err = query.Exec(context.Background())
 	if err != nil {
 		log.Printf("❌ Veritabanına ekleme hatası: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Veritabanına ekleme hatası: %v", err)})
 	}
 
 	log.Printf("✅ %d adet activity_code kaydı başarıyla eklendi/güncellendi.\n", recordCount)
+
+	// Yedek/rezerv kod tanımlarını FTL hesaplamaları için yenile
+	if err := db.LoadStandbyActivityCodes(context.Background()); err != nil {
+		log.Printf("⚠️ Yedek aktivite kodları yenilenemedi: %v", err)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"success": recordCount, "failed": 0, "message": fmt.Sprintf("%d kayıt başarıyla eklendi/güncellendi.", recordCount)})
 }
@@ -11,11 +11,13 @@ import (
 	// time paketi eklendi
 	"mini_CMS_Desktop_App/db"
 	"mini_CMS_Desktop_App/handlers/ftl" // ftl handler'ı için
+	"mini_CMS_Desktop_App/middleware"
 	"mini_CMS_Desktop_App/models"
 	"mini_CMS_Desktop_App/repositories"
 	"mini_CMS_Desktop_App/services"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/xuri/excelize/v2" // XLSX okuma kütüphanesi
 )
 
@@ -25,6 +27,8 @@ type ActualImportXLSXHandler struct {
 	ftlCalc    *services.FTLCalculator
 	tripRepo   *repositories.TripRepository
 	ftlHandler *ftl.FTLHandler
+
+	recalcRunner *services.FTLRecalcJobRunner
 }
 
 // NewActualImportXLSXHandler, handler'ın yeni bir örneğini oluşturur
@@ -33,12 +37,14 @@ func NewActualImportXLSXHandler(
 	ftlCalc *services.FTLCalculator,
 	tripRepo *repositories.TripRepository,
 	ftlHandler *ftl.FTLHandler,
+	recalcRunner *services.FTLRecalcJobRunner,
 ) *ActualImportXLSXHandler {
 	return &ActualImportXLSXHandler{
-		actualRepo: actualRepo,
-		ftlCalc:    ftlCalc,
-		tripRepo:   tripRepo,
-		ftlHandler: ftlHandler,
+		actualRepo:   actualRepo,
+		ftlCalc:      ftlCalc,
+		tripRepo:     tripRepo,
+		ftlHandler:   ftlHandler,
+		recalcRunner: recalcRunner,
 	}
 }
 
@@ -277,32 +283,29 @@ func (h *ActualImportXLSXHandler) ImportActualXLSX(c *fiber.Ctx) error {
 
 	log.Println("✅ COPY FROM başarılı!")
 
-	// --- FTL Hesaplamalarını Tetikle --- (Yorum satırı olarak kalacak)
-	/*
-		var affectedCrewIDs []string
-		err = db.DB.NewSelect().
-			Model((*models.Actual)(nil)).
-			ColumnExpr("DISTINCT person_id").
-			Where("period_month = ?", periodMonth).
-			Scan(c.Context(), &affectedCrewIDs)
-		if err != nil {
-			log.Printf("❌ Etkilenen ekip üyeleri çekilirken hata: %v", err)
-		}
-
-		log.Printf("ℹ️  FTL hesaplaması tetiklenecek ekip üyeleri: %v", affectedCrewIDs)
+	// --- FTL Hesaplamalarını Tetikle ---
+	// Dönemdeki ekip üyeleri sınırlı worker havuzuyla arka planda yeniden hesaplanır (recalculate=false ile atlanır).
+	if c.Query("recalculate") == "false" {
+		return c.JSON(fiber.Map{"success": 1, "failed": 0, "message": "XLSX import başarılı."})
+	}
 
-		for _, crewID := range affectedCrewIDs {
-			go func(capturedCrewID string) {
-				log.Printf("Bilgi: Ekip %s için FTL hesaplaması arka planda tetikleniyor...", capturedCrewID)
-				err := h.ftlCalc.RecalculateCrewSchedule(capturedCrewID)
-				if err != nil {
-					log.Printf("Hata: Ekip %s için FTL hesaplaması başarısız: %v", capturedCrewID, err)
-				} else {
-					log.Printf("✅ Ekip %s için FTL hesaplaması tamamlandı.", capturedCrewID)
-				}
-			}(crewID)
+	affectedCrewIDs, err := h.actualRepo.GetPersonIDsByPeriodMonth(c.Context(), periodMonth)
+	if err != nil {
+		log.Printf("❌ Etkilenen ekip üyeleri çekilirken hata: %v", err)
+		return c.JSON(fiber.Map{"success": 1, "failed": 0, "message": "XLSX import başarılı, FTL yeniden hesaplaması başlatılamadı."})
+	}
+	if len(affectedCrewIDs) > 0 {
+		job := &models.FTLRecalcJob{JobID: uuid.New().String(), Scope: models.RecalcScopePeriod, ScopeValue: periodMonth}
+		if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+			job.CreatedByUserID = userID
 		}
-	*/
+		if err := h.recalcRunner.Start(job, affectedCrewIDs); err != nil {
+			log.Printf("❌ Import sonrası FTL yeniden hesaplama işi başlatılamadı: %v", err)
+		} else {
+			log.Printf("ℹ️  %d ekip üyesi için FTL yeniden hesaplama işi %s başlatıldı.", len(affectedCrewIDs), job.JobID)
+			return c.JSON(fiber.Map{"success": 1, "failed": 0, "message": "XLSX import başarılı.", "recalc_job_id": job.JobID})
+		}
+	}
 	// --- FTL Hesaplamalarını Tetikleme kısmı SONU ---
 
 	return c.JSON(fiber.Map{"success": 1, "failed": 0, "message": "XLSX import başarılı."})
@@ -0,0 +1,270 @@
+package aircraft_type
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mini_CMS_Desktop_App/db"
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Import dosyasındaki sütun sırası: cms_type, body_class, icao_type, is_cargo, rest_facility_class, description.
+// İlk iki sütun zorunludur; diğerleri boş bırakılabilir.
+const (
+	aircraftTypeRequiredColumnCount = 2
+	aircraftTypeColumnCount         = 6
+)
+
+// AircraftTypeHandler, CMS uçak tipi tanımlarının import, CRUD ve tanımsız tip raporu isteklerini yönetir.
+// Her değişiklikten sonra sınıflandırmada kullanılan önbellek (db.LoadAircraftTypes) yenilenir.
+type AircraftTypeHandler struct {
+	repo       *repositories.AircraftTypeRepository
+	actualRepo *repositories.ActualRepository
+}
+
+func NewAircraftTypeHandler(repo *repositories.AircraftTypeRepository, actualRepo *repositories.ActualRepository) *AircraftTypeHandler {
+	return &AircraftTypeHandler{repo: repo, actualRepo: actualRepo}
+}
+
+// parseBool, string'i boolean'a dönüştürür.
+func parseBool(s string) (bool, error) {
+	switch strings.TrimSpace(strings.ToLower(s)) {
+	case "true", "1", "evet", "yes", "y", "x":
+		return true, nil
+	case "false", "0", "hayir", "hayır", "no", "n", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("bilinmeyen boolean değeri: '%s'", s)
+	}
+}
+
+// validateAircraftType, kaydı normalize eder ve alanlarını doğrular.
+func validateAircraftType(t *models.AircraftType) error {
+	t.CmsType = strings.ToUpper(strings.TrimSpace(t.CmsType))
+	t.ICAOType = strings.ToUpper(strings.TrimSpace(t.ICAOType))
+	t.Description = strings.TrimSpace(t.Description)
+	if t.CmsType == "" {
+		return fmt.Errorf("cms_type boş olamaz")
+	}
+	bodyClass := models.NormalizeBodyClass(t.BodyClass)
+	if bodyClass == "" {
+		return fmt.Errorf("body_class '%s' tanınmadı (%s veya %s olmalı)", t.BodyClass, models.AircraftBodyNarrow, models.AircraftBodyWide)
+	}
+	t.BodyClass = bodyClass
+	if !models.IsValidRestFacilityClass(t.RestFacilityClass) {
+		return fmt.Errorf("rest_facility_class 0 (yok), 1, 2 veya 3 olmalı")
+	}
+	return nil
+}
+
+// parseAircraftTypeRecord, CSV/XLSX satırını models.AircraftType'a dönüştürür.
+func parseAircraftTypeRecord(record []string) (models.AircraftType, error) {
+	for len(record) < aircraftTypeColumnCount {
+		record = append(record, "")
+	}
+	t := models.AircraftType{
+		CmsType:     record[0],
+		BodyClass:   record[1],
+		ICAOType:    record[2],
+		Description: record[5],
+	}
+	var err error
+	if t.IsCargo, err = parseBool(record[3]); err != nil {
+		return t, fmt.Errorf("'is_cargo' dönüşüm hatası: %w", err)
+	}
+	if v := strings.TrimSpace(record[4]); v != "" {
+		if t.RestFacilityClass, err = strconv.Atoi(v); err != nil {
+			return t, fmt.Errorf("'rest_facility_class' dönüşüm hatası: %w", err)
+		}
+	}
+	return t, validateAircraftType(&t)
+}
+
+// reloadCache, sınıflandırma önbelleğini tablodan yeniler.
+func reloadCache(ctx context.Context) {
+	if err := db.LoadAircraftTypes(ctx); err != nil {
+		log.Printf("❌ Uçak tipi önbelleği yenilenemedi: %v", err)
+	}
+}
+
+// ImportAircraftTypeData, aircraft_types tablosuna CSV veya XLSX verisi aktarır. Kayıtlar CMS tipine göre
+// eklenir veya güncellenir; reset=true ile önce mevcut tablo temizlenir.
+func (h *AircraftTypeHandler) ImportAircraftTypeData(c *fiber.Ctx) error {
+	log.Println("🔍 ImportAircraftTypeData çağrıldı")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("❌ Dosya alınamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Dosya alınamadı: %v", err)})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("❌ Dosya açılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Dosya açılamadı: %v", err)})
+	}
+	defer file.Close()
+
+	// Başlık dahil tüm satırları oku
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("❌ CSV satırı okuma hatası (Satır %d): %v", len(rows)+1, err)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("CSV okunamadı: %v", err)})
+			}
+			rows = append(rows, record)
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(file)
+		if err != nil {
+			log.Printf("❌ Excel dosyası açılamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel dosyası açılamadı: %v", err)})
+		}
+		sheetList := f.GetSheetList()
+		if len(sheetList) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Excel dosyasında hiç sayfa bulunamadı."})
+		}
+		if rows, err = f.GetRows(sheetList[0]); err != nil {
+			log.Printf("❌ Excel sayfasından satırlar okunamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel sayfasından veri okunamadı: %v", err)})
+		}
+	default:
+		log.Printf("❌ Desteklenmeyen dosya uzantısı: %s", fileHeader.Filename)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Desteklenmeyen dosya tipi. Lütfen .csv veya .xlsx dosyası yükleyin."})
+	}
+
+	if len(rows) < 2 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosya boş veya hiç veri satırı içermiyor."})
+	}
+	if len(rows[0]) < aircraftTypeRequiredColumnCount {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Başlık satırı yetersiz sütun içeriyor: en az %d bekleniyor", aircraftTypeRequiredColumnCount)})
+	}
+	log.Printf("📌 Header: %s", strings.Join(rows[0], ","))
+
+	// Aynı CMS tipi birden fazla satırda varsa son satır geçerlidir
+	byType := map[string]models.AircraftType{}
+	var order []string
+	failedCount := 0
+	for i, row := range rows[1:] {
+		lineNum := i + 2
+		if strings.Join(row, "") == "" {
+			continue
+		}
+		t, err := parseAircraftTypeRecord(row)
+		if err != nil {
+			log.Printf("⚠️ Satır %d atlandı: %v", lineNum, err)
+			failedCount++
+			continue
+		}
+		if _, seen := byType[t.CmsType]; !seen {
+			order = append(order, t.CmsType)
+		}
+		byType[t.CmsType] = t
+	}
+	types := make([]models.AircraftType, 0, len(order))
+	for _, cmsType := range order {
+		types = append(types, byType[cmsType])
+	}
+	if len(types) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosyada geçerli uçak tipi satırı bulunamadı.", "failed": failedCount})
+	}
+
+	if c.QueryBool("reset", false) {
+		log.Println("🚀 'reset=true' parametresi algılandı, mevcut uçak tipi tablosu temizleniyor...")
+		if err := h.repo.DeleteAllAircraftTypes(c.Context()); err != nil {
+			log.Printf("❌ Mevcut uçak tipi tablosu temizlenirken hata oluştu: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Mevcut veriler temizlenirken hata oluştu: %v", err)})
+		}
+	}
+	if err := h.repo.UpsertAircraftTypes(c.Context(), types); err != nil {
+		log.Printf("❌ Veritabanına ekleme hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"success": 0, "failed": failedCount, "error": fmt.Sprintf("Veritabanına ekleme hatası: %v", err)})
+	}
+	reloadCache(c.Context())
+
+	log.Printf("✅ %d uçak tipi kaydı eklendi/güncellendi. %d kayıt atlandı.", len(types), failedCount)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"success": len(types), "failed": failedCount, "message": fmt.Sprintf("%d kayıt başarıyla eklendi/güncellendi. %d kayıt atlandı.", len(types), failedCount)})
+}
+
+// ListAircraftTypes: Tüm uçak tiplerini döndürür. body_class verilirse yalnızca o gövde sınıfındakiler.
+func (h *AircraftTypeHandler) ListAircraftTypes(c *fiber.Ctx) error {
+	bodyClass := ""
+	if v := c.Query("body_class"); v != "" {
+		if bodyClass = models.NormalizeBodyClass(v); bodyClass == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body_class tanınmadı"})
+		}
+	}
+	types, err := h.repo.ListAircraftTypes(c.Context(), bodyClass)
+	if err != nil {
+		log.Printf("Hata: Uçak tipleri listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Uçak tipleri listelenemedi", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": types, "totalCount": len(types)})
+}
+
+// UpsertAircraftType: Bir CMS tipinin tanımını ekler veya günceller.
+func (h *AircraftTypeHandler) UpsertAircraftType(c *fiber.Ctx) error {
+	var t models.AircraftType
+	if err := c.BodyParser(&t); err != nil {
+		log.Printf("Hata: UpsertAircraftType isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+	if err := validateAircraftType(&t); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	types := []models.AircraftType{t}
+	if err := h.repo.UpsertAircraftTypes(c.Context(), types); err != nil {
+		log.Printf("Hata: Uçak tipi kaydedilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Uçak tipi kaydedilemedi", "details": err.Error()})
+	}
+	reloadCache(c.Context())
+
+	log.Printf("✅ %s tipi %s olarak kaydedildi (dinlenme tesisi sınıfı %d).", t.CmsType, t.BodyClass, t.RestFacilityClass)
+	return c.Status(fiber.StatusOK).JSON(types[0])
+}
+
+// DeleteAircraftType: Bir CMS tipinin tanımını siler (tip artık tanımsız olarak değerlendirilir).
+func (h *AircraftTypeHandler) DeleteAircraftType(c *fiber.Ctx) error {
+	cmsType := strings.ToUpper(strings.TrimSpace(c.Params("cms_type")))
+	deleted, err := h.repo.DeleteAircraftType(c.Context(), cmsType)
+	if err != nil {
+		log.Printf("Hata: Uçak tipi silinirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Uçak tipi silinemedi", "details": err.Error()})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Uçak tipi tanımı bulunamadı"})
+	}
+	reloadCache(c.Context())
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListUnmappedCmsTypes: actuals'ta geçen ancak aircraft_types tablosunda tanımlı olmayan CMS tiplerini
+// (bu tipler brief/debrief kuralı seçiminde "BİLİNMİYOR" kabul edilir) kullanım sayılarıyla döndürür.
+// period_month verilirse yalnızca o dönemin aktiviteleri taranır.
+func (h *AircraftTypeHandler) ListUnmappedCmsTypes(c *fiber.Ctx) error {
+	rows, err := h.actualRepo.GetUnmappedCmsTypes(c.Context(), c.Query("period_month"))
+	if err != nil {
+		log.Printf("Hata: Tanımsız CMS tipleri çekilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Tanımsız CMS tipleri çekilemedi", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": rows, "totalCount": len(rows)})
+}
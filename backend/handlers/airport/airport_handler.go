@@ -0,0 +1,208 @@
+package airport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Import dosyasındaki sütun sırası: iata_code, icao_code, name, city, country, time_zone, latitude, longitude, is_base.
+// İlk altı sütun zorunludur; koordinatlar ve is_base boş bırakılabilir.
+const (
+	airportRequiredColumnCount = 6
+	airportColumnCount         = 9
+)
+
+// AirportHandler, meydan referans verisinin import, listeleme, sorgu ve yerel saat isteklerini yönetir.
+type AirportHandler struct {
+	repo           *repositories.AirportRepository
+	airportService *services.AirportService
+}
+
+func NewAirportHandler(repo *repositories.AirportRepository, airportService *services.AirportService) *AirportHandler {
+	return &AirportHandler{repo: repo, airportService: airportService}
+}
+
+// parseBool, string'i boolean'a dönüştürür.
+func parseBool(s string) (bool, error) {
+	switch strings.TrimSpace(strings.ToLower(s)) {
+	case "true", "1", "evet", "yes", "y", "x":
+		return true, nil
+	case "false", "0", "hayir", "hayır", "no", "n", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("bilinmeyen boolean değeri: '%s'", s)
+	}
+}
+
+// parseCoordinate, enlem/boylam değerini (ondalık ayırıcı nokta veya virgül) okur ve aralığını kontrol eder.
+func parseCoordinate(s string, limit float64) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if v < -limit || v > limit {
+		return 0, fmt.Errorf("değer ±%.0f aralığında olmalı: %v", limit, v)
+	}
+	return v, nil
+}
+
+// parseAirportRecord, CSV/XLSX satırını models.Airport'a dönüştürür.
+func parseAirportRecord(record []string) (models.Airport, error) {
+	for len(record) < airportColumnCount {
+		record = append(record, "")
+	}
+	a := models.Airport{
+		IATACode: strings.ToUpper(strings.TrimSpace(record[0])),
+		ICAOCode: strings.ToUpper(strings.TrimSpace(record[1])),
+		Name:     strings.TrimSpace(record[2]),
+		City:     strings.TrimSpace(record[3]),
+		Country:  strings.ToUpper(strings.TrimSpace(record[4])),
+		TimeZone: strings.TrimSpace(record[5]),
+	}
+	if len(a.IATACode) != 3 {
+		return a, fmt.Errorf("'iata_code' 3 karakter olmalı: '%s'", a.IATACode)
+	}
+	if a.ICAOCode != "" && len(a.ICAOCode) != 4 {
+		return a, fmt.Errorf("'icao_code' 4 karakter olmalı: '%s'", a.ICAOCode)
+	}
+	if _, err := time.LoadLocation(a.TimeZone); a.TimeZone == "" || err != nil {
+		return a, fmt.Errorf("'time_zone' geçerli bir IANA zaman dilimi değil: '%s'", a.TimeZone)
+	}
+	var err error
+	if a.Latitude, err = parseCoordinate(record[6], 90); err != nil {
+		return a, fmt.Errorf("'latitude' dönüşüm hatası: %w", err)
+	}
+	if a.Longitude, err = parseCoordinate(record[7], 180); err != nil {
+		return a, fmt.Errorf("'longitude' dönüşüm hatası: %w", err)
+	}
+	if a.IsBase, err = parseBool(record[8]); err != nil {
+		return a, fmt.Errorf("'is_base' dönüşüm hatası: %w", err)
+	}
+	return a, nil
+}
+
+// ImportAirportData, airports tablosuna CSV veya XLSX verisi aktarır. Kayıtlar IATA koduna göre eklenir
+// veya güncellenir; reset=true ile önce mevcut tablo temizlenir. Import sonrası bellekteki meydan kaydı yenilenir.
+func (h *AirportHandler) ImportAirportData(c *fiber.Ctx) error {
+	log.Println("🔍 ImportAirportData çağrıldı")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("❌ Dosya alınamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Dosya alınamadı: %v", err)})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("❌ Dosya açılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Dosya açılamadı: %v", err)})
+	}
+	defer file.Close()
+
+	// Başlık dahil tüm satırları oku
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("❌ CSV satırı okuma hatası (Satır %d): %v", len(rows)+1, err)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("CSV okunamadı: %v", err)})
+			}
+			rows = append(rows, record)
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(file)
+		if err != nil {
+			log.Printf("❌ Excel dosyası açılamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel dosyası açılamadı: %v", err)})
+		}
+		sheetList := f.GetSheetList()
+		if len(sheetList) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Excel dosyasında hiç sayfa bulunamadı."})
+		}
+		if rows, err = f.GetRows(sheetList[0]); err != nil {
+			log.Printf("❌ Excel sayfasından satırlar okunamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel sayfasından veri okunamadı: %v", err)})
+		}
+	default:
+		log.Printf("❌ Desteklenmeyen dosya uzantısı: %s", fileHeader.Filename)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Desteklenmeyen dosya tipi. Lütfen .csv veya .xlsx dosyası yükleyin."})
+	}
+
+	if len(rows) < 2 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosya boş veya hiç veri satırı içermiyor."})
+	}
+	if len(rows[0]) < airportRequiredColumnCount {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Başlık satırı yetersiz sütun içeriyor: en az %d bekleniyor", airportRequiredColumnCount)})
+	}
+	log.Printf("📌 Header: %s", strings.Join(rows[0], ","))
+
+	// Aynı IATA kodu birden fazla satırda varsa son satır geçerlidir
+	byCode := map[string]models.Airport{}
+	var order []string
+	failedCount := 0
+	for i, row := range rows[1:] {
+		lineNum := i + 2
+		if strings.Join(row, "") == "" {
+			continue
+		}
+		airport, err := parseAirportRecord(row)
+		if err != nil {
+			log.Printf("⚠️ Satır %d atlandı: %v", lineNum, err)
+			failedCount++
+			continue
+		}
+		if _, seen := byCode[airport.IATACode]; !seen {
+			order = append(order, airport.IATACode)
+		}
+		byCode[airport.IATACode] = airport
+	}
+	airports := make([]models.Airport, 0, len(order))
+	for _, code := range order {
+		airports = append(airports, byCode[code])
+	}
+	if len(airports) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosyada geçerli meydan satırı bulunamadı.", "failed": failedCount})
+	}
+
+	if c.QueryBool("reset", false) {
+		log.Println("🚀 'reset=true' parametresi algılandı, mevcut meydan tablosu temizleniyor...")
+		if err := h.repo.DeleteAllAirports(c.Context()); err != nil {
+			log.Printf("❌ Mevcut meydan tablosu temizlenirken hata oluştu: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Mevcut veriler temizlenirken hata oluştu: %v", err)})
+		}
+	}
+	if err := h.repo.UpsertAirports(c.Context(), airports); err != nil {
+		log.Printf("❌ Veritabanına ekleme hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"success": 0, "failed": failedCount, "error": fmt.Sprintf("Veritabanına ekleme hatası: %v", err)})
+	}
+	if err := h.airportService.Reload(c.Context()); err != nil {
+		log.Printf("❌ Meydan kaydı yenilenemedi: %v", err)
+	}
+
+	log.Printf("✅ %d meydan kaydı eklendi/güncellendi. %d kayıt atlandı.", len(airports), failedCount)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"success": len(airports), "failed": failedCount, "message": fmt.Sprintf("%d kayıt başarıyla eklendi/güncellendi. %d kayıt atlandı.", len(airports), failedCount)})
+}
@@ -0,0 +1,53 @@
+package airport
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListAirports: Tüm meydanları IATA koduna göre sıralı döndürür.
+func (h *AirportHandler) ListAirports(c *fiber.Ctx) error {
+	airports, err := h.repo.ListAirports(c.Context())
+	if err != nil {
+		log.Printf("❌ Meydanlar listelenemedi: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Meydanlar listelenemedi", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": airports, "totalCount": len(airports)})
+}
+
+// QueryAirports: Arama ve filtre destekli meydan sorgusu.
+// Sorgu parametreleri: search, country, base_only (true/false), sortBy, sortOrder.
+func (h *AirportHandler) QueryAirports(c *fiber.Ctx) error {
+	airports, err := h.repo.QueryAirports(c.Context(), c.Query("search"), c.Query("country"), c.QueryBool("base_only", false), c.Query("sortBy", "iata_code"), c.Query("sortOrder", "asc"))
+	if err != nil {
+		log.Printf("❌ Meydan sorgusu başarısız: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Meydanlar sorgulanamadı", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": airports, "totalCount": len(airports)})
+}
+
+// LookupAirport: IATA veya ICAO koduyla meydanı ve verilen andaki (at, RFC3339; verilmezse şimdi) yerel saatini döndürür.
+func (h *AirportHandler) LookupAirport(c *fiber.Ctx) error {
+	at := time.Now()
+	if v := c.Query("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz zaman (RFC3339 bekleniyor)", "details": err.Error()})
+		}
+		at = t
+	}
+
+	airport, ok := h.airportService.Lookup(c.Params("code"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Meydan bulunamadı"})
+	}
+	localTime, _ := h.airportService.LocalTime(airport.IATACode, at)
+	offsetMin, _ := h.airportService.UTCOffsetMin(airport.IATACode, at)
+	return c.JSON(fiber.Map{
+		"airport":        airport,
+		"local_time":     localTime.Format("2006-01-02T15:04:05-07:00"),
+		"utc_offset_min": offsetMin,
+	})
+}
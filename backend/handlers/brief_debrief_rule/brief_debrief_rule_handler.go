@@ -0,0 +1,285 @@
+package brief_debrief_rule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kabul edilen geçerlilik tarihi biçimleri (dışa aktarım "2006-01-02" kullanır)
+var ruleDateLayouts = []string{"2006-01-02", "02.01.2006", "02/01/2006", time.RFC3339}
+
+// Brief/debrief süreleri için kabul edilen üst sınır (dakika)
+const maxBriefDebriefMin = 600
+
+// BriefDebriefRuleHandler, brief/debrief kurallarının listeleme, CRUD, import, export ve kapsam analizi isteklerini yönetir.
+// Her değişiklikten sonra kuralın eski ve yeni versiyonundan etkilenebilecek tripler yeniden hesaplama için işaretlenir.
+type BriefDebriefRuleHandler struct {
+	repo             *repositories.BriefDebriefRuleRepository
+	tripRepo         *repositories.TripRepository
+	briefDebriefCalc *services.BriefDebriefCalculator
+}
+
+func NewBriefDebriefRuleHandler(repo *repositories.BriefDebriefRuleRepository, tripRepo *repositories.TripRepository, briefDebriefCalc *services.BriefDebriefCalculator) *BriefDebriefRuleHandler {
+	return &BriefDebriefRuleHandler{repo: repo, tripRepo: tripRepo, briefDebriefCalc: briefDebriefCalc}
+}
+
+// BriefDebriefRuleRequest: Kural ekleme/güncelleme isteğinin yapısı. Tarihler "YYYY-MM-DD" biçimindedir; boş
+// bırakılan uç sınırsız kabul edilir.
+type BriefDebriefRuleRequest struct {
+	ScenarioType       string `json:"scenario_type"`
+	AircraftType       string `json:"aircraft_type"`
+	CrewType           string `json:"crew_type"`
+	DutyStartAirport   string `json:"duty_start_airport"`
+	BriefDurationMin   int    `json:"brief_duration_min"`
+	DebriefDurationMin int    `json:"debrief_duration_min"`
+	Priority           int    `json:"priority"`
+	ValidFrom          string `json:"valid_from"`
+	ValidTo            string `json:"valid_to"`
+}
+
+// parseRuleDate, geçerlilik tarihini okur; boş değer için nil döner.
+func parseRuleDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range ruleDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			return &day, nil
+		}
+	}
+	return nil, fmt.Errorf("geçersiz tarih '%s' (YYYY-MM-DD bekleniyor)", value)
+}
+
+// formatRuleDate, geçerlilik tarihini dışa aktarım için biçimlendirir (sınırsız uç boş döner).
+func formatRuleDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// toRule, isteği doğrulayıp models.BriefDebriefRule'a dönüştürür.
+func (req BriefDebriefRuleRequest) toRule() (models.BriefDebriefRule, error) {
+	rule := models.BriefDebriefRule{
+		ScenarioType:       strings.TrimSpace(req.ScenarioType),
+		AircraftType:       strings.TrimSpace(req.AircraftType),
+		CrewType:           strings.TrimSpace(req.CrewType),
+		DutyStartAirport:   strings.TrimSpace(req.DutyStartAirport),
+		BriefDurationMin:   req.BriefDurationMin,
+		DebriefDurationMin: req.DebriefDurationMin,
+		Priority:           req.Priority,
+	}
+	if rule.ScenarioType == "" || rule.AircraftType == "" || rule.CrewType == "" || rule.DutyStartAirport == "" {
+		return rule, fmt.Errorf("scenario_type, aircraft_type, crew_type ve duty_start_airport boş olamaz (joker değer için \"Hepsi\" kullanın)")
+	}
+	if rule.BriefDurationMin < 0 || rule.BriefDurationMin > maxBriefDebriefMin || rule.DebriefDurationMin < 0 || rule.DebriefDurationMin > maxBriefDebriefMin {
+		return rule, fmt.Errorf("brief/debrief süreleri 0-%d dakika aralığında olmalı", maxBriefDebriefMin)
+	}
+	var err error
+	if rule.ValidFrom, err = parseRuleDate(req.ValidFrom); err != nil {
+		return rule, fmt.Errorf("valid_from: %w", err)
+	}
+	if rule.ValidTo, err = parseRuleDate(req.ValidTo); err != nil {
+		return rule, fmt.Errorf("valid_to: %w", err)
+	}
+	if rule.ValidFrom != nil && rule.ValidTo != nil && rule.ValidTo.Before(*rule.ValidFrom) {
+		return rule, fmt.Errorf("valid_to, valid_from'dan önce olamaz")
+	}
+	return rule, nil
+}
+
+// flagTrips, verilen kural versiyonlarından etkilenebilecek tripleri yeniden hesaplama için işaretler.
+// İşaretleme hatası kural değişikliğini geri almaz; çağıran hatayı flagTripsErrorResponse ile döndürür.
+func (h *BriefDebriefRuleHandler) flagTrips(ctx context.Context, rules ...models.BriefDebriefRule) (int, error) {
+	flagged, err := h.tripRepo.FlagTripsForBriefDebriefRules(ctx, rules)
+	if err != nil {
+		log.Printf("❌ Kural değişikliğinden etkilenen tripler işaretlenemedi: %v", err)
+		return flagged, err
+	}
+	if flagged > 0 {
+		log.Printf("📌 Brief/debrief kural değişikliği: %d trip yeniden hesaplama için işaretlendi.", flagged)
+	}
+	return flagged, nil
+}
+
+// flagTripsErrorResponse, kural değişikliği kaydedildiği halde triplerin işaretlenemediğini 500 olarak bildirir.
+func flagTripsErrorResponse(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Kural değişikliği kaydedildi ancak etkilenen tripler yeniden hesaplama için işaretlenemedi",
+		"details": err.Error(),
+	})
+}
+
+// conflictResponse, geçerlilik aralığı çakışan kural için 409 yanıtı döndürür.
+func conflictResponse(c *fiber.Ctx, other *models.BriefDebriefRule) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":    "Aynı senaryo, uçak tipi, ekip tipi ve meydan için geçerlilik aralığı çakışan bir kural var",
+		"conflict": other,
+	})
+}
+
+// ListRules: Tüm brief/debrief kurallarını eşleştirme sırasıyla (öncelik yüksekten düşüğe) döndürür.
+// on (YYYY-MM-DD) verilirse yalnızca o tarihte yürürlükte olan versiyonlar döner.
+func (h *BriefDebriefRuleHandler) ListRules(c *fiber.Ctx) error {
+	on, err := parseRuleDate(c.Query("on"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz on parametresi", "details": err.Error()})
+	}
+	rules, err := h.repo.GetAllRules(c.Context())
+	if err != nil {
+		log.Printf("Hata: Brief/debrief kuralları listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralları listelenemedi", "details": err.Error()})
+	}
+	if on != nil {
+		inForce := rules[:0]
+		for _, rule := range rules {
+			if rule.InForceOn(*on) {
+				inForce = append(inForce, rule)
+			}
+		}
+		rules = inForce
+	}
+	return c.JSON(fiber.Map{"data": rules, "totalCount": len(rules)})
+}
+
+// CreateRule: Yeni bir kural veya mevcut bir kuralın yeni versiyonunu ekler. Aynı anahtara sahip versiyonların
+// geçerlilik aralıkları çakışamaz; yeni versiyondan önce eskisinin valid_to değeri kapatılmalıdır.
+func (h *BriefDebriefRuleHandler) CreateRule(c *fiber.Ctx) error {
+	var req BriefDebriefRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("Hata: CreateRule isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+	rule, err := req.toRule()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	other, err := h.repo.FindOverlappingRule(c.Context(), &rule)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İç sunucu hatası (çakışma kontrolü)", "details": err.Error()})
+	}
+	if other != nil {
+		return conflictResponse(c, other)
+	}
+	if err := h.repo.CreateRule(c.Context(), &rule); err != nil {
+		log.Printf("Hata: Brief/debrief kuralı eklenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralı eklenemedi", "details": err.Error()})
+	}
+
+	log.Printf("✅ Brief/debrief kuralı eklendi (ID %d).", rule.DataID)
+	flagged, err := h.flagTrips(c.Context(), rule)
+	if err != nil {
+		return flagTripsErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": rule, "flaggedTrips": flagged})
+}
+
+// UpdateRule: Kuralı günceller; kuralın eski ve yeni halinden etkilenebilecek tripler işaretlenir.
+func (h *BriefDebriefRuleHandler) UpdateRule(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kural ID"})
+	}
+	var req BriefDebriefRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("Hata: UpdateRule isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+	rule, err := req.toRule()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	rule.DataID = id
+
+	previous, err := h.repo.GetRuleByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralı çekilemedi", "details": err.Error()})
+	}
+	if previous == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Brief/debrief kuralı bulunamadı"})
+	}
+	other, err := h.repo.FindOverlappingRule(c.Context(), &rule)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İç sunucu hatası (çakışma kontrolü)", "details": err.Error()})
+	}
+	if other != nil {
+		return conflictResponse(c, other)
+	}
+	updated, err := h.repo.UpdateRule(c.Context(), &rule)
+	if err != nil {
+		log.Printf("Hata: Brief/debrief kuralı güncellenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralı güncellenemedi", "details": err.Error()})
+	}
+	if !updated {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Brief/debrief kuralı bulunamadı"})
+	}
+
+	log.Printf("✅ Brief/debrief kuralı güncellendi (ID %d).", rule.DataID)
+	flagged, err := h.flagTrips(c.Context(), *previous, rule)
+	if err != nil {
+		return flagTripsErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": rule, "flaggedTrips": flagged})
+}
+
+// DeleteRule: Kuralı siler; kuralla hesaplanmış olabilecek tripler işaretlenir.
+func (h *BriefDebriefRuleHandler) DeleteRule(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kural ID"})
+	}
+	previous, err := h.repo.GetRuleByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralı çekilemedi", "details": err.Error()})
+	}
+	if previous == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Brief/debrief kuralı bulunamadı"})
+	}
+	if _, err := h.repo.DeleteRule(c.Context(), id); err != nil {
+		log.Printf("Hata: Brief/debrief kuralı silinirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralı silinemedi", "details": err.Error()})
+	}
+
+	log.Printf("✅ Brief/debrief kuralı silindi (ID %d).", id)
+	flagged, err := h.flagTrips(c.Context(), *previous)
+	if err != nil {
+		return flagTripsErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"flaggedTrips": flagged})
+}
+
+// AnalyseRules: Kural tablosunun kapsam analizini döndürür: hiçbir kuralın eşleşmediği (varsayılan 60/30 süreye düşen)
+// ekip tipi × görev tipi × gövde sınıfı × meydan kombinasyonları, hiçbir kombinasyonda kazanamayan kurallar ve
+// aynı öncelikte birden fazla kuralın eşleştiği durumlar. on (YYYY-MM-DD, varsayılan bugün) tarihinde yürürlükte
+// olan versiyonlar analiz edilir.
+func (h *BriefDebriefRuleHandler) AnalyseRules(c *fiber.Ctx) error {
+	on, err := parseRuleDate(c.Query("on"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz on parametresi", "details": err.Error()})
+	}
+	date := time.Now()
+	if on != nil {
+		date = *on
+	}
+	analysis, err := h.briefDebriefCalc.AnalyseRules(c.Context(), date)
+	if err != nil {
+		log.Printf("Hata: Brief/debrief kural analizi yapılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kural analizi yapılamadı", "details": err.Error()})
+	}
+	log.Printf("🔍 Brief/debrief kural analizi (%s): %d kombinasyon, %d eşleşmesiz, %d kazanamayan kural, %d eşit öncelik grubu.",
+		analysis.AnalysedOn.Format("2006-01-02"), analysis.CombinationCount, len(analysis.Unmatched), len(analysis.NeverWinning), len(analysis.Ties))
+	return c.Status(fiber.StatusOK).JSON(analysis)
+}
@@ -0,0 +1,235 @@
+package brief_debrief_rule
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Import/export dosyasındaki sütun sırası. data_id dolu olan satırlar o kuralı günceller, boş olanlar yeni kural
+// olarak eklenir; valid_from ve valid_to boş bırakılabilir.
+var ruleFileHeader = []string{"data_id", "scenario_type", "aircraft_type", "crew_type", "duty_start_airport", "brief_duration_min", "debrief_duration_min", "priority", "valid_from", "valid_to"}
+
+// Zorunlu sütun sayısı (data_id'den priority'ye kadar)
+const ruleRequiredColumnCount = 8
+
+// parseRuleRecord, CSV/XLSX satırını models.BriefDebriefRule'a dönüştürür.
+func parseRuleRecord(record []string) (models.BriefDebriefRule, error) {
+	for len(record) < len(ruleFileHeader) {
+		record = append(record, "")
+	}
+	atoi := func(name, value string) (int, error) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return 0, nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("'%s' dönüşüm hatası: %w", name, err)
+		}
+		return v, nil
+	}
+
+	var req BriefDebriefRuleRequest
+	dataID, err := atoi("data_id", record[0])
+	if err != nil {
+		return models.BriefDebriefRule{}, err
+	}
+	req.ScenarioType, req.AircraftType, req.CrewType, req.DutyStartAirport = record[1], record[2], record[3], record[4]
+	if req.BriefDurationMin, err = atoi("brief_duration_min", record[5]); err != nil {
+		return models.BriefDebriefRule{}, err
+	}
+	if req.DebriefDurationMin, err = atoi("debrief_duration_min", record[6]); err != nil {
+		return models.BriefDebriefRule{}, err
+	}
+	if req.Priority, err = atoi("priority", record[7]); err != nil {
+		return models.BriefDebriefRule{}, err
+	}
+	req.ValidFrom, req.ValidTo = record[8], record[9]
+
+	rule, err := req.toRule()
+	rule.DataID = dataID
+	return rule, err
+}
+
+// ImportRuleData, brief_debrief_rules tablosuna CSV veya XLSX verisi aktarır. Tüm dosya tek işlemde uygulanır:
+// geçersiz satır, bulunamayan data_id veya çakışan geçerlilik aralığı varsa hiçbir değişiklik yapılmaz.
+// reset=true ile önce mevcut kurallar silinir. Eski ve yeni kurallardan etkilenebilecek tripler işaretlenir.
+func (h *BriefDebriefRuleHandler) ImportRuleData(c *fiber.Ctx) error {
+	log.Println("🔍 ImportRuleData çağrıldı")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("❌ Dosya alınamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Dosya alınamadı: %v", err)})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("❌ Dosya açılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Dosya açılamadı: %v", err)})
+	}
+	defer file.Close()
+
+	// Başlık dahil tüm satırları oku
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("❌ CSV satırı okuma hatası (Satır %d): %v", len(rows)+1, err)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("CSV okunamadı: %v", err)})
+			}
+			rows = append(rows, record)
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(file)
+		if err != nil {
+			log.Printf("❌ Excel dosyası açılamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel dosyası açılamadı: %v", err)})
+		}
+		sheetList := f.GetSheetList()
+		if len(sheetList) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Excel dosyasında hiç sayfa bulunamadı."})
+		}
+		if rows, err = f.GetRows(sheetList[0]); err != nil {
+			log.Printf("❌ Excel sayfasından satırlar okunamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel sayfasından veri okunamadı: %v", err)})
+		}
+	default:
+		log.Printf("❌ Desteklenmeyen dosya uzantısı: %s", fileHeader.Filename)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Desteklenmeyen dosya tipi. Lütfen .csv veya .xlsx dosyası yükleyin."})
+	}
+
+	if len(rows) < 2 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosya boş veya hiç veri satırı içermiyor."})
+	}
+	if len(rows[0]) < ruleRequiredColumnCount {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Başlık satırı yetersiz sütun içeriyor: en az %d bekleniyor", ruleRequiredColumnCount)})
+	}
+	log.Printf("📌 Header: %s", strings.Join(rows[0], ","))
+
+	// Kurallar birlikte uygulandığından geçersiz satırlar atlanmaz, tüm hatalar raporlanır
+	var rules []models.BriefDebriefRule
+	var rowErrors []string
+	for i, row := range rows[1:] {
+		lineNum := i + 2
+		if strings.Join(row, "") == "" {
+			continue
+		}
+		rule, err := parseRuleRecord(row)
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("Satır %d: %v", lineNum, err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if len(rowErrors) > 0 {
+		log.Printf("❌ Brief/debrief kural dosyasında %d geçersiz satır var, import yapılmadı.", len(rowErrors))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosyada geçersiz satırlar var, hiçbir kural aktarılmadı.", "details": rowErrors})
+	}
+	if len(rules) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosyada geçerli kural satırı bulunamadı."})
+	}
+
+	// Etkilenen tripleri işaretlemek için değişecek kuralların önceki hallerini al
+	reset := c.QueryBool("reset", false)
+	previous, err := h.repo.GetAllRules(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Mevcut kurallar çekilemedi", "details": err.Error()})
+	}
+	if !reset {
+		updatedIDs := map[int]bool{}
+		for _, rule := range rules {
+			updatedIDs[rule.DataID] = true
+		}
+		changed := previous[:0]
+		for _, rule := range previous {
+			if updatedIDs[rule.DataID] {
+				changed = append(changed, rule)
+			}
+		}
+		previous = changed
+	}
+
+	if reset {
+		log.Println("🚀 'reset=true' parametresi algılandı, mevcut brief/debrief kuralları değiştirilecek...")
+	}
+	if err := h.repo.ImportRules(c.Context(), rules, reset); err != nil {
+		log.Printf("❌ Brief/debrief kuralları aktarılamadı: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Brief/debrief kuralları aktarılamadı, hiçbir değişiklik yapılmadı.", "details": err.Error()})
+	}
+
+	flagged, err := h.flagTrips(c.Context(), append(previous, rules...)...)
+	if err != nil {
+		return flagTripsErrorResponse(c, err)
+	}
+	log.Printf("✅ %d brief/debrief kuralı eklendi/güncellendi.", len(rules))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"success": len(rules), "flaggedTrips": flagged, "message": fmt.Sprintf("%d kural başarıyla eklendi/güncellendi.", len(rules))})
+}
+
+// ExportRules: Tüm kuralları import ile aynı sütun düzeninde CSV (varsayılan) veya XLSX (format=xlsx) olarak indirir.
+func (h *BriefDebriefRuleHandler) ExportRules(c *fiber.Ctx) error {
+	rules, err := h.repo.GetAllRules(c.Context())
+	if err != nil {
+		log.Printf("Hata: Brief/debrief kuralları dışa aktarılırken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brief/debrief kuralları çekilemedi", "details": err.Error()})
+	}
+	records := make([][]string, 0, len(rules)+1)
+	records = append(records, ruleFileHeader)
+	for _, rule := range rules {
+		records = append(records, []string{
+			strconv.Itoa(rule.DataID), rule.ScenarioType, rule.AircraftType, rule.CrewType, rule.DutyStartAirport,
+			strconv.Itoa(rule.BriefDurationMin), strconv.Itoa(rule.DebriefDurationMin), strconv.Itoa(rule.Priority),
+			formatRuleDate(rule.ValidFrom), formatRuleDate(rule.ValidTo),
+		})
+	}
+	fileName := fmt.Sprintf("brief_debrief_kurallari_%s", time.Now().Format("20060102"))
+
+	if c.Query("format") == "xlsx" {
+		f := excelize.NewFile()
+		defer f.Close()
+		sheet := f.GetSheetName(0)
+		for i, record := range records {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			row := make([]interface{}, len(record))
+			for j, v := range record {
+				row[j] = v
+			}
+			if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Excel dosyası oluşturulamadı", "details": err.Error()})
+			}
+		}
+		var buf bytes.Buffer
+		if err := f.Write(&buf); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Excel dosyası oluşturulamadı", "details": err.Error()})
+		}
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		c.Attachment(fileName + ".xlsx")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.WriteAll(records)
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment(fileName + ".csv")
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
@@ -0,0 +1,262 @@
+package crew_position
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mini_CMS_Desktop_App/db"
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Import dosyasındaki sütun sırası: position_code, crew_type, rank_order, crew_need_column, description.
+// İlk iki sütun zorunludur; diğerleri boş bırakılabilir.
+const (
+	crewPositionRequiredColumnCount = 2
+	crewPositionColumnCount         = 5
+)
+
+// CrewPositionHandler, uçuş pozisyonu tanımlarının import, CRUD ve tanımsız pozisyon raporu isteklerini yönetir.
+// Her değişiklikten sonra sınıflandırmada kullanılan önbellek (db.LoadCrewPositions) yenilenir.
+type CrewPositionHandler struct {
+	repo       *repositories.CrewPositionRepository
+	actualRepo *repositories.ActualRepository
+}
+
+func NewCrewPositionHandler(repo *repositories.CrewPositionRepository, actualRepo *repositories.ActualRepository) *CrewPositionHandler {
+	return &CrewPositionHandler{repo: repo, actualRepo: actualRepo}
+}
+
+// validateCrewPosition, kaydı normalize eder ve alanlarını doğrular.
+func validateCrewPosition(p *models.CrewPosition) error {
+	p.PositionCode = strings.ToUpper(strings.TrimSpace(p.PositionCode))
+	p.CrewNeedColumn = strings.ToUpper(strings.TrimSpace(p.CrewNeedColumn))
+	p.Description = strings.TrimSpace(p.Description)
+	if p.PositionCode == "" {
+		return fmt.Errorf("position_code boş olamaz")
+	}
+	if p.PositionCode == models.DeadheadFlightPosition {
+		return fmt.Errorf("%s konumlandırma kodudur, pozisyon olarak tanımlanamaz", models.DeadheadFlightPosition)
+	}
+	crewType := models.NormalizeCrewType(p.CrewType)
+	if crewType == "" {
+		return fmt.Errorf("crew_type '%s' tanınmadı (%s veya %s olmalı)", p.CrewType, models.CrewTypeFlight, models.CrewTypeCabin)
+	}
+	p.CrewType = crewType
+	if p.RankOrder < 0 {
+		return fmt.Errorf("rank_order negatif olamaz")
+	}
+	if !models.IsValidCrewNeedColumn(p.CrewNeedColumn) {
+		return fmt.Errorf("crew_need_column '%s' tanınmadı (%s olmalı)", p.CrewNeedColumn, strings.Join(models.CrewNeedColumns(), "/"))
+	}
+	return nil
+}
+
+// parseCrewPositionRecord, CSV/XLSX satırını models.CrewPosition'a dönüştürür.
+func parseCrewPositionRecord(record []string) (models.CrewPosition, error) {
+	for len(record) < crewPositionColumnCount {
+		record = append(record, "")
+	}
+	p := models.CrewPosition{
+		PositionCode:   record[0],
+		CrewType:       record[1],
+		CrewNeedColumn: record[3],
+		Description:    record[4],
+	}
+	if v := strings.TrimSpace(record[2]); v != "" {
+		rank, err := strconv.Atoi(v)
+		if err != nil {
+			return p, fmt.Errorf("'rank_order' dönüşüm hatası: %w", err)
+		}
+		p.RankOrder = rank
+	}
+	return p, validateCrewPosition(&p)
+}
+
+// reloadCache, sınıflandırma önbelleğini tablodan yeniler.
+func reloadCache(ctx context.Context) {
+	if err := db.LoadCrewPositions(ctx); err != nil {
+		log.Printf("❌ Ekip pozisyonu önbelleği yenilenemedi: %v", err)
+	}
+}
+
+// ImportCrewPositionData, crew_positions tablosuna CSV veya XLSX verisi aktarır. Kayıtlar pozisyon koduna göre
+// eklenir veya güncellenir; reset=true ile önce mevcut tablo temizlenir.
+func (h *CrewPositionHandler) ImportCrewPositionData(c *fiber.Ctx) error {
+	log.Println("🔍 ImportCrewPositionData çağrıldı")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("❌ Dosya alınamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Dosya alınamadı: %v", err)})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("❌ Dosya açılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Dosya açılamadı: %v", err)})
+	}
+	defer file.Close()
+
+	// Başlık dahil tüm satırları oku
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("❌ CSV satırı okuma hatası (Satır %d): %v", len(rows)+1, err)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("CSV okunamadı: %v", err)})
+			}
+			rows = append(rows, record)
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(file)
+		if err != nil {
+			log.Printf("❌ Excel dosyası açılamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel dosyası açılamadı: %v", err)})
+		}
+		sheetList := f.GetSheetList()
+		if len(sheetList) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Excel dosyasında hiç sayfa bulunamadı."})
+		}
+		if rows, err = f.GetRows(sheetList[0]); err != nil {
+			log.Printf("❌ Excel sayfasından satırlar okunamadı: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Excel sayfasından veri okunamadı: %v", err)})
+		}
+	default:
+		log.Printf("❌ Desteklenmeyen dosya uzantısı: %s", fileHeader.Filename)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Desteklenmeyen dosya tipi. Lütfen .csv veya .xlsx dosyası yükleyin."})
+	}
+
+	if len(rows) < 2 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosya boş veya hiç veri satırı içermiyor."})
+	}
+	if len(rows[0]) < crewPositionRequiredColumnCount {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Başlık satırı yetersiz sütun içeriyor: en az %d bekleniyor", crewPositionRequiredColumnCount)})
+	}
+	log.Printf("📌 Header: %s", strings.Join(rows[0], ","))
+
+	// Aynı pozisyon kodu birden fazla satırda varsa son satır geçerlidir
+	byCode := map[string]models.CrewPosition{}
+	var order []string
+	failedCount := 0
+	for i, row := range rows[1:] {
+		lineNum := i + 2
+		if strings.Join(row, "") == "" {
+			continue
+		}
+		p, err := parseCrewPositionRecord(row)
+		if err != nil {
+			log.Printf("⚠️ Satır %d atlandı: %v", lineNum, err)
+			failedCount++
+			continue
+		}
+		if _, seen := byCode[p.PositionCode]; !seen {
+			order = append(order, p.PositionCode)
+		}
+		byCode[p.PositionCode] = p
+	}
+	positions := make([]models.CrewPosition, 0, len(order))
+	for _, code := range order {
+		positions = append(positions, byCode[code])
+	}
+	if len(positions) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dosyada geçerli pozisyon satırı bulunamadı.", "failed": failedCount})
+	}
+
+	if c.QueryBool("reset", false) {
+		log.Println("🚀 'reset=true' parametresi algılandı, mevcut pozisyon tablosu temizleniyor...")
+		if err := h.repo.DeleteAllCrewPositions(c.Context()); err != nil {
+			log.Printf("❌ Mevcut pozisyon tablosu temizlenirken hata oluştu: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Mevcut veriler temizlenirken hata oluştu: %v", err)})
+		}
+	}
+	if err := h.repo.UpsertCrewPositions(c.Context(), positions); err != nil {
+		log.Printf("❌ Veritabanına ekleme hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"success": 0, "failed": failedCount, "error": fmt.Sprintf("Veritabanına ekleme hatası: %v", err)})
+	}
+	reloadCache(c.Context())
+
+	log.Printf("✅ %d pozisyon kaydı eklendi/güncellendi. %d kayıt atlandı.", len(positions), failedCount)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"success": len(positions), "failed": failedCount, "message": fmt.Sprintf("%d kayıt başarıyla eklendi/güncellendi. %d kayıt atlandı.", len(positions), failedCount)})
+}
+
+// ListCrewPositions: Tüm pozisyonları kıdem sırasıyla döndürür. crew_type verilirse yalnızca o ekip tipindekiler.
+func (h *CrewPositionHandler) ListCrewPositions(c *fiber.Ctx) error {
+	crewType := ""
+	if v := c.Query("crew_type"); v != "" {
+		if crewType = models.NormalizeCrewType(v); crewType == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "crew_type tanınmadı"})
+		}
+	}
+	positions, err := h.repo.ListCrewPositions(c.Context(), crewType)
+	if err != nil {
+		log.Printf("Hata: Ekip pozisyonları listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ekip pozisyonları listelenemedi", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": positions, "totalCount": len(positions)})
+}
+
+// UpsertCrewPosition: Bir pozisyon kodunun tanımını ekler veya günceller.
+func (h *CrewPositionHandler) UpsertCrewPosition(c *fiber.Ctx) error {
+	var p models.CrewPosition
+	if err := c.BodyParser(&p); err != nil {
+		log.Printf("Hata: UpsertCrewPosition isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+	if err := validateCrewPosition(&p); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	positions := []models.CrewPosition{p}
+	if err := h.repo.UpsertCrewPositions(c.Context(), positions); err != nil {
+		log.Printf("Hata: Ekip pozisyonu kaydedilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ekip pozisyonu kaydedilemedi", "details": err.Error()})
+	}
+	reloadCache(c.Context())
+
+	log.Printf("✅ %s pozisyonu %s olarak kaydedildi (kıdem %d, ihtiyaç sütunu '%s').", p.PositionCode, p.CrewType, p.RankOrder, p.CrewNeedColumn)
+	return c.Status(fiber.StatusOK).JSON(positions[0])
+}
+
+// DeleteCrewPosition: Bir pozisyon kodunun tanımını siler (kod artık tanımsız olarak raporlanır).
+func (h *CrewPositionHandler) DeleteCrewPosition(c *fiber.Ctx) error {
+	code := strings.ToUpper(strings.TrimSpace(c.Params("position_code")))
+	deleted, err := h.repo.DeleteCrewPosition(c.Context(), code)
+	if err != nil {
+		log.Printf("Hata: Ekip pozisyonu silinirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ekip pozisyonu silinemedi", "details": err.Error()})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Ekip pozisyonu tanımı bulunamadı"})
+	}
+	reloadCache(c.Context())
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListUnmappedFlightPositions: actuals'ta geçen ancak crew_positions tablosunda tanımlı olmayan uçuş pozisyonlarını
+// (bu pozisyonların ekip tipi "BİLİNMİYOR" kabul edilir ve açık trip ihtiyacında sayılmaz) kullanım sayılarıyla
+// döndürür. period_month verilirse yalnızca o dönemin aktiviteleri taranır.
+func (h *CrewPositionHandler) ListUnmappedFlightPositions(c *fiber.Ctx) error {
+	rows, err := h.actualRepo.GetUnmappedFlightPositions(c.Context(), c.Query("period_month"))
+	if err != nil {
+		log.Printf("Hata: Tanımsız uçuş pozisyonları çekilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Tanımsız uçuş pozisyonları çekilemedi", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": rows, "totalCount": len(rows)})
+}
@@ -0,0 +1,177 @@
+package ftl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mini_CMS_Desktop_App/middleware"
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommanderDiscretionHandler, kaptan takdiri kayıtlarının girilmesi, listelenmesi ve otoriteye
+// sunulacak kullanım raporunun üretilmesi isteklerini yönetir.
+type CommanderDiscretionHandler struct {
+	discretionRepo *repositories.CommanderDiscretionRepository
+	tripRepo       *repositories.TripRepository
+	ftlCalc        *services.FTLCalculator
+}
+
+func NewCommanderDiscretionHandler(discretionRepo *repositories.CommanderDiscretionRepository, tripRepo *repositories.TripRepository, ftlCalc *services.FTLCalculator) *CommanderDiscretionHandler {
+	return &CommanderDiscretionHandler{discretionRepo: discretionRepo, tripRepo: tripRepo, ftlCalc: ftlCalc}
+}
+
+// RecordDiscretion: Bir trip için kaptan takdiri kaydeder ve ekip üyesinin programını yeniden hesaplar.
+func (h *CommanderDiscretionHandler) RecordDiscretion(c *fiber.Ctx) error {
+	var discretion models.CommanderDiscretion
+	if err := c.BodyParser(&discretion); err != nil {
+		log.Printf("Hata: RecordDiscretion isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+
+	discretion.Reason = strings.TrimSpace(discretion.Reason)
+	discretion.CommanderPersonID = strings.TrimSpace(discretion.CommanderPersonID)
+	if discretion.TripID == "" || discretion.Reason == "" || discretion.CommanderPersonID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "trip_id, reason ve commander_person_id boş olamaz"})
+	}
+	if discretion.ExtensionMin <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "extension_min pozitif olmalı"})
+	}
+
+	trip, err := h.tripRepo.GetTripByID(discretion.TripID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İç sunucu hatası (trip çekme)"})
+	}
+	if trip == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Trip bulunamadı"})
+	}
+
+	if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+		discretion.RecordedByUserID = userID
+	}
+	discretion.DataID = 0
+	if err := h.discretionRepo.CreateDiscretion(c.Context(), &discretion); err != nil {
+		log.Printf("Hata: Kaptan takdiri kaydedilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kaptan takdiri kaydedilemedi", "details": err.Error()})
+	}
+	log.Printf("✅ Trip %s için %d dakikalık kaptan takdiri kaydedildi (kaptan: %s).", discretion.TripID, discretion.ExtensionMin, discretion.CommanderPersonID)
+
+	if err := h.ftlCalc.RecalculateCrewSchedule(trip.CrewMemberID); err != nil {
+		log.Printf("Hata: Kaptan takdiri sonrası ekip %s yeniden hesaplanırken sorun: %v", trip.CrewMemberID, err)
+	}
+	if updated, err := h.tripRepo.GetTripByID(discretion.TripID); err == nil && updated != nil {
+		trip = updated
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"discretion": discretion, "trip": trip})
+}
+
+// ListDiscretions: Kaptan takdiri kayıtlarını döndürür (trip_id ile filtrelenebilir).
+func (h *CommanderDiscretionHandler) ListDiscretions(c *fiber.Ctx) error {
+	discretions, err := h.discretionRepo.ListDiscretions(c.Context(), c.Query("trip_id"))
+	if err != nil {
+		log.Printf("Hata: Kaptan takdiri kayıtları listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kaptan takdiri kayıtları listelenemedi", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(discretions)
+}
+
+// discretionGroupKey, trip'in rapordaki gruplama anahtarını döndürür:
+// crew → ekip person_id'si, fleet → ilk uçuş bacağının CMS tipi, route → uçuş bacaklarının meydan sırası.
+func discretionGroupKey(trip models.Trip, groupBy string) string {
+	switch groupBy {
+	case "fleet":
+		for _, act := range trip.Activities {
+			if act.GroupCode == "FLT" && act.PlaneCmsType != "" {
+				return act.PlaneCmsType
+			}
+		}
+		return "BİLİNMİYOR"
+	case "route":
+		var ports []string
+		for _, act := range trip.Activities {
+			if act.GroupCode != "FLT" {
+				continue
+			}
+			if len(ports) == 0 {
+				ports = append(ports, act.DeparturePort)
+			}
+			ports = append(ports, act.ArrivalPort)
+		}
+		if len(ports) == 0 {
+			return "BİLİNMİYOR"
+		}
+		return strings.Join(ports, "-")
+	default:
+		return trip.CrewMemberID
+	}
+}
+
+// DiscretionReport: Dönem içindeki kaptan takdiri kullanımını ekip (crew), filo (fleet) veya rotaya (route) göre toplar.
+// Parametreler: from, to, group_by (varsayılan crew), format=csv ile otoriteye sunulacak CSV dosyası döner.
+func (h *CommanderDiscretionHandler) DiscretionReport(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz tarih formatı (YYYY-MM-DD bekleniyor)"})
+	}
+	groupBy := c.Query("group_by", "crew")
+	if groupBy != "crew" && groupBy != "fleet" && groupBy != "route" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "group_by crew, fleet veya route olmalı"})
+	}
+
+	trips, err := h.tripRepo.GetTripsWithCommanderDiscretion(c.Context(), from, to)
+	if err != nil {
+		log.Printf("Hata: Kaptan takdiri raporu oluşturulurken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kaptan takdiri raporu oluşturulamadı", "details": err.Error()})
+	}
+
+	rows := map[string]*models.DiscretionReportRow{}
+	for _, trip := range trips {
+		key := discretionGroupKey(trip, groupBy)
+		row, ok := rows[key]
+		if !ok {
+			row = &models.DiscretionReportRow{GroupKey: key}
+			rows[key] = row
+		}
+		row.Count++
+		row.TotalExtensionMin += trip.CommanderDiscretionMin
+		if trip.CommanderDiscretionMin > row.MaxExtensionMin {
+			row.MaxExtensionMin = trip.CommanderDiscretionMin
+		}
+	}
+
+	report := make([]models.DiscretionReportRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, *row)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Count != report[j].Count {
+			return report[i].Count > report[j].Count
+		}
+		return report[i].GroupKey < report[j].GroupKey
+	})
+
+	if c.Query("format") != "csv" {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"group_by": groupBy, "from": from, "to": to, "data": report})
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{groupBy, "count", "total_extension_min", "max_extension_min"})
+	for _, row := range report {
+		_ = writer.Write([]string{row.GroupKey, strconv.Itoa(row.Count), strconv.Itoa(row.TotalExtensionMin), strconv.Itoa(row.MaxExtensionMin)})
+	}
+	writer.Flush()
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment(fmt.Sprintf("kaptan_takdiri_%s_%s_%s.csv", groupBy, from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102")))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
@@ -0,0 +1,76 @@
+package ftl
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CrewBaseAirportHandler, minimum dinlenme kuralında ana üs sayılan meydanların tanımlarını yönetir.
+type CrewBaseAirportHandler struct {
+	baseAirportRepo *repositories.CrewBaseAirportRepository
+}
+
+func NewCrewBaseAirportHandler(baseAirportRepo *repositories.CrewBaseAirportRepository) *CrewBaseAirportHandler {
+	return &CrewBaseAirportHandler{baseAirportRepo: baseAirportRepo}
+}
+
+// ListBaseAirports: Tüm meydan → ana üs eşlemelerini döndürür.
+func (h *CrewBaseAirportHandler) ListBaseAirports(c *fiber.Ctx) error {
+	airports, err := h.baseAirportRepo.ListBaseAirports(c.Context())
+	if err != nil {
+		log.Printf("Hata: Ana üs meydanları listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ana üs meydanları listelenemedi", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(airports)
+}
+
+// UpsertBaseAirport: Bir meydanı verilen ana üs grubuna ekler veya grubunu günceller; meydan airports
+// tablosunda ana üs olarak işaretlenir. Meydan airports tablosunda tanımlı değilse 400 döner.
+func (h *CrewBaseAirportHandler) UpsertBaseAirport(c *fiber.Ctx) error {
+	var airport models.CrewBaseAirport
+	if err := c.BodyParser(&airport); err != nil {
+		log.Printf("Hata: UpsertBaseAirport isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+
+	airport.AirportCode = strings.ToUpper(strings.TrimSpace(airport.AirportCode))
+	airport.BaseCode = strings.ToUpper(strings.TrimSpace(airport.BaseCode))
+	if airport.AirportCode == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "airport_code boş olamaz"})
+	}
+	if airport.BaseCode == "" {
+		airport.BaseCode = airport.AirportCode
+	}
+
+	if err := h.baseAirportRepo.UpsertBaseAirport(c.Context(), &airport); err != nil {
+		if errors.Is(err, repositories.ErrBaseAirportNotDefined) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Meydan önce meydan referans verisine eklenmelidir", "details": err.Error()})
+		}
+		log.Printf("Hata: Ana üs meydanı kaydedilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ana üs meydanı kaydedilemedi", "details": err.Error()})
+	}
+
+	log.Printf("✅ %s meydanı %s ana üssüne eşlendi.", airport.AirportCode, airport.BaseCode)
+	return c.Status(fiber.StatusOK).JSON(airport)
+}
+
+// DeleteBaseAirport: Bir meydanın ana üs grup eşlemesini siler. Meydanın ana üs bayrağı (airports.is_base)
+// korunur; meydan kendi kodunu ana üs grubu olarak kullanmaya devam eder.
+func (h *CrewBaseAirportHandler) DeleteBaseAirport(c *fiber.Ctx) error {
+	airportCode := strings.ToUpper(strings.TrimSpace(c.Params("airport_code")))
+	deleted, err := h.baseAirportRepo.DeleteBaseAirport(c.Context(), airportCode)
+	if err != nil {
+		log.Printf("Hata: Ana üs meydanı silinirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ana üs meydanı silinemedi", "details": err.Error()})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Ana üs meydanı tanımı bulunamadı"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
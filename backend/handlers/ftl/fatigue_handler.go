@@ -0,0 +1,59 @@
+package ftl
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FatigueRiskRow, en yüksek yorgunluk riskli tripler listesindeki tek bir satırdır.
+type FatigueRiskRow struct {
+	TripID                    string     `json:"trip_id"`
+	CrewMemberID              string     `json:"crew_member_id"`
+	CalculatedDutyPeriodStart time.Time  `json:"calculated_duty_period_start"`
+	CalculatedDutyPeriodEnd   time.Time  `json:"calculated_duty_period_end"`
+	DutyStartAirport          string     `json:"duty_start_airport"`
+	NightDuty                 bool       `json:"night_duty"`
+	FatigueScore              float64    `json:"fatigue_score"`
+	FatiguePeakAt             *time.Time `json:"fatigue_peak_at,omitempty"`
+}
+
+// ListHighestFatigueTrips: Dönemdeki yorgunluk skoru (KSS eşdeğeri, 1-9) en yüksek tripleri döndürür.
+// Sorgu parametreleri: from, to (YYYY-MM-DD), crew_id, min_score, limit (varsayılan 50).
+func (h *FTLHandler) ListHighestFatigueTrips(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz tarih formatı (YYYY-MM-DD bekleniyor)", "details": err.Error()})
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit pozitif bir sayı olmalı"})
+	}
+	minScore, err := strconv.ParseFloat(c.Query("min_score", "0"), 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "min_score sayı olmalı", "details": err.Error()})
+	}
+
+	trips, err := h.tripRepo.GetHighestFatigueTrips(c.Context(), from, to, c.Query("crew_id"), minScore, limit)
+	if err != nil {
+		log.Printf("Hata: Yorgunluk riski en yüksek tripler çekilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Yorgunluk riski en yüksek tripler çekilemedi", "details": err.Error()})
+	}
+
+	rows := make([]FatigueRiskRow, 0, len(trips))
+	for _, t := range trips {
+		rows = append(rows, FatigueRiskRow{
+			TripID:                    t.TripID,
+			CrewMemberID:              t.CrewMemberID,
+			CalculatedDutyPeriodStart: t.CalculatedDutyPeriodStart,
+			CalculatedDutyPeriodEnd:   t.CalculatedDutyPeriodEnd,
+			DutyStartAirport:          t.DutyStartAirport,
+			NightDuty:                 t.NightDuty,
+			FatigueScore:              t.FatigueScore,
+			FatiguePeakAt:             t.FatiguePeakAt,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(rows)
+}
@@ -0,0 +1,159 @@
+package ftl
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// explainTemplate, FTL denetim izinin yazdırılabilir (tarayıcıdan PDF'e aktarılabilir) HTML görünümüdür.
+var explainTemplate = template.Must(template.New("ftl_explain").Funcs(template.FuncMap{
+	"hm": func(min int) string {
+		sign := ""
+		if min < 0 {
+			sign, min = "-", -min
+		}
+		return fmt.Sprintf("%s%02d:%02d", sign, min/60, min%60)
+	},
+	"ts": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04 MST")
+	},
+	"ruleID": func(id int) string {
+		if id == 0 {
+			return "varsayılan"
+		}
+		return fmt.Sprintf("#%d", id)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="tr">
+<head>
+<meta charset="utf-8">
+<title>FTL Denetim İzi - {{.TripID}}</title>
+<style>
+  @page { size: A4; margin: 15mm; }
+  body { font-family: Arial, Helvetica, sans-serif; font-size: 11px; color: #111; }
+  h1 { font-size: 18px; margin-bottom: 2px; }
+  h2 { font-size: 13px; margin: 16px 0 6px; border-bottom: 1px solid #999; page-break-after: avoid; }
+  table { width: 100%; border-collapse: collapse; page-break-inside: avoid; }
+  th, td { border: 1px solid #ccc; padding: 3px 5px; text-align: left; vertical-align: top; }
+  th { background: #f0f0f0; width: 30%; }
+  .exceeded { color: #b00000; font-weight: bold; }
+  .muted { color: #666; }
+</style>
+</head>
+<body>
+<h1>FTL Denetim İzi</h1>
+<div class="muted">Trip {{.TripID}} · Ekip {{.CrewMemberID}} · Oluşturulma {{ts .GeneratedAt}} · Kaynak {{if eq .Source "recorded"}}kayıtlı hesaplama{{else}}yeniden hesaplama{{end}}</div>
+
+<h2>Mevzuat ve Kural Seti</h2>
+<table>
+<tr><th>Mevzuat çerçevesi</th><td>{{.Regulation}}</td></tr>
+<tr><th>Kural seti</th><td>v{{.RuleSetVersion}} {{.RuleSetName}}</td></tr>
+</table>
+
+<h2>Zaman Dilimleri</h2>
+<table>
+<tr><th>Tercih edilen (görev/dinlenme, kümülatif pencereler)</th><td>{{.TimeZones.PreferredTZ}}</td></tr>
+<tr><th>Ana üs</th><td>{{.TimeZones.CrewBaseAirport}} ({{.TimeZones.BaseTZ}})</td></tr>
+<tr><th>Aklimatizasyon</th><td>{{.TimeZones.AcclimatisationState}} · referans {{.TimeZones.AcclimatisationReferenceTZ}}</td></tr>
+</table>
+
+<h2>Brief / Debrief</h2>
+<table>
+<tr><th></th><th>Kural</th><th>Görev tipi</th><th>Uçak tipi</th><th>Ekip tipi</th><th>Meydan</th><th>Süre</th></tr>
+<tr><td>Brief</td><td>{{ruleID .Brief.RuleID}}</td><td>{{.Brief.DutyType}}</td><td>{{.Brief.AircraftType}}</td><td>{{.Brief.CrewType}}</td><td>{{.Brief.DutyStartAirport}}</td><td>{{hm .Brief.DurationMin}}</td></tr>
+<tr><td>Debrief</td><td>{{ruleID .Debrief.RuleID}}</td><td>{{.Debrief.DutyType}}</td><td>{{.Debrief.AircraftType}}</td><td>{{.Debrief.CrewType}}</td><td>{{.Debrief.DutyStartAirport}}</td><td>{{hm .Debrief.DurationMin}}</td></tr>
+</table>
+
+<h2>Görev Süresi</h2>
+<table>
+<tr><th>Türetme</th><td>{{if eq .DutyPeriod.Derivation "activities"}}İlk aktivitenin görev başlangıcı → son aktivitenin görev bitişi{{else}}İlk bacak kalkışı − brief → son bacak varışı + debrief{{end}}</td></tr>
+<tr><th>İlk bacak kalkışı / son bacak varışı</th><td>{{ts .DutyPeriod.FirstLegDepartureTime}} / {{ts .DutyPeriod.LastLegArrivalTime}}</td></tr>
+<tr><th>Görev başlangıcı / bitişi</th><td>{{ts .DutyPeriod.Start}} / {{ts .DutyPeriod.End}}</td></tr>
+<tr><th>Görev süresi / sayılan görev süresi</th><td>{{hm .DutyPeriod.DurationMin}} / {{hm .DutyPeriod.CountedDutyMin}}</td></tr>
+<tr><th>UGS süresi (görev başlangıcı → son varış)</th><td>{{hm .DutyPeriod.FDPDurationMin}}</td></tr>
+<tr><th>Blok süresi (DH hariç)</th><td>{{hm .DutyPeriod.BlockTimeMin}} · {{.DutyPeriod.SectorCount}} sektör</td></tr>
+</table>
+
+{{with .Rest}}
+<h2>Dinlenme</h2>
+<table>
+<tr><th>Önceki trip</th><td>{{.PrevTripID}}</td></tr>
+<tr><th>Dinlenme</th><td>{{ts .Start}} → {{ts .End}} ({{hm .DurationMin}})</td></tr>
+<tr><th>Yer / gereken minimum</th><td>{{.Location}} / <span class="{{if lt .DurationMin .RequiredMin}}exceeded{{end}}">{{hm .RequiredMin}}</span></td></tr>
+</table>
+{{end}}
+
+{{with .FDPLimit}}
+<h2>Azami UGS Limiti</h2>
+<table>
+<tr><th>Tablo / satır</th><td>{{.Table}} · {{.Row}}</td></tr>
+{{if .Error}}<tr><th>Hata</th><td class="exceeded">{{.Error}}</td></tr>{{end}}
+<tr><th>Tablo limiti</th><td>{{hm .TableLimitMin}}{{if .Augmented}} (uzatılmış ekip){{end}}</td></tr>
+<tr><th>Bölünmüş görev uzatması</th><td>+{{hm .SplitDutyExtensionMin}}</td></tr>
+<tr><th>Yedek görev düşümü</th><td>−{{hm .StandbyReductionMin}}</td></tr>
+<tr><th>Uygulanan limit / gerçekleşen UGS</th><td>{{hm .FinalLimitMin}} / <span class="{{if gt .FDPDurationMin .FinalLimitMin}}exceeded{{end}}">{{hm .FDPDurationMin}}</span></td></tr>
+{{if .CommanderDiscretionMin}}<tr><th>Kaptan takdiri</th><td>{{hm .CommanderDiscretionMin}}</td></tr>{{end}}
+</table>
+{{end}}
+
+<h2>Kümülatif Pencereler</h2>
+<table>
+<tr><th>Kural</th><th>Pencere</th><th>Toplam / limit</th><th>Katkı veren tripler</th></tr>
+{{range .CumulativeWindows}}
+<tr><td>{{.RuleCode}}</td><td>{{ts .WindowStart}} → {{ts .WindowEnd}}</td><td class="{{if .Exceeded}}exceeded{{end}}">{{hm .TotalMin}} / {{hm .LimitMin}}</td><td>{{range $i, $id := .ContributingTripIDs}}{{if $i}}, {{end}}{{$id}}{{end}}</td></tr>
+{{end}}
+</table>
+
+<h2>İhlaller</h2>
+{{if .Violations}}
+<table>
+<tr><th>Kural</th><th>Önem</th><th>Pencere</th><th>Ölçülen / limit</th><th>Tripler</th></tr>
+{{range .Violations}}
+<tr><td>{{.RuleCode}}</td><td>{{.Severity}}</td><td>{{ts .WindowStart}} → {{ts .WindowEnd}}</td><td>{{.MeasuredValue}} / {{.Limit}} {{.Unit}}</td><td>{{range $i, $id := .ContributingTripIDs}}{{if $i}}, {{end}}{{$id}}{{end}}{{if .Details}} · {{.Details}}{{end}}</td></tr>
+{{end}}
+</table>
+{{else}}
+<p>İhlal yok.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// ExplainTrip: Trip'in kayıtlı FTL hesaplamasındaki her değerin kaynağını döndürür (kaydetmez).
+// format=html verilirse yazdırılabilir (PDF'e aktarılabilir) HTML sayfası döner.
+func (h *FTLHandler) ExplainTrip(c *fiber.Ctx) error {
+	explanation, err := h.ftlCalc.ExplainTrip(c.Context(), c.Params("trip_id"))
+	if err != nil {
+		log.Printf("Hata: Trip %s için FTL denetim izi oluşturulamadı: %v", c.Params("trip_id"), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL denetim izi oluşturulamadı", "details": err.Error()})
+	}
+	if explanation == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Trip bulunamadı"})
+	}
+
+	if c.Query("format") == "html" {
+		return renderExplanationHTML(c, explanation)
+	}
+	return c.Status(fiber.StatusOK).JSON(explanation)
+}
+
+func renderExplanationHTML(c *fiber.Ctx, explanation *models.FTLExplanation) error {
+	var buf bytes.Buffer
+	if err := explainTemplate.Execute(&buf, explanation); err != nil {
+		log.Printf("Hata: FTL denetim izi HTML'i oluşturulamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "HTML oluşturulamadı", "details": err.Error()})
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="ftl_denetim_izi_%s.html"`, explanation.TripID))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
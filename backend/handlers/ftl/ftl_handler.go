@@ -16,14 +16,16 @@ import (
 )
 
 type FTLHandler struct {
-	ftlCalc  *services.FTLCalculator
-	tripRepo *repositories.TripRepository
+	ftlCalc    *services.FTLCalculator
+	tripRepo   *repositories.TripRepository
+	offDayRepo *repositories.OffDayComplianceRepository
 }
 
-func NewFTLHandler(ftlCalc *services.FTLCalculator, tripRepo *repositories.TripRepository) *FTLHandler {
+func NewFTLHandler(ftlCalc *services.FTLCalculator, tripRepo *repositories.TripRepository, offDayRepo *repositories.OffDayComplianceRepository) *FTLHandler {
 	return &FTLHandler{
-		ftlCalc:  ftlCalc,
-		tripRepo: tripRepo,
+		ftlCalc:    ftlCalc,
+		tripRepo:   tripRepo,
+		offDayRepo: offDayRepo,
 	}
 }
 
@@ -151,7 +153,7 @@ func (h *FTLHandler) HandleCalculateTripFTL(c *fiber.Ctx) error {
 	if req.CrewType != "" {
 		crewType = req.CrewType
 	} else if len(req.Activities) > 0 {
-		crewType = models.GetCrewTypeFromFlightPosition(req.Activities[0].FlightPosition)
+		crewType = models.GetCrewTypeFromActivities(req.Activities)
 	} else {
 		crewType = "BİLİNMİYOR"
 	}
@@ -220,7 +222,7 @@ func (h *FTLHandler) HandleCalculateTripFTL(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.ftlCalc.CalculateFTLForTrip(trip, allCrewTrips); err != nil {
+	if err := h.ftlCalc.CalculateFTLForTrip(c.Context(), trip, allCrewTrips); err != nil {
 		log.Printf("Hata: Trip %s için FTL hesaplanırken sorun: %v", trip.TripID, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL hesaplanırken hata"})
 	}
@@ -260,15 +262,10 @@ func (h *FTLHandler) HandleRecalculateCrewScheduleFTL(c *fiber.Ctx) error { // <
 		allCrewTrips[i] = &allCrewTripsRaw[i]
 	}
 
-	sort.Slice(allCrewTrips, func(i, j int) bool {
-		return allCrewTrips[i].FirstLegDepartureTime.Before(allCrewTrips[j].FirstLegDepartureTime)
-	})
+	// Program sırasıyla tek geçişte hesaplanır (liste yerinde sıralanır)
+	h.ftlCalc.EvaluateSchedule(c.Context(), allCrewTrips)
 
-	for i := range allCrewTrips {
-		trip := allCrewTrips[i]
-		if err := h.ftlCalc.CalculateFTLForTrip(trip, allCrewTrips); err != nil {
-			log.Printf("Hata: Toplu yeniden hesaplama sırasında trip %s için FTL hesaplanırken sorun: %v", trip.TripID, err)
-		}
+	for _, trip := range allCrewTrips {
 		if err := h.tripRepo.SaveTrip(trip); err != nil {
 			log.Printf("Hata: Toplu yeniden hesaplama sonrası trip %s kaydedilirken sorun: %v", trip.TripID, err)
 		}
@@ -0,0 +1,131 @@
+package ftl
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"mini_CMS_Desktop_App/middleware"
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// FTLRecalcJobHandler, toplu FTL yeniden hesaplama işlerinin başlatılması, izlenmesi ve iptal edilmesi isteklerini yönetir.
+type FTLRecalcJobHandler struct {
+	runner       *services.FTLRecalcJobRunner
+	jobRepo      *repositories.FTLRecalcJobRepository
+	actualRepo   *repositories.ActualRepository
+	crewInfoRepo *repositories.CrewInfoRepository
+	tripRepo     *repositories.TripRepository
+}
+
+func NewFTLRecalcJobHandler(runner *services.FTLRecalcJobRunner, jobRepo *repositories.FTLRecalcJobRepository, actualRepo *repositories.ActualRepository, crewInfoRepo *repositories.CrewInfoRepository, tripRepo *repositories.TripRepository) *FTLRecalcJobHandler {
+	return &FTLRecalcJobHandler{runner: runner, jobRepo: jobRepo, actualRepo: actualRepo, crewInfoRepo: crewInfoRepo, tripRepo: tripRepo}
+}
+
+// StartRecalcJobRequest: Toplu yeniden hesaplama isteğinin yapısı
+type StartRecalcJobRequest struct {
+	Scope      string `json:"scope"`       // "period", "base", "fleet", "flagged"
+	ScopeValue string `json:"scope_value"` // Örn: "2024-05", "IST", "B737" ("flagged" kapsamında kullanılmaz)
+	Workers    int    `json:"workers"`     // 0 = varsayılan
+}
+
+// StartRecalcJob: Kapsamdaki tüm ekip üyeleri için arka planda FTL yeniden hesaplaması başlatır.
+// İlerleme, process_id sorgu parametresi (verilmezse iş için üretilen ID) üzerinden WebSocket ile bildirilir.
+func (h *FTLRecalcJobHandler) StartRecalcJob(c *fiber.Ctx) error {
+	var req StartRecalcJobRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("Hata: StartRecalcJob isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+	req.ScopeValue = strings.TrimSpace(req.ScopeValue)
+	if req.ScopeValue == "" && req.Scope != models.RecalcScopeFlagged {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "scope_value boş olamaz"})
+	}
+
+	var crewIDs []string
+	var err error
+	switch req.Scope {
+	case models.RecalcScopePeriod:
+		crewIDs, err = h.actualRepo.GetPersonIDsByPeriodMonth(c.Context(), req.ScopeValue)
+	case models.RecalcScopeBase:
+		crewIDs, err = h.crewInfoRepo.GetPersonIDsBy(c.Context(), "base_location", req.ScopeValue)
+	case models.RecalcScopeFleet:
+		crewIDs, err = h.crewInfoRepo.GetPersonIDsBy(c.Context(), "base_filo", req.ScopeValue)
+	case models.RecalcScopeFlagged:
+		crewIDs, err = h.tripRepo.GetCrewIDsWithRecalcRequired(c.Context())
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "scope period, base, fleet veya flagged olmalı"})
+	}
+	if err != nil {
+		log.Printf("Hata: Yeniden hesaplanacak ekip üyeleri çekilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ekip üyeleri çekilemedi", "details": err.Error()})
+	}
+	if len(crewIDs) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kapsamda yeniden hesaplanacak ekip üyesi bulunamadı"})
+	}
+
+	processID := c.Query("process_id")
+	if processID == "" {
+		processID = uuid.New().String()
+	}
+	existing, err := h.jobRepo.GetJob(c.Context(), processID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İç sunucu hatası (iş kontrolü)", "details": err.Error()})
+	}
+	if existing != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Bu process_id ile bir iş zaten mevcut"})
+	}
+
+	job := &models.FTLRecalcJob{JobID: processID, Scope: req.Scope, ScopeValue: req.ScopeValue, Workers: req.Workers}
+	if userID, err := middleware.GetUserIDFromContext(c); err == nil {
+		job.CreatedByUserID = userID
+	}
+	if err := h.runner.Start(job, crewIDs); err != nil {
+		log.Printf("Hata: FTL yeniden hesaplama işi başlatılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL yeniden hesaplama işi başlatılamadı", "details": err.Error()})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// ListRecalcJobs: Son toplu yeniden hesaplama işlerini döndürür (limit, varsayılan 50).
+func (h *FTLRecalcJobHandler) ListRecalcJobs(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit pozitif bir sayı olmalı"})
+	}
+	jobs, err := h.jobRepo.ListJobs(c.Context(), limit)
+	if err != nil {
+		log.Printf("Hata: Yeniden hesaplama işleri listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Yeniden hesaplama işleri listelenemedi", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(jobs)
+}
+
+// GetRecalcJob: Verilen işin özetini döndürür.
+func (h *FTLRecalcJobHandler) GetRecalcJob(c *fiber.Ctx) error {
+	job, err := h.jobRepo.GetJob(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Printf("Hata: Yeniden hesaplama işi çekilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Yeniden hesaplama işi çekilemedi", "details": err.Error()})
+	}
+	if job == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Yeniden hesaplama işi bulunamadı"})
+	}
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// CancelRecalcJob: Çalışan işi iptal eder; işlenmekte olan ekip üyeleri bir sonraki tripte durur.
+func (h *FTLRecalcJobHandler) CancelRecalcJob(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if !h.runner.Cancel(jobID) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Çalışan bir yeniden hesaplama işi bulunamadı"})
+	}
+	log.Printf("⚠️ FTL yeniden hesaplama işi %s iptal edildi.", jobID)
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"job_id": jobID, "message": "İptal isteği alındı"})
+}
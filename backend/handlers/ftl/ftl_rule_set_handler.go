@@ -0,0 +1,97 @@
+package ftl
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FTLRuleSetHandler, versiyonlu FTL kural setlerinin listelenmesi, oluşturulması ve aktive edilmesi isteklerini yönetir.
+type FTLRuleSetHandler struct {
+	ruleSetRepo *repositories.FTLRuleSetRepository
+}
+
+func NewFTLRuleSetHandler(ruleSetRepo *repositories.FTLRuleSetRepository) *FTLRuleSetHandler {
+	return &FTLRuleSetHandler{ruleSetRepo: ruleSetRepo}
+}
+
+// ListRuleSets: Tüm kural seti versiyonlarını döndürür.
+func (h *FTLRuleSetHandler) ListRuleSets(c *fiber.Ctx) error {
+	ruleSets, err := h.ruleSetRepo.ListRuleSets(c.Context())
+	if err != nil {
+		log.Printf("Hata: FTL kural setleri listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL kural setleri listelenemedi", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(ruleSets)
+}
+
+// CreateRuleSet: Yeni bir kural seti versiyonu oluşturur (pasif olarak kaydedilir).
+func (h *FTLRuleSetHandler) CreateRuleSet(c *fiber.Ctx) error {
+	var ruleSet models.FTLRuleSet
+	if err := c.BodyParser(&ruleSet); err != nil {
+		log.Printf("Hata: CreateRuleSet isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+
+	if ruleSet.EffectiveFrom.IsZero() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "effective_from boş olamaz"})
+	}
+	if ruleSet.EffectiveTo != nil && !ruleSet.EffectiveTo.After(ruleSet.EffectiveFrom) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "effective_to, effective_from'dan sonra olmalı"})
+	}
+
+	// Düzensiz görev limitleri verilmezse varsayılanlar kullanılır
+	defaults := models.DefaultFTLRuleSet()
+	if ruleSet.MaxConsecutiveNightDuties == 0 {
+		ruleSet.MaxConsecutiveNightDuties = defaults.MaxConsecutiveNightDuties
+	}
+	if ruleSet.MaxDisruptiveDutiesPerCycle == 0 {
+		ruleSet.MaxDisruptiveDutiesPerCycle = defaults.MaxDisruptiveDutiesPerCycle
+	}
+
+	limits := []int{
+		ruleSet.MaxDuty7DaysMin, ruleSet.MaxDuty14DaysMin, ruleSet.MaxDuty28DaysMin, ruleSet.MaxDutyYearMin,
+		ruleSet.MaxFlight28DaysMin, ruleSet.MaxFlight12MonthsMin, ruleSet.MaxFlightYearMin,
+		ruleSet.MinRestHomeBaseMin, ruleSet.MinRestAwayMin,
+		ruleSet.MaxConsecutiveNightDuties, ruleSet.MaxDisruptiveDutiesPerCycle,
+	}
+	for _, limit := range limits {
+		if limit <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Tüm limit ve minimum dinlenme değerleri pozitif olmalı"})
+		}
+	}
+
+	ruleSet.CreatedAt = time.Now()
+	if err := h.ruleSetRepo.CreateRuleSet(c.Context(), &ruleSet); err != nil {
+		log.Printf("Hata: FTL kural seti oluşturulurken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL kural seti oluşturulamadı", "details": err.Error()})
+	}
+
+	log.Printf("✅ FTL kural seti versiyon %d oluşturuldu (%s).", ruleSet.Version, ruleSet.Name)
+	return c.Status(fiber.StatusCreated).JSON(ruleSet)
+}
+
+// ActivateRuleSet: Verilen ID'li kural setini aktive eder.
+func (h *FTLRuleSetHandler) ActivateRuleSet(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kural seti ID'si"})
+	}
+
+	ruleSet, err := h.ruleSetRepo.ActivateRuleSet(c.Context(), id)
+	if err != nil {
+		log.Printf("Hata: FTL kural seti %d aktive edilirken sorun: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL kural seti aktive edilemedi", "details": err.Error()})
+	}
+	if ruleSet == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "FTL kural seti bulunamadı"})
+	}
+
+	log.Printf("✅ FTL kural seti versiyon %d aktive edildi.", ruleSet.Version)
+	return c.Status(fiber.StatusOK).JSON(ruleSet)
+}
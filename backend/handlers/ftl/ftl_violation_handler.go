@@ -0,0 +1,202 @@
+package ftl
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ViolationSummaryRow, ihlal özet raporundaki tek bir gruplama satırıdır.
+type ViolationSummaryRow struct {
+	RuleCode     string  `json:"rule_code"`
+	Severity     string  `json:"severity"`
+	CrewMemberID string  `json:"crew_member_id,omitempty"`
+	Count        int     `json:"count"`
+	TripCount    int     `json:"trip_count"`
+	MaxExcess    float64 `json:"max_excess"` // Limitin ihlal yönünde en çok geçildiği miktar (asgari kurallarda limit - measured_value)
+}
+
+// parseDateRange, "from" ve "to" (YYYY-MM-DD) sorgu parametrelerini okur.
+// Verilmezse içinde bulunulan ayın başı ve sonraki ayın başı kullanılır; "to" günü dahildir.
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return from, to, err
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return from, to, err
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+// loadViolations, sorgu parametrelerine göre trip ihlallerini ve aralıktaki aylara ait boş gün uygunluğu
+// ihlallerini düzleştirilmiş olarak getirir ve filtreler.
+func (h *FTLHandler) loadViolations(c *fiber.Ctx) ([]models.TripViolation, error) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Geçersiz tarih formatı (YYYY-MM-DD bekleniyor)")
+	}
+
+	trips, err := h.tripRepo.GetTripsWithViolations(c.Context(), from, to, c.Query("crew_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	ruleCodeFilter := map[string]bool{}
+	for _, code := range strings.Split(c.Query("rule_code"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			ruleCodeFilter[code] = true
+		}
+	}
+	severityFilter := c.Query("severity")
+	keep := func(v models.FTLViolation) bool {
+		if len(ruleCodeFilter) > 0 && !ruleCodeFilter[v.RuleCode] {
+			return false
+		}
+		return severityFilter == "" || v.Severity == severityFilter
+	}
+
+	violations := []models.TripViolation{}
+	for _, trip := range trips {
+		for _, v := range trip.FTLViolations {
+			if !keep(v) {
+				continue
+			}
+			violations = append(violations, models.TripViolation{
+				TripID:         trip.TripID,
+				CrewMemberID:   trip.CrewMemberID,
+				RuleSetVersion: trip.RuleSetVersion,
+				FTLViolation:   v,
+			})
+		}
+	}
+
+	if h.offDayRepo != nil {
+		periods, err := h.offDayRepo.ListCompliance(c.Context(), from.Format("2006-01"), to.Add(-time.Nanosecond).Format("2006-01"), c.Query("crew_id"), true)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range periods {
+			for _, v := range p.Violations {
+				if !keep(v) {
+					continue
+				}
+				violations = append(violations, models.TripViolation{
+					CrewMemberID: p.CrewMemberID,
+					PeriodMonth:  p.PeriodMonth,
+					FTLViolation: v,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// ListViolations: Dönem içindeki ihlalleri düz liste olarak döndürür.
+// Parametreler: from, to, crew_id, rule_code (virgülle ayrılmış), severity, sort_by, sort_order.
+func (h *FTLHandler) ListViolations(c *fiber.Ctx) error {
+	violations, err := h.loadViolations(c)
+	if err != nil {
+		if fe, ok := err.(*fiber.Error); ok {
+			return c.Status(fe.Code).JSON(fiber.Map{"error": fe.Message})
+		}
+		log.Printf("Hata: FTL ihlalleri listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL ihlalleri listelenemedi", "details": err.Error()})
+	}
+
+	sortBy := c.Query("sort_by", "window_start")
+	desc := c.Query("sort_order", "asc") == "desc"
+	less := func(a, b models.TripViolation) bool {
+		switch sortBy {
+		case "measured_value":
+			return a.MeasuredValue < b.MeasuredValue
+		case "excess":
+			return a.Excess() < b.Excess()
+		case "rule_code":
+			return a.RuleCode < b.RuleCode
+		case "crew_member_id":
+			return a.CrewMemberID < b.CrewMemberID
+		default:
+			return a.WindowStart.Before(b.WindowStart)
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if desc {
+			return less(violations[j], violations[i])
+		}
+		return less(violations[i], violations[j])
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": violations, "totalCount": len(violations)})
+}
+
+// ViolationSummary: İhlalleri kural kodu ve önem derecesine (group_by=crew ile ayrıca ekip üyesine) göre toplar.
+func (h *FTLHandler) ViolationSummary(c *fiber.Ctx) error {
+	violations, err := h.loadViolations(c)
+	if err != nil {
+		if fe, ok := err.(*fiber.Error); ok {
+			return c.Status(fe.Code).JSON(fiber.Map{"error": fe.Message})
+		}
+		log.Printf("Hata: FTL ihlal özeti oluşturulurken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "FTL ihlal özeti oluşturulamadı", "details": err.Error()})
+	}
+
+	byCrew := c.Query("group_by") == "crew"
+	rows := map[string]*ViolationSummaryRow{}
+	tripsSeen := map[string]map[string]bool{}
+	for _, v := range violations {
+		key := v.RuleCode + "|" + v.Severity
+		if byCrew {
+			key += "|" + v.CrewMemberID
+		}
+		row, ok := rows[key]
+		if !ok {
+			row = &ViolationSummaryRow{RuleCode: v.RuleCode, Severity: v.Severity}
+			if byCrew {
+				row.CrewMemberID = v.CrewMemberID
+			}
+			rows[key] = row
+			tripsSeen[key] = map[string]bool{}
+		}
+		row.Count++
+		if v.TripID != "" && !tripsSeen[key][v.TripID] {
+			tripsSeen[key][v.TripID] = true
+			row.TripCount++
+		}
+		if excess := v.Excess(); excess > row.MaxExcess {
+			row.MaxExcess = excess
+		}
+	}
+
+	summary := make([]ViolationSummaryRow, 0, len(rows))
+	for _, row := range rows {
+		summary = append(summary, *row)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		if summary[i].RuleCode != summary[j].RuleCode {
+			return summary[i].RuleCode < summary[j].RuleCode
+		}
+		return summary[i].CrewMemberID < summary[j].CrewMemberID
+	})
+
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
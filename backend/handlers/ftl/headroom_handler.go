@@ -0,0 +1,97 @@
+package ftl
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeadroomHandler, ekip üyelerinin FTL pencerelerinde kalan süre (headroom) sorgularını yönetir.
+type HeadroomHandler struct {
+	ftlCalc      *services.FTLCalculator
+	crewInfoRepo *repositories.CrewInfoRepository
+}
+
+func NewHeadroomHandler(ftlCalc *services.FTLCalculator, crewInfoRepo *repositories.CrewInfoRepository) *HeadroomHandler {
+	return &HeadroomHandler{ftlCalc: ftlCalc, crewInfoRepo: crewInfoRepo}
+}
+
+// parseHeadroomAt, "at" (RFC3339) veya "date" (YYYY-MM-DD, günün başı UTC) sorgu parametresini okur; ikisi de
+// verilmezse şimdiki zaman kullanılır.
+func parseHeadroomAt(c *fiber.Ctx) (time.Time, error) {
+	if v := c.Query("at"); v != "" {
+		return time.Parse(time.RFC3339, v)
+	}
+	if v := c.Query("date"); v != "" {
+		return time.Parse("2006-01-02", v)
+	}
+	return time.Now(), nil
+}
+
+// GetCrewHeadroom: Ekip üyesinin verilen anda 7/14/28 gün, 12 ay ve yıl pencerelerinde kalan görev ve uçuş
+// sürelerini ve son görevinden sonraki en erken yasal raporlama zamanını döndürür.
+func (h *HeadroomHandler) GetCrewHeadroom(c *fiber.Ctx) error {
+	at, err := parseHeadroomAt(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz tarih (date: YYYY-MM-DD, at: RFC3339)", "details": err.Error()})
+	}
+	headroom, err := h.ftlCalc.CrewHeadroom(c.Context(), c.Params("crew_id"), at)
+	if err != nil {
+		log.Printf("Hata: Ekip %s için headroom hesaplanamadı: %v", c.Params("crew_id"), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Headroom hesaplanamadı", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(headroom)
+}
+
+// RankCrewHeadroom: Bir filo veya üsteki tüm ekip üyelerini hedef tarihteki headroom'a göre sıralar.
+// Sorgu parametreleri: scope (base, fleet), scope_value, date/at, sort_by (duty, flight), limit (0 = tümü).
+func (h *HeadroomHandler) RankCrewHeadroom(c *fiber.Ctx) error {
+	at, err := parseHeadroomAt(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz tarih (date: YYYY-MM-DD, at: RFC3339)", "details": err.Error()})
+	}
+	scopeValue := strings.TrimSpace(c.Query("scope_value"))
+	if scopeValue == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "scope_value boş olamaz"})
+	}
+	var column string
+	switch c.Query("scope") {
+	case models.RecalcScopeBase:
+		column = "base_location"
+	case models.RecalcScopeFleet:
+		column = "base_filo"
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "scope base veya fleet olmalı"})
+	}
+	sortBy := c.Query("sort_by", models.HeadroomDuty)
+	if sortBy != models.HeadroomDuty && sortBy != models.HeadroomFlight {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "sort_by duty veya flight olmalı"})
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "0"))
+	if err != nil || limit < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit negatif olmayan bir sayı olmalı"})
+	}
+
+	crewIDs, err := h.crewInfoRepo.GetPersonIDsBy(c.Context(), column, scopeValue)
+	if err != nil {
+		log.Printf("Hata: Headroom sıralaması için ekip üyeleri çekilemedi: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ekip üyeleri çekilemedi", "details": err.Error()})
+	}
+
+	ranked, err := h.ftlCalc.RankCrewHeadroom(c.Context(), crewIDs, at, sortBy)
+	if err != nil {
+		log.Printf("Hata: Headroom sıralaması hesaplanamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Headroom sıralaması hesaplanamadı", "details": err.Error()})
+	}
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return c.Status(fiber.StatusOK).JSON(ranked)
+}
@@ -0,0 +1,101 @@
+package ftl
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OffDayComplianceHandler, ekip üyelerinin aylık boş gün hak edişi ve dağılım kontrolü isteklerini yönetir.
+type OffDayComplianceHandler struct {
+	checker        *services.OffDayComplianceChecker
+	complianceRepo *repositories.OffDayComplianceRepository
+	actualRepo     *repositories.ActualRepository
+	crewInfoRepo   *repositories.CrewInfoRepository
+}
+
+func NewOffDayComplianceHandler(checker *services.OffDayComplianceChecker, complianceRepo *repositories.OffDayComplianceRepository, actualRepo *repositories.ActualRepository, crewInfoRepo *repositories.CrewInfoRepository) *OffDayComplianceHandler {
+	return &OffDayComplianceHandler{checker: checker, complianceRepo: complianceRepo, actualRepo: actualRepo, crewInfoRepo: crewInfoRepo}
+}
+
+// CheckOffDayComplianceRequest: Boş gün uygunluk kontrolü isteğinin yapısı
+type CheckOffDayComplianceRequest struct {
+	Month      string   `json:"month"`       // "YYYY-MM" (actuals.period_month biçimi de kabul edilir)
+	CrewIDs    []string `json:"crew_ids"`    // Verilirse yalnızca bu ekip üyeleri kontrol edilir
+	Scope      string   `json:"scope"`       // "base", "fleet"; boşsa ayda aktivitesi olan tüm ekip üyeleri
+	ScopeValue string   `json:"scope_value"` // Örn: "IST", "B737"
+}
+
+// CheckCompliance: Verilen ay için ekip üyelerinin çalışılan/boş gün sayılarını actuals'tan hesaplar,
+// off_day_table hak edişi ve dağılımına göre kontrol eder ve sonuçları kaydeder.
+func (h *OffDayComplianceHandler) CheckCompliance(c *fiber.Ctx) error {
+	var req CheckOffDayComplianceRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("Hata: CheckCompliance isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+	period, err := services.ParseOffDayPeriod(req.Month)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz ay (YYYY-MM bekleniyor)", "details": err.Error()})
+	}
+
+	crewIDs := req.CrewIDs
+	if len(crewIDs) == 0 {
+		scopeValue := strings.TrimSpace(req.ScopeValue)
+		switch req.Scope {
+		case "":
+			// Ana üs zaman dilimi farkları için aralık bir gün genişletilir
+			crewIDs, err = h.actualRepo.GetPersonIDsInRange(c.Context(), period.AddDate(0, 0, -1), period.AddDate(0, 1, 1))
+		case models.RecalcScopeBase:
+			crewIDs, err = h.crewInfoRepo.GetPersonIDsBy(c.Context(), "base_location", scopeValue)
+		case models.RecalcScopeFleet:
+			crewIDs, err = h.crewInfoRepo.GetPersonIDsBy(c.Context(), "base_filo", scopeValue)
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "scope base veya fleet olmalı"})
+		}
+		if err != nil {
+			log.Printf("Hata: Boş gün kontrolü için ekip üyeleri çekilemedi: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Ekip üyeleri çekilemedi", "details": err.Error()})
+		}
+	}
+	if len(crewIDs) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kapsamda kontrol edilecek ekip üyesi bulunamadı"})
+	}
+
+	results, errs := h.checker.CheckCrewPeriods(c.Context(), crewIDs, period)
+	if len(results) == 0 && len(errs) > 0 {
+		log.Printf("Hata: Boş gün uygunluğu kontrol edilemedi: %v", errs)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Boş gün uygunluğu kontrol edilemedi", "details": strings.Join(errs, "; ")})
+	}
+
+	violationCount := 0
+	for _, r := range results {
+		violationCount += len(r.Violations)
+	}
+	log.Printf("✅ %s dönemi boş gün kontrolü: %d ekip üyesi, %d ihlal, %d hata.", period.Format("2006-01"), len(results), violationCount, len(errs))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": results, "violationCount": violationCount, "errors": errs})
+}
+
+// ListCompliance: Kaydedilmiş boş gün uygunluk sonuçlarını döndürür.
+// Sorgu parametreleri: month (YYYY-MM, verilmezse içinde bulunulan ay), crew_id, violations_only (true/false).
+func (h *OffDayComplianceHandler) ListCompliance(c *fiber.Ctx) error {
+	month := c.Query("month", time.Now().Format("2006-01"))
+	period, err := services.ParseOffDayPeriod(month)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz ay (YYYY-MM bekleniyor)", "details": err.Error()})
+	}
+	periodMonth := period.Format("2006-01")
+
+	rows, err := h.complianceRepo.ListCompliance(c.Context(), periodMonth, periodMonth, c.Query("crew_id"), c.Query("violations_only") == "true")
+	if err != nil {
+		log.Printf("Hata: Boş gün uygunluk sonuçları listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Boş gün uygunluk sonuçları listelenemedi", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": rows, "totalCount": len(rows)})
+}
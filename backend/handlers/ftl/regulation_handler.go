@@ -0,0 +1,88 @@
+package ftl
+
+import (
+	"log"
+	"strings"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegulationHandler, ekip üyelerine ve uçak tescillerine uygulanacak FTL mevzuat çerçevesi atamalarını yönetir.
+type RegulationHandler struct {
+	regulationRepo *repositories.RegulationAssignmentRepository
+}
+
+func NewRegulationHandler(regulationRepo *repositories.RegulationAssignmentRepository) *RegulationHandler {
+	return &RegulationHandler{regulationRepo: regulationRepo}
+}
+
+// ListRegulations: Tanımlı mevzuat çerçevelerini ve varsayılanı döndürür.
+func (h *RegulationHandler) ListRegulations(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"regulations": []string{models.RegulationSHTFTL, models.RegulationEASA, models.RegulationFAA117},
+		"default":     models.DefaultRegulation,
+	})
+}
+
+// ListAssignments: Mevzuat atamalarını döndürür (subject_type ile filtrelenebilir).
+func (h *RegulationHandler) ListAssignments(c *fiber.Ctx) error {
+	assignments, err := h.regulationRepo.ListAssignments(c.Context(), c.Query("subject_type"))
+	if err != nil {
+		log.Printf("Hata: Mevzuat atamaları listelenirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Mevzuat atamaları listelenemedi", "details": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(assignments)
+}
+
+// UpsertAssignment: Bir ekip üyesine veya uçak tesciline mevzuat çerçevesi atar ya da atamayı günceller.
+// Atama, ilgili ekip üyeleri yeniden hesaplandığında trip sonuçlarına yansır.
+func (h *RegulationHandler) UpsertAssignment(c *fiber.Ctx) error {
+	var assignment models.RegulationAssignment
+	if err := c.BodyParser(&assignment); err != nil {
+		log.Printf("Hata: UpsertAssignment isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+
+	assignment.SubjectValue = strings.TrimSpace(assignment.SubjectValue)
+	if assignment.SubjectType == models.RegulationSubjectRegistration {
+		assignment.SubjectValue = strings.ToUpper(assignment.SubjectValue)
+	}
+	if assignment.SubjectType != models.RegulationSubjectCrew && assignment.SubjectType != models.RegulationSubjectRegistration {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "subject_type crew veya registration olmalı"})
+	}
+	if assignment.SubjectValue == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "subject_value boş olamaz"})
+	}
+	if !models.IsValidRegulation(assignment.Regulation) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz mevzuat çerçevesi: " + assignment.Regulation})
+	}
+
+	if err := h.regulationRepo.UpsertAssignment(c.Context(), &assignment); err != nil {
+		log.Printf("Hata: Mevzuat ataması kaydedilirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Mevzuat ataması kaydedilemedi", "details": err.Error()})
+	}
+
+	log.Printf("✅ %s=%s için %s mevzuatı atandı.", assignment.SubjectType, assignment.SubjectValue, assignment.Regulation)
+	return c.Status(fiber.StatusOK).JSON(assignment)
+}
+
+// DeleteAssignment: Mevzuat atamasını siler (konu varsayılan çerçeveye döner).
+func (h *RegulationHandler) DeleteAssignment(c *fiber.Ctx) error {
+	subjectType := c.Params("subject_type")
+	subjectValue := strings.TrimSpace(c.Params("subject_value"))
+	if subjectType == models.RegulationSubjectRegistration {
+		subjectValue = strings.ToUpper(subjectValue)
+	}
+	deleted, err := h.regulationRepo.DeleteAssignment(c.Context(), subjectType, subjectValue)
+	if err != nil {
+		log.Printf("Hata: Mevzuat ataması silinirken sorun: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Mevzuat ataması silinemedi", "details": err.Error()})
+	}
+	if !deleted {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Mevzuat ataması bulunamadı"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package ftl
+
+import (
+	"errors"
+	"log"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WhatIfRequest: Kaydedilmeden denenecek program değişiklikleri
+type WhatIfRequest struct {
+	Changes []models.WhatIfChange `json:"changes"`
+}
+
+// HandleWhatIf: Önerilen değişiklikleri etkilenen ekip üyelerinin mevcut programına uygular ve FTL ihlallerini
+// mevcut durumla karşılaştırmalı olarak döndürür. Hiçbir trip kaydedilmez.
+func (h *FTLHandler) HandleWhatIf(c *fiber.Ctx) error {
+	var req WhatIfRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("Hata: WhatIf isteği ayrıştırılamadı: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi", "details": err.Error()})
+	}
+
+	results, err := h.ftlCalc.EvaluateWhatIf(c.Context(), req.Changes)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWhatIfChange) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz değişiklik", "details": err.Error()})
+		}
+		log.Printf("Hata: What-if değerlendirmesi yapılamadı: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "What-if değerlendirmesi yapılamadı", "details": err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": results})
+}
@@ -7,8 +7,12 @@ import (
 	"mini_CMS_Desktop_App/handlers"
 	"mini_CMS_Desktop_App/handlers/activity_code"
 	"mini_CMS_Desktop_App/handlers/aircraft_crew_need"
+	"mini_CMS_Desktop_App/handlers/aircraft_type"
+	"mini_CMS_Desktop_App/handlers/airport"
+	"mini_CMS_Desktop_App/handlers/brief_debrief_rule"
 	"mini_CMS_Desktop_App/handlers/crew_document"
 	"mini_CMS_Desktop_App/handlers/crew_info"
+	"mini_CMS_Desktop_App/handlers/crew_position"
 	"mini_CMS_Desktop_App/handlers/off_day_table"
 	"mini_CMS_Desktop_App/handlers/open_trip"
 	"mini_CMS_Desktop_App/handlers/penalty"
@@ -57,19 +61,44 @@ func main() {
 	userPrefRepo := repositories.NewUserPreferenceRepository(sqlDB)
 	publishRepo := repositories.NewPublishRepository(sqlDB)
 	openTripRepo := repositories.NewOpenTripRepo(sqlDB) // ✅ Tek repo
+	ftlRuleSetRepo := repositories.NewFTLRuleSetRepository(sqlDB)
+	crewInfoRepo := repositories.NewCrewInfoRepository(sqlDB)
+	aircraftTypeRepo := repositories.NewAircraftTypeRepository(sqlDB)
+	crewPositionRepo := repositories.NewCrewPositionRepository(sqlDB)
+	discretionRepo := repositories.NewCommanderDiscretionRepository(sqlDB)
+	baseAirportRepo := repositories.NewCrewBaseAirportRepository(sqlDB)
+	regulationRepo := repositories.NewRegulationAssignmentRepository(sqlDB)
+	recalcJobRepo := repositories.NewFTLRecalcJobRepository(sqlDB)
+	offDayTableRepo := repositories.NewOffDayTableRepository(sqlDB)
+	airportRepo := repositories.NewAirportRepository(sqlDB)
+	offDayComplianceRepo := repositories.NewOffDayComplianceRepository(sqlDB)
 
 	// --- Services ---
 	briefDebriefCalc := services.NewBriefDebriefCalculator(briefDebriefRuleRepo)
-	ftlCalc := services.NewFTLCalculator(briefDebriefCalc, tripRepo, actualRepo, userPrefRepo)
+	airportService := services.NewAirportService(airportRepo)
+	ftlCalc := services.NewFTLCalculator(briefDebriefCalc, tripRepo, actualRepo, userPrefRepo, ftlRuleSetRepo, crewInfoRepo, discretionRepo, baseAirportRepo, regulationRepo)
 	openTripService := services.NewOpenTripService(openTripRepo) // ✅ Tek parametre
+	offDayChecker := services.NewOffDayComplianceChecker(actualRepo, offDayTableRepo, offDayComplianceRepo, crewInfoRepo)
+	recalcRunner := services.NewFTLRecalcJobRunner(ftlCalc, offDayChecker, recalcJobRepo, progress.SendProgressUpdate)
 
 	// --- Handlers ---
-	ftlHandler := ftl.NewFTLHandler(ftlCalc, tripRepo)
-	actualImportXLSXHandler := handlers.NewActualImportXLSXHandler(actualRepo, ftlCalc, tripRepo, ftlHandler)
+	ftlHandler := ftl.NewFTLHandler(ftlCalc, tripRepo, offDayComplianceRepo)
+	ftlRuleSetHandler := ftl.NewFTLRuleSetHandler(ftlRuleSetRepo)
+	discretionHandler := ftl.NewCommanderDiscretionHandler(discretionRepo, tripRepo, ftlCalc)
+	baseAirportHandler := ftl.NewCrewBaseAirportHandler(baseAirportRepo)
+	regulationHandler := ftl.NewRegulationHandler(regulationRepo)
+	headroomHandler := ftl.NewHeadroomHandler(ftlCalc, crewInfoRepo)
+	offDayHandler := ftl.NewOffDayComplianceHandler(offDayChecker, offDayComplianceRepo, actualRepo, crewInfoRepo)
+	recalcJobHandler := ftl.NewFTLRecalcJobHandler(recalcRunner, recalcJobRepo, actualRepo, crewInfoRepo, tripRepo)
+	actualImportXLSXHandler := handlers.NewActualImportXLSXHandler(actualRepo, ftlCalc, tripRepo, ftlHandler, recalcRunner)
 	publishImportXLSXHandler := handlers.NewPublishImportXLSXHandler(publishRepo)
 	publishQueryHandler := handlers.NewPublishQueryHandler(publishRepo)
 	userPrefHandler := user_preference.NewUserPreferenceHandler(userPrefRepo)
 	openTripHandler := open_trip.NewOpenTripHandler(openTripService)
+	airportHandler := airport.NewAirportHandler(airportRepo, airportService)
+	aircraftTypeHandler := aircraft_type.NewAircraftTypeHandler(aircraftTypeRepo, actualRepo)
+	crewPositionHandler := crew_position.NewCrewPositionHandler(crewPositionRepo, actualRepo)
+	briefDebriefRuleHandler := brief_debrief_rule.NewBriefDebriefRuleHandler(briefDebriefRuleRepo, tripRepo, briefDebriefCalc)
 
 	// --- Public Routes ---
 	app.Post("/api/register", handlers.RegisterUserHandler)
@@ -103,6 +132,35 @@ func main() {
 	protected.Post("/off-day-table/import-data", off_day_table.ImportOffDayTableData)
 	protected.Get("/off-day-table/list", off_day_table.ListOffDayTable)
 
+	// AIRPORTS
+	protected.Post("/airports/import-data", airportHandler.ImportAirportData)
+	protected.Get("/airports/list", airportHandler.ListAirports)
+	protected.Get("/airports/query", airportHandler.QueryAirports)
+	protected.Get("/airports/lookup/:code", airportHandler.LookupAirport)
+
+	// AIRCRAFT TYPES
+	protected.Post("/aircraft-types/import-data", aircraftTypeHandler.ImportAircraftTypeData)
+	protected.Get("/aircraft-types/list", aircraftTypeHandler.ListAircraftTypes)
+	protected.Get("/aircraft-types/unmapped", aircraftTypeHandler.ListUnmappedCmsTypes)
+	protected.Put("/aircraft-types", aircraftTypeHandler.UpsertAircraftType)
+	protected.Delete("/aircraft-types/:cms_type", aircraftTypeHandler.DeleteAircraftType)
+
+	// CREW POSITIONS
+	protected.Post("/crew-positions/import-data", crewPositionHandler.ImportCrewPositionData)
+	protected.Get("/crew-positions/list", crewPositionHandler.ListCrewPositions)
+	protected.Get("/crew-positions/unmapped", crewPositionHandler.ListUnmappedFlightPositions)
+	protected.Put("/crew-positions", crewPositionHandler.UpsertCrewPosition)
+	protected.Delete("/crew-positions/:position_code", crewPositionHandler.DeleteCrewPosition)
+
+	// BRIEF/DEBRIEF RULES
+	protected.Get("/brief-debrief-rules/list", briefDebriefRuleHandler.ListRules)
+	protected.Get("/brief-debrief-rules/export", briefDebriefRuleHandler.ExportRules)
+	protected.Get("/brief-debrief-rules/analysis", briefDebriefRuleHandler.AnalyseRules)
+	protected.Post("/brief-debrief-rules/import-data", briefDebriefRuleHandler.ImportRuleData)
+	protected.Post("/brief-debrief-rules", briefDebriefRuleHandler.CreateRule)
+	protected.Put("/brief-debrief-rules/:id", briefDebriefRuleHandler.UpdateRule)
+	protected.Delete("/brief-debrief-rules/:id", briefDebriefRuleHandler.DeleteRule)
+
 	// CREW INFO
 	protected.Post("/crew-info/import-data", crew_info.ImportCrewInfoData)
 	protected.Get("/crew-info/list", crew_info.ListCrewInfo)
@@ -117,8 +175,34 @@ func main() {
 
 	// FTL
 	protected.Post("/ftl/calculate_trip", ftlHandler.HandleCalculateTripFTL)
+	protected.Post("/ftl/what-if", ftlHandler.HandleWhatIf)
+	protected.Get("/ftl/trips/:trip_id/explain", ftlHandler.ExplainTrip)
+	protected.Get("/ftl/headroom", headroomHandler.RankCrewHeadroom)
+	protected.Get("/ftl/headroom/:crew_id", headroomHandler.GetCrewHeadroom)
 	protected.Post("/ftl/recalculate_crew_schedule", ftlHandler.HandleRecalculateCrewScheduleFTL)
+	protected.Post("/ftl/recalc-jobs", recalcJobHandler.StartRecalcJob)
+	protected.Get("/ftl/recalc-jobs", recalcJobHandler.ListRecalcJobs)
+	protected.Get("/ftl/recalc-jobs/:id", recalcJobHandler.GetRecalcJob)
+	protected.Post("/ftl/recalc-jobs/:id/cancel", recalcJobHandler.CancelRecalcJob)
 	protected.Get("/ftl/trips_by_crew_id", ftlHandler.GetTripsByCrewID)
+	protected.Get("/ftl/violations", ftlHandler.ListViolations)
+	protected.Get("/ftl/violations/summary", ftlHandler.ViolationSummary)
+	protected.Get("/ftl/fatigue/highest-risk", ftlHandler.ListHighestFatigueTrips)
+	protected.Get("/ftl/off-days", offDayHandler.ListCompliance)
+	protected.Post("/ftl/off-days/check", offDayHandler.CheckCompliance)
+	protected.Get("/ftl/rule-sets", ftlRuleSetHandler.ListRuleSets)
+	protected.Post("/ftl/rule-sets", ftlRuleSetHandler.CreateRuleSet)
+	protected.Post("/ftl/rule-sets/:id/activate", ftlRuleSetHandler.ActivateRuleSet)
+	protected.Get("/ftl/discretions", discretionHandler.ListDiscretions)
+	protected.Post("/ftl/discretions", discretionHandler.RecordDiscretion)
+	protected.Get("/ftl/discretions/report", discretionHandler.DiscretionReport)
+	protected.Get("/ftl/base-airports", baseAirportHandler.ListBaseAirports)
+	protected.Put("/ftl/base-airports", baseAirportHandler.UpsertBaseAirport)
+	protected.Delete("/ftl/base-airports/:airport_code", baseAirportHandler.DeleteBaseAirport)
+	protected.Get("/ftl/regulations", regulationHandler.ListRegulations)
+	protected.Get("/ftl/regulations/assignments", regulationHandler.ListAssignments)
+	protected.Put("/ftl/regulations/assignments", regulationHandler.UpsertAssignment)
+	protected.Delete("/ftl/regulations/assignments/:subject_type/:subject_value", regulationHandler.DeleteAssignment)
 
 	// USER PREFERENCES
 	protected.Post("/user_preferences", userPrefHandler.SetUserPreference)
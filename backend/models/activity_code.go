@@ -13,4 +13,5 @@ type ActivityCode struct {
 	ActivityCode            string `bun:"activity_code,unique" json:"activity_code"`
 	ActivityGroupCode       string `bun:"activity_group_code" json:"activity_group_code"`
 	ActivityCodeExplanation string `bun:"activity_code_explanation" json:"activity_code_explanation"`
+	StandbyType             string `bun:"standby_type" json:"standby_type"` // "airport_standby", "home_standby", "reserve" veya boş
 }
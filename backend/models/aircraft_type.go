@@ -0,0 +1,169 @@
+package models
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/uptrace/bun"
+)
+
+// Gövde sınıfları (brief/debrief kurallarındaki aircraft_type değerleri)
+const (
+	AircraftBodyNarrow  = "DAR GÖVDE"
+	AircraftBodyWide    = "GENİŞ GÖVDE"
+	AircraftTypeUnknown = "BİLİNMİYOR" // Tanımsız CMS tipi
+)
+
+// Uçuş içi dinlenme tesisi sınıfları (CS FTL.1.205(c))
+const (
+	RestFacilityNone   = 0 // Dinlenme tesisi yok; uzatılmış ekip limitleri uygulanmaz
+	RestFacilityClass1 = 1 // Yatay uzanılabilen, yolcu kabininden ayrı ranza
+	RestFacilityClass2 = 2 // En az 45° yatabilen, perdeyle ayrılmış koltuk
+	RestFacilityClass3 = 3 // En az 40° yatabilen, ayak desteği olan koltuk
+)
+
+// AircraftType, bir CMS uçak tipinin gövde sınıfını, ICAO tip kodunu, kargo bayrağını ve uçuş içi dinlenme
+// tesisi sınıfını tutar. Brief/debrief kuralı seçimi gövde sınıfına, uzatılmış (3-4 pilotlu) ekiplerin
+// azami UGS limiti dinlenme tesisi sınıfına göre yapılır.
+type AircraftType struct {
+	bun.BaseModel `bun:"table:aircraft_types"`
+
+	DataID            int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	CmsType           string    `json:"cms_type" bun:"cms_type,notnull,unique"` // Örn: "77X", "350"
+	BodyClass         string    `json:"body_class" bun:"body_class,notnull"`    // "DAR GÖVDE", "GENİŞ GÖVDE"
+	ICAOType          string    `json:"icao_type,omitempty" bun:"icao_type"`    // Örn: "B77W", "A359"
+	IsCargo           bool      `json:"is_cargo" bun:"is_cargo,notnull,default:false"`
+	RestFacilityClass int       `json:"rest_facility_class" bun:"rest_facility_class,notnull,default:0"` // 0 = yok, 1-3 = sınıf
+	Description       string    `json:"description,omitempty" bun:"description"`
+	UpdatedAt         time.Time `json:"updated_at" bun:"updated_at,default:current_timestamp"`
+}
+
+// IsValidRestFacilityClass, verilen değerin tanımlı bir dinlenme tesisi sınıfı olup olmadığını döndürür.
+func IsValidRestFacilityClass(class int) bool {
+	return class >= RestFacilityNone && class <= RestFacilityClass3
+}
+
+// NormalizeBodyClass, gövde sınıfı girdisini ("dar gövde", "GENIS GOVDE", "narrow", "WB" vb.) tanımlı sabite
+// çevirir; tanınmayan değerler için boş döner.
+func NormalizeBodyClass(value string) string {
+	switch strings.ToUpperSpecial(unicode.TurkishCase, strings.TrimSpace(value)) {
+	case AircraftBodyNarrow, "DAR GOVDE", "NARROW", "NARROWBODY", "NB":
+		return AircraftBodyNarrow
+	case AircraftBodyWide, "GENIS GOVDE", "GENİS GOVDE", "WIDE", "WIDEBODY", "WB":
+		return AircraftBodyWide
+	}
+	return ""
+}
+
+// Varsayılan CMS tipi → gövde sınıfı haritası. aircraft_types tablosu boşken başlangıç verisi olarak kullanılır;
+// çalışma zamanında tablonun önbelleğe alınmış kopyası (RegisterAircraftTypes) esas alınır.
+var defaultAircraftTypeMapping = map[string]string{
+	"310": "DAR GÖVDE", "319": "DAR GÖVDE", "320": "DAR GÖVDE", "321": "DAR GÖVDE", "32D": "DAR GÖVDE", "32H": "DAR GÖVDE",
+	"37B": "DAR GÖVDE", "3A0": "DAR GÖVDE", "3A1": "DAR GÖVDE", "3HD": "DAR GÖVDE", "3S2": "DAR GÖVDE", "3VF": "DAR GÖVDE",
+	"6VF": "DAR GÖVDE", "734": "DAR GÖVDE", "737": "DAR GÖVDE", "738": "DAR GÖVDE", "739": "DAR GÖVDE", "73D": "DAR GÖVDE",
+	"73E": "DAR GÖVDE", "73H": "DAR GÖVDE", "73M": "DAR GÖVDE", "73N": "DAR GÖVDE", "73P": "DAR GÖVDE", "73S": "DAR GÖVDE",
+	"73V": "DAR GÖVDE", "73Z": "DAR GÖVDE", "74D": "DAR GÖVDE", "78H": "DAR GÖVDE", "78I": "DAR GÖVDE", "78L": "DAR GÖVDE",
+	"78T": "DAR GÖVDE", "79Y": "DAR GÖVDE", "79Z": "DAR GÖVDE", "7A8": "DAR GÖVDE", "7B8": "DAR GÖVDE", "7C3": "DAR GÖVDE",
+	"7D3": "DAR GÖVDE", "7VF": "DAR GÖVDE", "8VF": "DAR GÖVDE", "9VF": "DAR GÖVDE", "A20": "DAR GÖVDE", "A21": "DAR GÖVDE",
+	"A32": "DAR GÖVDE", "A78": "DAR GÖVDE", "B32": "DAR GÖVDE", "B78": "DAR GÖVDE", "C32": "DAR GÖVDE", "D23": "DAR GÖVDE",
+	"D32": "DAR GÖVDE", "D73": "DAR GÖVDE", "E20": "DAR GÖVDE", "E21": "DAR GÖVDE", "E32": "DAR GÖVDE", "G32": "DAR GÖVDE",
+	"HD3": "DAR GÖVDE", "K21": "DAR GÖVDE", "L20": "DAR GÖVDE", "L21": "DAR GÖVDE", "M32": "DAR GÖVDE", "N32": "DAR GÖVDE",
+	"N78": "DAR GÖVDE", "N79": "DAR GÖVDE", "R21": "DAR GÖVDE", "SA1": "DAR GÖVDE", "SC0": "DAR GÖVDE", "SL7": "DAR GÖVDE",
+	"SL8": "DAR GÖVDE", "SX1": "DAR GÖVDE", "SY0": "DAR GÖVDE", "TC0": "DAR GÖVDE", "TC1": "DAR GÖVDE", "TC2": "DAR GÖVDE",
+	"TC3": "DAR GÖVDE", "TC4": "DAR GÖVDE", "TC5": "DAR GÖVDE", "TC6": "DAR GÖVDE", "TC7": "DAR GÖVDE", "TC8": "DAR GÖVDE",
+	"TC9": "DAR GÖVDE", "TKB": "DAR GÖVDE", "TKD": "DAR GÖVDE", "TKE": "DAR GÖVDE", "TKS": "DAR GÖVDE", "U21": "DAR GÖVDE",
+	"V20": "DAR GÖVDE", "V21": "DAR GÖVDE", "VFA": "DAR GÖVDE", "VFB": "DAR GÖVDE", "VFC": "DAR GÖVDE", "VFD": "DAR GÖVDE",
+	"VFE": "DAR GÖVDE", "VFM": "DAR GÖVDE", "VFQ": "DAR GÖVDE", "VFS": "DAR GÖVDE", "VFT": "DAR GÖVDE", "VFX": "DAR GÖVDE",
+	"VIP": "DAR GÖVDE", "W20": "DAR GÖVDE", "W21": "DAR GÖVDE", "W31": "DAR GÖVDE", "W32": "DAR GÖVDE", "W33": "DAR GÖVDE",
+	"W34": "DAR GÖVDE", "X20": "DAR GÖVDE", "X21": "DAR GÖVDE", "X32": "DAR GÖVDE", "Y20": "DAR GÖVDE", "Y21": "DAR GÖVDE",
+	"Z73": "DAR GÖVDE", "ZB1": "DAR GÖVDE", "ZB2": "DAR GÖVDE", "ZB3": "DAR GÖVDE",
+
+	"330": "GENİŞ GÖVDE", "332": "GENİŞ GÖVDE", "333": "GENİŞ GÖVDE", "33A": "GENİŞ GÖVDE", "33B": "GENİŞ GÖVDE", "33C": "GENİŞ GÖVDE",
+	"33E": "GENİŞ GÖVDE", "33F": "GENİŞ GÖVDE", "33H": "GENİŞ GÖVDE", "33I": "GENİŞ GÖVDE", "33J": "GENİŞ GÖVDE", "33M": "GENİŞ GÖVDE",
+	"33N": "GENİŞ GÖVDE", "33P": "GENİŞ GÖVDE", "33R": "GENİŞ GÖVDE", "33S": "GENİŞ GÖVDE", "33T": "GENİŞ GÖVDE", "33V": "GENİŞ GÖVDE",
+	"33X": "GENİŞ GÖVDE", "33Y": "GENİŞ GÖVDE", "33Z": "GENİŞ GÖVDE", "340": "GENİŞ GÖVDE", "343": "GENİŞ GÖVDE", "350": "GENİŞ GÖVDE",
+	"35D": "GENİŞ GÖVDE", "35E": "GENİŞ GÖVDE", "3MF": "GENİŞ GÖVDE", "74F": "GENİŞ GÖVDE", "74G": "GENİŞ GÖVDE", "74I": "GENİŞ GÖVDE",
+	"777": "GENİŞ GÖVDE", "77A": "GENİŞ GÖVDE", "77B": "GENİŞ GÖVDE", "77K": "GENİŞ GÖVDE", "77M": "GENİŞ GÖVDE", "77R": "GENİŞ GÖVDE",
+	"77X": "GENİŞ GÖVDE", "787": "GENİŞ GÖVDE", "789": "GENİŞ GÖVDE", "B74": "GENİŞ GÖVDE", "C74": "GENİŞ GÖVDE", "D33": "GENİŞ GÖVDE",
+	"H77": "GENİŞ GÖVDE", "I77": "GENİŞ GÖVDE", "TK3": "GENİŞ GÖVDE", "TK7": "GENİŞ GÖVDE",
+}
+
+// DefaultAircraftTypes, varsayılan haritadaki CMS tiplerini aircraft_types başlangıç verisi olarak döndürür.
+// Gövde sınıfından dinlenme tesisi çıkarılmaz; tüm tipler tesissiz (RestFacilityNone) eklenir ve gerçek sınıflar
+// API veya import ile açıkça girilir.
+func DefaultAircraftTypes() []AircraftType {
+	types := make([]AircraftType, 0, len(defaultAircraftTypeMapping))
+	for cmsType, bodyClass := range defaultAircraftTypeMapping {
+		types = append(types, AircraftType{CmsType: cmsType, BodyClass: bodyClass, RestFacilityClass: RestFacilityNone})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].CmsType < types[j].CmsType })
+	return types
+}
+
+var (
+	aircraftTypes       map[string]AircraftType // nil: tablo henüz yüklenmedi, varsayılan harita kullanılır
+	aircraftTypesMu     sync.RWMutex
+	unknownCmsTypesSeen sync.Map // Tanımsız CMS tipi uyarısı her tip için bir kez yazılır
+)
+
+// RegisterAircraftTypes, aircraft_types tablosundan okunan tipleri önbelleğe alır. Sonraki tüm sınıflandırmalar
+// bu kopyadan yapılır; tablo değiştiğinde (import, CRUD) yeniden çağrılmalıdır.
+func RegisterAircraftTypes(types []AircraftType) {
+	cache := make(map[string]AircraftType, len(types))
+	for _, t := range types {
+		t.CmsType = strings.ToUpper(strings.TrimSpace(t.CmsType))
+		cache[t.CmsType] = t
+	}
+	aircraftTypesMu.Lock()
+	aircraftTypes = cache
+	aircraftTypesMu.Unlock()
+	unknownCmsTypesSeen.Range(func(key, _ any) bool {
+		unknownCmsTypesSeen.Delete(key)
+		return true
+	})
+}
+
+// GetAircraftTypeInfo, CMS tipinin önbellekteki tanımını döndürür. Tablo henüz yüklenmemişse varsayılan
+// haritadaki gövde sınıfı döner.
+func GetAircraftTypeInfo(cmsType string) (AircraftType, bool) {
+	cmsType = strings.ToUpper(strings.TrimSpace(cmsType))
+	aircraftTypesMu.RLock()
+	cache := aircraftTypes
+	aircraftTypesMu.RUnlock()
+	if cache == nil {
+		bodyClass, ok := defaultAircraftTypeMapping[cmsType]
+		return AircraftType{CmsType: cmsType, BodyClass: bodyClass}, ok
+	}
+	t, ok := cache[cmsType]
+	return t, ok
+}
+
+// GetAircraftTypeFromCmsType, CMS tipinin gövde sınıfını döndürür; tanımsız tipler için AircraftTypeUnknown
+// döner ve tip başına bir kez uyarı yazılır (bkz. tanımsız CMS tipleri raporu).
+func GetAircraftTypeFromCmsType(cmsType string) string {
+	if t, ok := GetAircraftTypeInfo(cmsType); ok && t.BodyClass != "" {
+		return t.BodyClass
+	}
+	if cmsType != "" {
+		if _, seen := unknownCmsTypesSeen.LoadOrStore(cmsType, true); !seen {
+			log.Printf("⚠️ CMS uçak tipi '%s' aircraft_types tablosunda tanımlı değil; gövde sınıfı %s kabul edildi.", cmsType, AircraftTypeUnknown)
+		}
+	}
+	return AircraftTypeUnknown
+}
+
+// GetRestFacilityClass, CMS tipinin uçuş içi dinlenme tesisi sınıfını döndürür (tanımsız tipler için tesis yok).
+func GetRestFacilityClass(cmsType string) int {
+	t, _ := GetAircraftTypeInfo(cmsType)
+	return t.RestFacilityClass
+}
+
+// IsCargoCmsType, CMS tipinin kargo uçağı olarak işaretlenip işaretlenmediğini döndürür.
+func IsCargoCmsType(cmsType string) bool {
+	t, _ := GetAircraftTypeInfo(cmsType)
+	return t.IsCargo
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Airport, meydan referans verisini (kodlar, zaman dilimi, koordinatlar, ülke, ana üs bayrağı) tutar.
+// Yerel saat hesapları ve ana üs tespiti bu tablodan yüklenen verilerle yapılır; aynı ana üsse bağlı
+// meydanların gruplanması crew_base_airports tablosundadır.
+type Airport struct {
+	bun.BaseModel `bun:"table:airports"`
+
+	DataID    int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	IATACode  string    `json:"iata_code" bun:"iata_code,notnull,unique"` // Örn: "SAW"
+	ICAOCode  string    `json:"icao_code,omitempty" bun:"icao_code"`      // Örn: "LTFJ"
+	Name      string    `json:"name,omitempty" bun:"name"`
+	City      string    `json:"city,omitempty" bun:"city"`
+	Country   string    `json:"country,omitempty" bun:"country"`   // ISO 3166-1 alpha-2, örn: "TR"
+	TimeZone  string    `json:"time_zone" bun:"time_zone,notnull"` // IANA, örn: "Europe/Istanbul"
+	Latitude  float64   `json:"latitude" bun:"latitude"`
+	Longitude float64   `json:"longitude" bun:"longitude"`
+	IsBase    bool      `json:"is_base" bun:"is_base,notnull,default:false"` // Ekip ana üssü olarak kullanılan meydan
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,default:current_timestamp"`
+}
+
+var (
+	registeredAirports   = map[string]Airport{} // IATA ve ICAO kodlarıyla indekslenir
+	registeredAirportsMu sync.RWMutex
+)
+
+// RegisterAirports, airports tablosundan okunan meydanları etkin hale getirir. Kayıtlı meydanların zaman
+// dilimi varsayılan haritanın önüne geçer; geçersiz IANA zaman dilimine sahip kayıtlar atlanır.
+func RegisterAirports(airports []Airport) {
+	indexed := make(map[string]Airport, len(airports)*2)
+	for _, a := range airports {
+		a.IATACode = strings.ToUpper(strings.TrimSpace(a.IATACode))
+		a.ICAOCode = strings.ToUpper(strings.TrimSpace(a.ICAOCode))
+		if a.IATACode == "" {
+			continue
+		}
+		if _, ok := loadLocation(a.TimeZone); !ok {
+			continue
+		}
+		indexed[a.IATACode] = a
+		if a.ICAOCode != "" {
+			indexed[a.ICAOCode] = a
+		}
+	}
+
+	registeredAirportsMu.Lock()
+	registeredAirports = indexed
+	registeredAirportsMu.Unlock()
+}
+
+// GetAirport, IATA veya ICAO koduyla kayıtlı meydanı döndürür.
+func GetAirport(code string) (Airport, bool) {
+	registeredAirportsMu.RLock()
+	defer registeredAirportsMu.RUnlock()
+	a, ok := registeredAirports[strings.ToUpper(strings.TrimSpace(code))]
+	return a, ok
+}
+
+// airportTimeZone, meydanın IANA zaman dilimini önce kayıtlı meydanlardan, sonra varsayılan haritadan bulur.
+func airportTimeZone(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if a, ok := GetAirport(code); ok {
+		return a.TimeZone, true
+	}
+	tz, ok := defaultAirportTimeZones[code]
+	return tz, ok
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// Varsayılan meydan (IATA) → IANA zaman dilimi haritası.
+// airports tablosu boşken başlangıç verisi olarak kullanılır; tablodan yüklenen meydanlar (RegisterAirports)
+// bu haritanın üzerine yazılır. Hiçbirinde olmayan meydanlar için GetAirportLocation false döner ve
+// çağıran taraf referans zaman dilimini varsayar.
+var defaultAirportTimeZones = map[string]string{
+	// Türkiye
+	"IST": "Europe/Istanbul", "SAW": "Europe/Istanbul", "ISL": "Europe/Istanbul", "ESB": "Europe/Istanbul",
+	"ADB": "Europe/Istanbul", "AYT": "Europe/Istanbul", "DLM": "Europe/Istanbul", "BJV": "Europe/Istanbul",
+	"TZX": "Europe/Istanbul", "ADA": "Europe/Istanbul", "GZT": "Europe/Istanbul", "ASR": "Europe/Istanbul",
+	"DIY": "Europe/Istanbul", "ERZ": "Europe/Istanbul", "VAN": "Europe/Istanbul", "SZF": "Europe/Istanbul",
+	"KYA": "Europe/Istanbul", "MLX": "Europe/Istanbul", "EZS": "Europe/Istanbul", "GZP": "Europe/Istanbul",
+	"ECN": "Asia/Nicosia",
+
+	// Avrupa
+	"LHR": "Europe/London", "LGW": "Europe/London", "STN": "Europe/London", "MAN": "Europe/London", "BHX": "Europe/London",
+	"EDI": "Europe/London", "DUB": "Europe/Dublin", "CDG": "Europe/Paris", "ORY": "Europe/Paris", "NCE": "Europe/Paris",
+	"LYS": "Europe/Paris", "FRA": "Europe/Berlin", "MUC": "Europe/Berlin", "BER": "Europe/Berlin", "DUS": "Europe/Berlin",
+	"HAM": "Europe/Berlin", "STR": "Europe/Berlin", "CGN": "Europe/Berlin", "AMS": "Europe/Amsterdam", "BRU": "Europe/Brussels",
+	"ZRH": "Europe/Zurich", "GVA": "Europe/Zurich", "VIE": "Europe/Vienna", "FCO": "Europe/Rome", "MXP": "Europe/Rome",
+	"VCE": "Europe/Rome", "MAD": "Europe/Madrid", "BCN": "Europe/Madrid", "LIS": "Europe/Lisbon", "ATH": "Europe/Athens",
+	"CPH": "Europe/Copenhagen", "ARN": "Europe/Stockholm", "OSL": "Europe/Oslo", "HEL": "Europe/Helsinki",
+	"WAW": "Europe/Warsaw", "PRG": "Europe/Prague", "BUD": "Europe/Budapest", "OTP": "Europe/Bucharest",
+	"SOF": "Europe/Sofia", "BEG": "Europe/Belgrade", "KBP": "Europe/Kyiv", "SVO": "Europe/Moscow", "DME": "Europe/Moscow",
+	"VKO": "Europe/Moscow", "LED": "Europe/Moscow",
+
+	// Orta Doğu, Kafkasya ve Orta Asya
+	"TBS": "Asia/Tbilisi", "GYD": "Asia/Baku", "EVN": "Asia/Yerevan", "IKA": "Asia/Tehran", "BGW": "Asia/Baghdad",
+	"EBL": "Asia/Baghdad", "TLV": "Asia/Jerusalem", "AMM": "Asia/Amman", "BEY": "Asia/Beirut", "DXB": "Asia/Dubai",
+	"AUH": "Asia/Dubai", "DOH": "Asia/Qatar", "BAH": "Asia/Bahrain", "KWI": "Asia/Kuwait", "JED": "Asia/Riyadh",
+	"RUH": "Asia/Riyadh", "MED": "Asia/Riyadh", "MCT": "Asia/Muscat", "TAS": "Asia/Tashkent", "ALA": "Asia/Almaty",
+	"NQZ": "Asia/Almaty", "FRU": "Asia/Bishkek", "ASB": "Asia/Ashgabat", "DYU": "Asia/Dushanbe", "KBL": "Asia/Kabul",
+
+	// Asya - Pasifik
+	"DEL": "Asia/Kolkata", "BOM": "Asia/Kolkata", "KHI": "Asia/Karachi", "ISB": "Asia/Karachi", "LHE": "Asia/Karachi",
+	"DAC": "Asia/Dhaka", "CMB": "Asia/Colombo", "MLE": "Indian/Maldives", "BKK": "Asia/Bangkok", "SGN": "Asia/Ho_Chi_Minh",
+	"HAN": "Asia/Ho_Chi_Minh", "KUL": "Asia/Kuala_Lumpur", "SIN": "Asia/Singapore", "CGK": "Asia/Jakarta",
+	"DPS": "Asia/Makassar", "MNL": "Asia/Manila", "HKG": "Asia/Hong_Kong", "PEK": "Asia/Shanghai", "PKX": "Asia/Shanghai",
+	"PVG": "Asia/Shanghai", "CAN": "Asia/Shanghai", "ICN": "Asia/Seoul", "NRT": "Asia/Tokyo", "HND": "Asia/Tokyo",
+	"KIX": "Asia/Tokyo", "SYD": "Australia/Sydney", "MEL": "Australia/Melbourne",
+
+	// Afrika
+	"CAI": "Africa/Cairo", "HRG": "Africa/Cairo", "SSH": "Africa/Cairo", "TUN": "Africa/Tunis", "ALG": "Africa/Algiers",
+	"CMN": "Africa/Casablanca", "TIP": "Africa/Tripoli", "KRT": "Africa/Khartoum", "ADD": "Africa/Addis_Ababa",
+	"NBO": "Africa/Nairobi", "DAR": "Africa/Dar_es_Salaam", "JNB": "Africa/Johannesburg", "CPT": "Africa/Johannesburg",
+	"LOS": "Africa/Lagos", "ABV": "Africa/Lagos", "ACC": "Africa/Accra", "DKR": "Africa/Dakar", "MGQ": "Africa/Mogadishu",
+
+	// Amerika
+	"JFK": "America/New_York", "EWR": "America/New_York", "IAD": "America/New_York", "BOS": "America/New_York",
+	"MIA": "America/New_York", "ATL": "America/New_York", "ORD": "America/Chicago", "IAH": "America/Chicago",
+	"DFW": "America/Chicago", "DEN": "America/Denver", "LAX": "America/Los_Angeles", "SFO": "America/Los_Angeles",
+	"SEA": "America/Los_Angeles", "YYZ": "America/Toronto", "YUL": "America/Toronto", "YVR": "America/Vancouver",
+	"MEX": "America/Mexico_City", "CUN": "America/Cancun", "HAV": "America/Havana", "BOG": "America/Bogota",
+	"CCS": "America/Caracas", "GRU": "America/Sao_Paulo", "GIG": "America/Sao_Paulo", "EZE": "America/Argentina/Buenos_Aires",
+	"SCL": "America/Santiago", "LIM": "America/Lima", "PTY": "America/Panama",
+}
+
+// DefaultAirportTimeZones, varsayılan meydan → zaman dilimi haritasının bir kopyasını döndürür.
+func DefaultAirportTimeZones() map[string]string {
+	zones := make(map[string]string, len(defaultAirportTimeZones))
+	for code, tz := range defaultAirportTimeZones {
+		zones[code] = tz
+	}
+	return zones
+}
+
+var (
+	airportLocationCache   = map[string]*time.Location{}
+	airportLocationCacheMu sync.Mutex
+)
+
+// GetAirportLocation, meydan koduna (IATA veya ICAO) karşılık gelen zaman dilimini döndürür.
+// Meydan bilinmiyorsa veya zaman dilimi yüklenemezse ikinci dönüş değeri false olur.
+func GetAirportLocation(airportCode string) (*time.Location, bool) {
+	tzName, ok := airportTimeZone(airportCode)
+	if !ok {
+		return nil, false
+	}
+	return loadLocation(tzName)
+}
+
+// loadLocation, IANA zaman dilimini önbellekli olarak yükler.
+func loadLocation(tzName string) (*time.Location, bool) {
+	airportLocationCacheMu.Lock()
+	defer airportLocationCacheMu.Unlock()
+
+	if loc, ok := airportLocationCache[tzName]; ok {
+		return loc, true
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return nil, false
+	}
+	airportLocationCache[tzName] = loc
+	return loc, true
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SectorBlockTime, trip içindeki bir FLT sektörünün blok süresini (kalkış-varış) tutar.
+// Trip.Sectors içinde JSONB olarak saklanır; konumlandırma (DH) sektörleri dahil edilmez.
+type SectorBlockTime struct {
+	FlightNo      string    `json:"flight_no"`
+	DeparturePort string    `json:"departure_port"`
+	ArrivalPort   string    `json:"arrival_port"`
+	DepartureTime time.Time `json:"departure_time"`
+	ArrivalTime   time.Time `json:"arrival_time"`
+	BlockMin      int       `json:"block_min"`
+}
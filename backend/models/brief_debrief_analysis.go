@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Eşleştirmede hiçbir kurala kazanamayan kuralların nedeni
+const (
+	RuleNeverWinsShadowed    = "shadowed"    // Eşleştiği tüm kombinasyonlarda daha yüksek öncelikli (veya eşit öncelikte daha küçük ID'li) bir kural kazanıyor
+	RuleNeverWinsUnreachable = "unreachable" // Hesaplayıcının üretebileceği hiçbir kombinasyonla eşleşmiyor (ör. üretilmeyen ekip/görev tipi)
+)
+
+// BriefDebriefCombination, brief/debrief kuralı seçimine giren girdi kombinasyonudur.
+type BriefDebriefCombination struct {
+	CrewType         string `json:"crew_type"`
+	ScenarioType     string `json:"scenario_type"`
+	AircraftType     string `json:"aircraft_type"`
+	DutyStartAirport string `json:"duty_start_airport"` // "Diğer": kurallarda adı geçmeyen herhangi bir meydan
+}
+
+// BriefDebriefTie, aynı öncelikte birden fazla kuralın eşleştiği kombinasyonları kural grubu bazında toplar.
+// Bu durumda küçük ID'li kural kazanır; süreler farklıysa sonuç kural ekleme sırasına bağlı kalır.
+type BriefDebriefTie struct {
+	Priority        int                       `json:"priority"`
+	RuleIDs         []int                     `json:"rule_ids"`
+	WinnerRuleID    int                       `json:"winner_rule_id"`
+	DurationsDiffer bool                      `json:"durations_differ"`
+	Combinations    []BriefDebriefCombination `json:"combinations"`
+}
+
+// BriefDebriefNeverWinningRule, analiz tarihinde yürürlükte olup hiçbir kombinasyonda seçilmeyen kuralı açıklar.
+type BriefDebriefNeverWinningRule struct {
+	Rule                BriefDebriefRule `json:"rule"`
+	Reason              string           `json:"reason"` // "shadowed", "unreachable"
+	MatchedCombinations int              `json:"matched_combinations"`
+	ShadowedBy          []int            `json:"shadowed_by,omitempty"` // Eşleştiği kombinasyonlarda kazanan kural ID'leri
+}
+
+// BriefDebriefAnalysis, brief/debrief kural tablosunun verilen tarihteki kapsam analizidir: hiçbir kuralın
+// eşleşmediği (varsayılan sürelere düşen) kombinasyonlar, hiç kazanamayan kurallar ve eşit öncelikli çakışmalar.
+type BriefDebriefAnalysis struct {
+	AnalysedOn        time.Time                      `json:"analysed_on"`
+	RuleCount         int                            `json:"rule_count"` // Analiz tarihinde yürürlükte olan kural sayısı
+	CombinationCount  int                            `json:"combination_count"`
+	DefaultBriefMin   int                            `json:"default_brief_min"`
+	DefaultDebriefMin int                            `json:"default_debrief_min"`
+	Unmatched         []BriefDebriefCombination      `json:"unmatched"`
+	NeverWinning      []BriefDebriefNeverWinningRule `json:"never_winning"`
+	Ties              []BriefDebriefTie              `json:"ties"`
+}
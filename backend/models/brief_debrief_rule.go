@@ -2,7 +2,11 @@
 
 package models
 
-import "github.com/uptrace/bun" // bun paketi eklendi
+import (
+	"time"
+
+	"github.com/uptrace/bun" // bun paketi eklendi
+)
 
 // brief ve debrief süreleri için tanımlanan kuralları temsil eder.
 type BriefDebriefRule struct {
@@ -16,6 +20,29 @@ type BriefDebriefRule struct {
 	BriefDurationMin   int    `json:"brief_duration_min" bun:"brief_duration_min"`     // Briefing süresi dakika cinsinden
 	DebriefDurationMin int    `json:"debrief_duration_min" bun:"debrief_duration_min"` // Debriefing süresi dakika cinsinden
 	Priority           int    `json:"priority" bun:"priority,default:0"`               // Kural eşleşmesi için öncelik (yüksek sayı = yüksek öncelik)
+
+	// Geçerlilik aralığı (görev tarihine göre, iki uç dahil); boş uç sınırsız kabul edilir. Aynı senaryo, uçak
+	// tipi, ekip tipi ve meydan için birden fazla versiyon tanımlanabilir ancak aralıkları çakışamaz.
+	ValidFrom *time.Time `json:"valid_from,omitempty" bun:"valid_from,type:date"`
+	ValidTo   *time.Time `json:"valid_to,omitempty" bun:"valid_to,type:date"`
+	UpdatedAt time.Time  `json:"updated_at" bun:"updated_at,default:current_timestamp"`
+}
+
+// InForceOn, kuralın verilen görev tarihinde (takvim günü olarak) yürürlükte olup olmadığını döndürür.
+func (r BriefDebriefRule) InForceOn(dutyDate time.Time) bool {
+	day := time.Date(dutyDate.Year(), dutyDate.Month(), dutyDate.Day(), 0, 0, 0, 0, time.UTC)
+	if r.ValidFrom != nil && day.Before(dateOnly(*r.ValidFrom)) {
+		return false
+	}
+	if r.ValidTo != nil && day.After(dateOnly(*r.ValidTo)) {
+		return false
+	}
+	return true
+}
+
+// dateOnly, zamanın takvim gününü UTC gece yarısı olarak döndürür.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
 
 // TableName, bun ORM'in bu struct'ı 'brief_debrief_rules' tablosuyla eşleştirmesini sağlar.
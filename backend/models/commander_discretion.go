@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// CommanderDiscretion, gecikme nedeniyle azami UGS limitinin kaptan pilot takdiriyle aşıldığı
+// bir trip için kaydedilen takdir yetkisi kullanımını temsil eder (ORO.FTL.205(f)).
+type CommanderDiscretion struct {
+	bun.BaseModel `bun:"table:commander_discretions"`
+
+	DataID            int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	TripID            string    `json:"trip_id" bun:"trip_id,notnull"`
+	ExtensionMin      int       `json:"extension_min" bun:"extension_min,notnull"`             // Kaptanın onayladığı uzatma (dakika)
+	Reason            string    `json:"reason" bun:"reason,notnull"`                           // Gecikme/uzatma gerekçesi
+	CommanderPersonID string    `json:"commander_person_id" bun:"commander_person_id,notnull"` // Takdiri kullanan kaptanın person_id'si
+	RecordedByUserID  int64     `json:"recorded_by_user_id" bun:"recorded_by_user_id"`
+	CreatedAt         time.Time `json:"created_at" bun:"created_at,default:current_timestamp"`
+}
+
+// DiscretionReportRow, takdir yetkisi kullanım raporundaki tek bir gruplama satırıdır.
+type DiscretionReportRow struct {
+	GroupKey          string `json:"group_key"` // group_by'a göre ekip person_id'si, filo (CMS tipi) veya rota
+	Count             int    `json:"count"`
+	TotalExtensionMin int    `json:"total_extension_min"` // Limiti aşan fiili süre toplamı
+	MaxExtensionMin   int    `json:"max_extension_min"`
+}
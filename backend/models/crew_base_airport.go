@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Dinlenmenin yapıldığı yer (minimum dinlenme kuralının seçimi için)
+const (
+	RestLocationHomeBase          = "home_base"          // Ekip üyesinin ana üssü (kendi konutunda dinlenme)
+	RestLocationAwayAccommodation = "away_accommodation" // Ana üs dışı; işletmenin sağladığı konaklama tesisinde dinlenme
+)
+
+// CrewBaseAirport, bir meydanın hangi ekip ana üssüne ait olduğunu tutar.
+// Aynı şehirdeki birden fazla meydan (ör. IST, SAW, ISL) tek bir ana üs kodu altında gruplanır;
+// ekip üyesinin crew_info.base_location değeri bu gruplardan birinin herhangi bir meydanı olabilir.
+type CrewBaseAirport struct {
+	bun.BaseModel `bun:"table:crew_base_airports"`
+
+	DataID      int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	AirportCode string    `json:"airport_code" bun:"airport_code,notnull,unique"` // IATA kodu, örn: "SAW"
+	BaseCode    string    `json:"base_code" bun:"base_code,notnull"`              // Ana üs grubu, örn: "IST"
+	UpdatedAt   time.Time `json:"updated_at" bun:"updated_at,default:current_timestamp"`
+}
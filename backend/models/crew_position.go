@@ -0,0 +1,175 @@
+package models
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/uptrace/bun"
+)
+
+// Ekip tipleri (brief/debrief kurallarındaki crew_type değerleri)
+const (
+	CrewTypeFlight  = "Uçuş Ekibi"
+	CrewTypeCabin   = "Kabin Ekibi"
+	CrewTypeUnknown = "BİLİNMİYOR" // Tanımsız uçuş pozisyonu
+)
+
+// DeadheadFlightPosition, yolcu olarak konumlandırılan ekibin pozisyon kodudur; bir ekip tipi belirtmez.
+const DeadheadFlightPosition = "DH"
+
+// crewNeedColumns, aircraft_crew_need tablosundaki pozisyon sayısı sütunlarıdır (C/P/J/EF/A/S/L/EC/T).
+var crewNeedColumns = []string{"C", "P", "J", "EF", "A", "S", "L", "EC", "T"}
+
+// CrewPosition, bir uçuş pozisyonu kodunun ekip tipini, kıdem sırasını ve aircraft_crew_need tablosunda
+// sayıldığı sütunu tutar. Brief/debrief kuralı seçimi ekip tipine, açık trip hesabı ihtiyaç sütununa göre yapılır.
+type CrewPosition struct {
+	bun.BaseModel `bun:"table:crew_positions"`
+
+	DataID         int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	PositionCode   string    `json:"position_code" bun:"position_code,notnull,unique"` // Örn: "C1", "P2", "L"
+	CrewType       string    `json:"crew_type" bun:"crew_type,notnull"`                // "Uçuş Ekibi", "Kabin Ekibi"
+	RankOrder      int       `json:"rank_order" bun:"rank_order,notnull,default:0"`    // Küçük değer daha kıdemli
+	CrewNeedColumn string    `json:"crew_need_column" bun:"crew_need_column"`          // C/P/J/EF/A/S/L/EC/T; boşsa ihtiyaçta sayılmaz
+	Description    string    `json:"description,omitempty" bun:"description"`
+	UpdatedAt      time.Time `json:"updated_at" bun:"updated_at,default:current_timestamp"`
+}
+
+// CrewNeedColumns, aircraft_crew_need pozisyon sütunlarının bir kopyasını döndürür.
+func CrewNeedColumns() []string {
+	return append([]string(nil), crewNeedColumns...)
+}
+
+// IsValidCrewNeedColumn, verilen değerin aircraft_crew_need pozisyon sütunlarından biri (veya boş) olup
+// olmadığını döndürür.
+func IsValidCrewNeedColumn(column string) bool {
+	if column == "" {
+		return true
+	}
+	for _, c := range crewNeedColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCrewType, ekip tipi girdisini ("uçuş ekibi", "UCUS EKIBI", "cockpit", "cabin" vb.) tanımlı sabite
+// çevirir; tanınmayan değerler için boş döner.
+func NormalizeCrewType(value string) string {
+	switch strings.ToUpperSpecial(unicode.TurkishCase, strings.TrimSpace(value)) {
+	case "UÇUŞ EKİBİ", "UCUS EKIBI", "UÇUŞ EKIBI", "FLIGHT", "FLIGHT CREW", "COCKPIT", "FC":
+		return CrewTypeFlight
+	case "KABİN EKİBİ", "KABIN EKIBI", "CABIN", "CABIN CREW", "CC":
+		return CrewTypeCabin
+	}
+	return ""
+}
+
+// Varsayılan pozisyon tanımları (daha önce kodda sabit olan flightCrewPositions/cabinCrewPositions haritaları).
+// crew_positions tablosu boşken başlangıç verisi olarak kullanılır. Kabin pozisyonlarının ihtiyaç sütunu
+// bilinmediğinden boş bırakılır; API üzerinden tamamlanır.
+var defaultFlightCrewPositions = []struct{ code, column string }{
+	{"C1", "C"}, {"C2", "C"}, {"C3", "C"}, {"C4", "C"}, {"CI", "C"}, {"CN", "C"},
+	{"P1", "P"}, {"P2", "P"}, {"P3", "P"}, {"P4", "P"}, {"P5", "P"}, {"P6", "P"},
+	{"J1", "J"}, {"J2", "J"},
+}
+
+var defaultCabinCrewPositions = []string{"P", "L", "V", "F", "K", "E", "N", "Y", "Q", "Z", "B", "H"}
+
+// DefaultCrewPositions, varsayılan pozisyonları crew_positions başlangıç verisi olarak döndürür.
+// Kıdem sırası listedeki sıradır.
+func DefaultCrewPositions() []CrewPosition {
+	positions := make([]CrewPosition, 0, len(defaultFlightCrewPositions)+len(defaultCabinCrewPositions))
+	for _, p := range defaultFlightCrewPositions {
+		positions = append(positions, CrewPosition{PositionCode: p.code, CrewType: CrewTypeFlight, RankOrder: len(positions) + 1, CrewNeedColumn: p.column})
+	}
+	for _, code := range defaultCabinCrewPositions {
+		positions = append(positions, CrewPosition{PositionCode: code, CrewType: CrewTypeCabin, RankOrder: len(positions) + 1})
+	}
+	return positions
+}
+
+var (
+	crewPositions              map[string]CrewPosition // nil: tablo henüz yüklenmedi, varsayılan tanımlar kullanılır
+	crewPositionsMu            sync.RWMutex
+	unknownFlightPositionsSeen sync.Map // Tanımsız pozisyon uyarısı her kod için bir kez yazılır
+	defaultCrewPositionIndex   = indexCrewPositions(DefaultCrewPositions())
+)
+
+func indexCrewPositions(positions []CrewPosition) map[string]CrewPosition {
+	index := make(map[string]CrewPosition, len(positions))
+	for _, p := range positions {
+		p.PositionCode = strings.ToUpper(strings.TrimSpace(p.PositionCode))
+		index[p.PositionCode] = p
+	}
+	return index
+}
+
+// RegisterCrewPositions, crew_positions tablosundan okunan pozisyonları önbelleğe alır. Tablo değiştiğinde
+// (import, CRUD) yeniden çağrılmalıdır.
+func RegisterCrewPositions(positions []CrewPosition) {
+	cache := indexCrewPositions(positions)
+	crewPositionsMu.Lock()
+	crewPositions = cache
+	crewPositionsMu.Unlock()
+	unknownFlightPositionsSeen.Range(func(key, _ any) bool {
+		unknownFlightPositionsSeen.Delete(key)
+		return true
+	})
+}
+
+// GetCrewPositionInfo, pozisyon kodunun önbellekteki tanımını döndürür. Tablo henüz yüklenmemişse varsayılan
+// tanımlar kullanılır.
+func GetCrewPositionInfo(flightPosition string) (CrewPosition, bool) {
+	code := strings.ToUpper(strings.TrimSpace(flightPosition))
+	crewPositionsMu.RLock()
+	cache := crewPositions
+	crewPositionsMu.RUnlock()
+	if cache == nil {
+		cache = defaultCrewPositionIndex
+	}
+	p, ok := cache[code]
+	return p, ok
+}
+
+// GetCrewTypeFromFlightPosition, pozisyon kodunun ekip tipini döndürür. Tanımsız kodlar için CrewTypeUnknown
+// döner ve kod başına bir kez uyarı yazılır (bkz. tanımsız pozisyonlar raporu); DH ve boş kod uyarı üretmez.
+func GetCrewTypeFromFlightPosition(flightPosition string) string {
+	if p, ok := GetCrewPositionInfo(flightPosition); ok && p.CrewType != "" {
+		return p.CrewType
+	}
+	code := strings.ToUpper(strings.TrimSpace(flightPosition))
+	if code != "" && code != DeadheadFlightPosition {
+		if _, seen := unknownFlightPositionsSeen.LoadOrStore(code, true); !seen {
+			log.Printf("⚠️ Uçuş pozisyonu '%s' crew_positions tablosunda tanımlı değil; ekip tipi %s kabul edildi.", code, CrewTypeUnknown)
+		}
+	}
+	return CrewTypeUnknown
+}
+
+// GetCrewTypeFromActivities, aktivitelerdeki ilk tanımlı pozisyonun ekip tipini döndürür. Trip konumlandırma
+// (DH) ile başlasa bile ekip tipi görevli sektördeki pozisyondan bulunur.
+func GetCrewTypeFromActivities(activities []Actual) string {
+	for i := range activities {
+		if crewType := GetCrewTypeFromFlightPosition(activities[i].FlightPosition); crewType != CrewTypeUnknown {
+			return crewType
+		}
+	}
+	return CrewTypeUnknown
+}
+
+// IsFlightCrewPosition, uçuş pozisyonunun kokpit (pilot) pozisyonu olup olmadığını döndürür.
+func IsFlightCrewPosition(flightPosition string) bool {
+	p, ok := GetCrewPositionInfo(flightPosition)
+	return ok && p.CrewType == CrewTypeFlight
+}
+
+// GetCrewNeedColumn, pozisyonun aircraft_crew_need tablosunda sayıldığı sütunu döndürür (tanımsız veya
+// ihtiyaçta sayılmayan pozisyonlar için boş).
+func GetCrewNeedColumn(flightPosition string) string {
+	p, _ := GetCrewPositionInfo(flightPosition)
+	return p.CrewNeedColumn
+}
@@ -0,0 +1,110 @@
+package models
+
+import "time"
+
+// Görev süresi (duty period) başlangıç/bitişinin türetilme yöntemi
+const (
+	DutyDerivationActivities = "activities"    // İlk aktivitenin duty_start'ı ve son aktivitenin duty_end'i
+	DutyDerivationBriefLegs  = "brief_debrief" // İlk bacak kalkışı - brief, son bacak varışı + debrief
+)
+
+// Denetim izinin kaynağı
+const (
+	ExplanationSourceRecorded     = "recorded"     // Trip hesaplanırken kaydedilen kural ID'leri ve pencere toplamları
+	ExplanationSourceRecalculated = "recalculated" // Kayıt eski veya yeniden hesaplama bekliyor; önceki triplerle yeniden hesaplandı
+)
+
+// FTLExplanation, bir trip'in FTL hesaplamasındaki her değerin nereden geldiğini açıklayan denetim izidir.
+// Trip hesaplanırken kaydedilen değerlerden üretilir; veritabanına yazılmaz.
+type FTLExplanation struct {
+	TripID         string    `json:"trip_id"`
+	CrewMemberID   string    `json:"crew_member_id"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	Source         string    `json:"source"` // ExplanationSourceRecorded veya ExplanationSourceRecalculated
+	Regulation     string    `json:"regulation"`
+	RuleSetVersion int       `json:"rule_set_version"`
+	RuleSetName    string    `json:"rule_set_name"`
+
+	TimeZones         TimeZoneExplanation     `json:"time_zones"`
+	Brief             BriefDebriefExplanation `json:"brief"`
+	Debrief           BriefDebriefExplanation `json:"debrief"`
+	DutyPeriod        DutyPeriodExplanation   `json:"duty_period"`
+	Rest              *RestExplanation        `json:"rest,omitempty"`
+	FDPLimit          *FDPLimitExplanation    `json:"fdp_limit,omitempty"`
+	CumulativeWindows []CumulativeWindowTotal `json:"cumulative_windows"`
+	Violations        []FTLViolation          `json:"violations"`
+}
+
+// TimeZoneExplanation, hesaplamada kullanılan zaman dilimlerini açıklar.
+type TimeZoneExplanation struct {
+	PreferredTZ                string `json:"preferred_tz"` // Görev/dinlenme zamanları ve kümülatif pencereler (kullanıcı tercihi, varsayılan UTC)
+	CrewBaseAirport            string `json:"crew_base_airport"`
+	BaseTZ                     string `json:"base_tz"` // Düzensiz görev, dinlenme yeri ve yorgunluk hesapları
+	AcclimatisationState       string `json:"acclimatisation_state"`
+	AcclimatisationReferenceTZ string `json:"acclimatisation_reference_tz"` // UGS tablosunda kullanılan referans
+}
+
+// BriefDebriefExplanation, brief veya debrief süresinin hangi kural ve girdilerle belirlendiğini açıklar.
+type BriefDebriefExplanation struct {
+	RuleID           int    `json:"rule_id"` // 0 = hiçbir kural eşleşmedi, varsayılan süre uygulandı
+	DefaultApplied   bool   `json:"default_applied"`
+	CrewType         string `json:"crew_type"`
+	DutyType         string `json:"duty_type"`
+	AircraftType     string `json:"aircraft_type"`
+	DutyStartAirport string `json:"duty_start_airport"`
+	DurationMin      int    `json:"duration_min"`
+}
+
+// DutyPeriodExplanation, görev ve UGS süresinin türetilmesini açıklar.
+type DutyPeriodExplanation struct {
+	Derivation            string    `json:"derivation"` // DutyDerivationActivities veya DutyDerivationBriefLegs
+	FirstLegDepartureTime time.Time `json:"first_leg_departure_time"`
+	LastLegArrivalTime    time.Time `json:"last_leg_arrival_time"`
+	Start                 time.Time `json:"start"`
+	End                   time.Time `json:"end"`
+	DurationMin           int       `json:"duration_min"`
+	FDPDurationMin        int       `json:"fdp_duration_min"` // Görev başlangıcı → son bacak varışı
+	CountedDutyMin        int       `json:"counted_duty_min"` // Kümülatif görev pencerelerine sayılan süre
+	BlockTimeMin          int       `json:"block_time_min"`   // Kümülatif uçuş pencerelerine sayılan süre
+	SectorCount           int       `json:"sector_count"`
+}
+
+// RestExplanation, trip öncesi dinlenmenin ölçümünü ve uygulanan minimumu açıklar.
+type RestExplanation struct {
+	PrevTripID  string    `json:"prev_trip_id"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	DurationMin int       `json:"duration_min"`
+	Location    string    `json:"location"`
+	RequiredMin int       `json:"required_min"`
+}
+
+// FDPLimitExplanation, azami UGS limitinin hangi tablo satırından alındığını ve nasıl düzeltildiğini açıklar.
+// ApplyMaxDailyUGSLimit uyguladığı limiti bu yapıyla trip'e kaydeder (Trip.AppliedFDPLimit).
+type FDPLimitExplanation struct {
+	Table                  string `json:"table"`
+	Row                    string `json:"row"`
+	Sectors                int    `json:"sectors"`
+	Augmented              bool   `json:"augmented"`
+	TableLimitMin          int    `json:"table_limit_min"`
+	SplitDutyExtensionMin  int    `json:"split_duty_extension_min"`
+	StandbyReductionMin    int    `json:"standby_reduction_min"`
+	FinalLimitMin          int    `json:"final_limit_min"`
+	FDPDurationMin         int    `json:"fdp_duration_min"`
+	CommanderDiscretionMin int    `json:"commander_discretion_min"`
+	// Evde yedekten çağrılan triplerde yedek + UGS toplamı ve azami uyanık kalma süresi
+	HomeStandbyAwakeMin      int    `json:"home_standby_awake_min,omitempty"`
+	HomeStandbyAwakeLimitMin int    `json:"home_standby_awake_limit_min,omitempty"`
+	Error                    string `json:"error,omitempty"`
+}
+
+// CumulativeWindowTotal, tek bir kümülatif pencerenin toplamını ve toplama katkı veren tripleri tutar.
+type CumulativeWindowTotal struct {
+	RuleCode            string    `json:"rule_code"`
+	WindowStart         time.Time `json:"window_start"`
+	WindowEnd           time.Time `json:"window_end"`
+	TotalMin            int       `json:"total_min"`
+	LimitMin            int       `json:"limit_min"`
+	Exceeded            bool      `json:"exceeded"`
+	ContributingTripIDs []string  `json:"contributing_trip_ids"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Toplu FTL yeniden hesaplama işi kapsamları
+const (
+	RecalcScopePeriod  = "period"  // actuals.period_month dönemine aktivitesi olan ekip üyeleri
+	RecalcScopeBase    = "base"    // crew_info.base_location değeri verilen ana üs olan ekip üyeleri
+	RecalcScopeFleet   = "fleet"   // crew_info.base_filo değeri verilen filo olan ekip üyeleri
+	RecalcScopeFlagged = "flagged" // Yeniden hesaplanması gereken (recalc_required) trip'i olan ekip üyeleri
+)
+
+// Toplu FTL yeniden hesaplama işi durumları
+const (
+	RecalcJobRunning   = "running"
+	RecalcJobCompleted = "completed"
+	RecalcJobCancelled = "cancelled"
+	RecalcJobFailed    = "failed"
+)
+
+// FTLRecalcJob, arka planda çalışan toplu FTL yeniden hesaplama işinin özetini tutar.
+// JobID, ilerleme bildirimlerinin gönderildiği WebSocket process_id değeriyle aynıdır.
+type FTLRecalcJob struct {
+	bun.BaseModel `bun:"table:ftl_recalc_jobs"`
+
+	JobID           string     `json:"job_id" bun:"job_id,pk"`
+	Scope           string     `json:"scope" bun:"scope,notnull"`             // "period", "base", "fleet", "flagged"
+	ScopeValue      string     `json:"scope_value" bun:"scope_value,notnull"` // Örn: "2024-05", "IST", "B737"
+	Status          string     `json:"status" bun:"status,notnull"`
+	Workers         int        `json:"workers" bun:"workers,notnull,default:0"`
+	TotalCrew       int        `json:"total_crew" bun:"total_crew,notnull,default:0"`
+	CrewProcessed   int        `json:"crew_processed" bun:"crew_processed,notnull,default:0"`
+	TripsSaved      int        `json:"trips_saved" bun:"trips_saved,notnull,default:0"`
+	ViolationsFound int        `json:"violations_found" bun:"violations_found,notnull,default:0"`
+	ErrorCount      int        `json:"error_count" bun:"error_count,notnull,default:0"`
+	Errors          []string   `json:"errors" bun:"errors,type:jsonb,null"` // İlk hatalar (en fazla 100 kayıt)
+	CreatedByUserID int64      `json:"created_by_user_id" bun:"created_by_user_id"`
+	StartedAt       time.Time  `json:"started_at" bun:"started_at,notnull"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty" bun:"finished_at"`
+}
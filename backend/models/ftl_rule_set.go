@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// FTLRuleSet, FTL hesaplamalarında kullanılan kümülatif limitlerin ve minimum dinlenme
+// sürelerinin versiyonlu, yürürlük tarihli bir kopyasını temsil eder.
+// Mevzuat değişikliği veya otorite muafiyeti yeni bir versiyon oluşturulup aktive edilerek uygulanır.
+type FTLRuleSet struct {
+	bun.BaseModel `bun:"table:ftl_rule_sets"`
+
+	DataID        int        `json:"data_id" bun:"data_id,pk,autoincrement"`
+	Version       int        `json:"version" bun:"version,notnull,unique"`        // Otomatik artan versiyon numarası
+	Name          string     `json:"name" bun:"name"`                             // Örn: "SHT-FTL 2024"
+	Description   string     `json:"description" bun:"description"`               // Değişiklik gerekçesi, muafiyet referansı vb.
+	EffectiveFrom time.Time  `json:"effective_from" bun:"effective_from,notnull"` // Yürürlük başlangıcı (dahil)
+	EffectiveTo   *time.Time `json:"effective_to,omitempty" bun:"effective_to"`   // Yürürlük bitişi (hariç), NULL = süresiz
+	IsActive      bool       `json:"is_active" bun:"is_active,notnull,default:false"`
+
+	// Kümülatif görev süresi limitleri (dakika)
+	MaxDuty7DaysMin  int `json:"max_duty_7_days_min" bun:"max_duty_7_days_min,notnull"`
+	MaxDuty14DaysMin int `json:"max_duty_14_days_min" bun:"max_duty_14_days_min,notnull"`
+	MaxDuty28DaysMin int `json:"max_duty_28_days_min" bun:"max_duty_28_days_min,notnull"`
+	MaxDutyYearMin   int `json:"max_duty_year_min" bun:"max_duty_year_min,notnull"`
+
+	// Kümülatif uçuş süresi limitleri (dakika)
+	MaxFlight28DaysMin   int `json:"max_flight_28_days_min" bun:"max_flight_28_days_min,notnull"`
+	MaxFlight12MonthsMin int `json:"max_flight_12_months_min" bun:"max_flight_12_months_min,notnull"`
+	MaxFlightYearMin     int `json:"max_flight_year_min" bun:"max_flight_year_min,notnull"`
+
+	// Minimum dinlenme süreleri (dakika)
+	MinRestHomeBaseMin int `json:"min_rest_home_base_min" bun:"min_rest_home_base_min,notnull"`
+	MinRestAwayMin     int `json:"min_rest_away_min" bun:"min_rest_away_min,notnull"`
+
+	// Düzensiz görev limitleri (ana üs yerel saatine göre)
+	MaxConsecutiveNightDuties   int `json:"max_consecutive_night_duties" bun:"max_consecutive_night_duties,notnull,default:3"`
+	MaxDisruptiveDutiesPerCycle int `json:"max_disruptive_duties_per_cycle" bun:"max_disruptive_duties_per_cycle,notnull,default:4"` // İki uzatılmış dinlenme arası
+
+	ActivatedAt *time.Time `json:"activated_at,omitempty" bun:"activated_at"`
+	CreatedAt   time.Time  `json:"created_at" bun:"created_at,default:current_timestamp"`
+}
+
+// DefaultFTLRuleSet, veritabanında yürürlükte bir kural seti bulunamadığında kullanılan
+// SHT-FTL varsayılan limitlerini döndürür. Version 0, kaydedilmemiş yerleşik seti belirtir.
+func DefaultFTLRuleSet() FTLRuleSet {
+	return FTLRuleSet{
+		Version:              0,
+		Name:                 "SHT-FTL (yerleşik varsayılan)",
+		EffectiveFrom:        time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaxDuty7DaysMin:      60 * 60,
+		MaxDuty14DaysMin:     110 * 60,
+		MaxDuty28DaysMin:     190 * 60,
+		MaxDutyYearMin:       2000 * 60,
+		MaxFlight28DaysMin:   100 * 60,
+		MaxFlight12MonthsMin: 1000 * 60,
+		MaxFlightYearMin:     900 * 60,
+		MinRestHomeBaseMin:   12 * 60,
+		MinRestAwayMin:       10 * 60,
+
+		MaxConsecutiveNightDuties:   3,
+		MaxDisruptiveDutiesPerCycle: 4,
+	}
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// FTL ihlal kural kodları
+const (
+	RuleMinRestPeriodViolated             = "MinRestPeriodViolated"
+	RuleAwayRestSleepOpportunityViolated  = "AwayRestSleepOpportunityViolated"
+	RuleTimeZoneRecoveryRestViolated      = "TimeZoneRecoveryRestViolated"
+	RuleMaxDutyPeriod7DaysExceeded        = "MaxDutyPeriod7DaysExceeded"
+	RuleMaxDutyPeriod14DaysExceeded       = "MaxDutyPeriod14DaysExceeded"
+	RuleMaxDutyPeriod28DaysExceeded       = "MaxDutyPeriod28DaysExceeded"
+	RuleMaxDutyPeriodYearExceeded         = "MaxDutyPeriodYearExceeded"
+	RuleMaxFlightTime28DaysExceeded       = "MaxFlightTime28DaysExceeded"
+	RuleMaxFlightTime12MonthsExceeded     = "MaxFlightTime12MonthsExceeded"
+	RuleMaxFlightTimeYearExceeded         = "MaxFlightTimeYearExceeded"
+	RuleMaxDailyUGSLimitViolated          = "MaxDailyUGSLimitViolated"
+	RuleMaxDailyUGSLimitError             = "MaxDailyUGSLimitError"
+	RuleMaxHomeStandbyAwakeTimeExceeded   = "MaxHomeStandbyAwakeTimeExceeded"
+	RuleExtendedRecoveryRestMissing       = "ExtendedRecoveryRestMissing"
+	RuleExtendedRecoveryRestNotExtended   = "ExtendedRecoveryRestNotExtended"
+	RuleMaxConsecutiveNightDutiesExceeded = "MaxConsecutiveNightDutiesExceeded"
+	RuleMaxDisruptiveDutiesExceeded       = "MaxDisruptiveDutiesExceeded"
+	RuleCommandersDiscretionUsed          = "CommandersDiscretionUsed"
+	RuleOffDayEntitlementShortfall        = "OffDayEntitlementShortfall"
+	RuleOffDayDistributionNotMet          = "OffDayDistributionNotMet"
+)
+
+// minimumRuleCodes, ölçülen değerin limitin altına düşmesiyle ihlal oluşan (asgari dinlenme, gece, boş gün vb.)
+// kurallardır; diğer kurallarda ihlal limitin aşılmasıdır.
+var minimumRuleCodes = map[string]bool{
+	RuleMinRestPeriodViolated:            true,
+	RuleAwayRestSleepOpportunityViolated: true,
+	RuleTimeZoneRecoveryRestViolated:     true,
+	RuleExtendedRecoveryRestNotExtended:  true,
+	RuleOffDayEntitlementShortfall:       true,
+	RuleOffDayDistributionNotMet:         true,
+}
+
+// IsMinimumRule, kuralın limiti bir alt sınır olup olmadığını döndürür.
+func IsMinimumRule(ruleCode string) bool {
+	return minimumRuleCodes[ruleCode]
+}
+
+// İhlal önem dereceleri
+const (
+	SeverityViolation  = "violation"  // Yasal limit aşımı
+	SeverityWarning    = "warning"    // Limit aşılmadı ancak dikkat gerektiriyor
+	SeverityError      = "error"      // Hesaplama yapılamadı (eksik tablo satırı vb.)
+	SeverityDiscretion = "discretion" // Limit, kayıtlı kaptan takdiri kapsamında aşıldı (ihlal sayılmaz)
+)
+
+// İhlal ölçü birimleri
+const (
+	UnitMinutes = "min"
+	UnitCount   = "count"
+)
+
+// FTLViolation, bir trip için tespit edilen tek bir FTL ihlalini dilden bağımsız, yapısal olarak tanımlar.
+// Trip.FTLViolations içinde JSONB olarak saklanır; metin üretimi istemcinin sorumluluğundadır.
+type FTLViolation struct {
+	RuleCode            string    `json:"rule_code"`
+	Severity            string    `json:"severity"`
+	WindowStart         time.Time `json:"window_start"`
+	WindowEnd           time.Time `json:"window_end"`
+	MeasuredValue       float64   `json:"measured_value"`
+	Limit               float64   `json:"limit"`
+	Unit                string    `json:"unit"`
+	ContributingTripIDs []string  `json:"contributing_trip_ids"`
+	Details             string    `json:"details,omitempty"` // Yalnızca SeverityError için teknik açıklama
+}
+
+// Excess, ölçülen değerin limiti ihlal yönünde ne kadar geçtiğini döndürür: azami kurallarda ölçülen - limit,
+// asgari kurallarda limit - ölçülen.
+func (v FTLViolation) Excess() float64 {
+	if IsMinimumRule(v.RuleCode) {
+		return v.Limit - v.MeasuredValue
+	}
+	return v.MeasuredValue - v.Limit
+}
+
+// UnmarshalJSON, eski sürümlerde "KuralKodu: açıklama" biçiminde metin olarak saklanmış
+// ihlalleri de okuyabilmek için özelleştirilmiştir.
+func (v *FTLViolation) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		code, details, _ := strings.Cut(legacy, ":")
+		*v = FTLViolation{
+			RuleCode: strings.TrimSpace(code),
+			Severity: SeverityViolation,
+			Details:  strings.TrimSpace(details),
+		}
+		return nil
+	}
+
+	type plain FTLViolation
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*v = FTLViolation(p)
+	return nil
+}
+
+// TripViolation, ihlal listeleme/raporlama uç noktaları için bir ihlali ait olduğu trip ve ekip bilgisiyle birlikte taşır.
+// Boş gün uygunluğu ihlallerinde TripID boştur ve ihlal PeriodMonth dönemine aittir.
+type TripViolation struct {
+	TripID         string `json:"trip_id"`
+	CrewMemberID   string `json:"crew_member_id"`
+	RuleSetVersion int    `json:"rule_set_version"`
+	PeriodMonth    string `json:"period_month,omitempty"`
+	FTLViolation
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Headroom pencere türleri
+const (
+	HeadroomDuty   = "duty"   // Kümülatif görev süresi
+	HeadroomFlight = "flight" // Kümülatif uçuş (blok) süresi
+)
+
+// WindowHeadroom, tek bir kümülatif pencerede kullanılan ve kalan süreyi tutar.
+type WindowHeadroom struct {
+	Kind         string    `json:"kind"`   // HeadroomDuty, HeadroomFlight
+	Window       string    `json:"window"` // "7d", "14d", "28d", "12m", "year"
+	RuleCode     string    `json:"rule_code"`
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
+	UsedMin      int       `json:"used_min"`
+	LimitMin     int       `json:"limit_min"`
+	RemainingMin int       `json:"remaining_min"` // Limit aşılmışsa 0
+}
+
+// CrewHeadroom, bir ekip üyesinin verilen anda FTL pencerelerinde kalan süresini ve son görevinden sonraki
+// en erken yasal raporlama zamanını özetler. Kayıtlı trip hesaplamaları kullanılır.
+type CrewHeadroom struct {
+	CrewMemberID string           `json:"crew_member_id"`
+	At           time.Time        `json:"at"`
+	Regulation   string           `json:"regulation"`
+	Windows      []WindowHeadroom `json:"windows"`
+
+	// Tüm görev / uçuş pencerelerindeki en düşük kalan süre (sıralamada kullanılır)
+	MinRemainingDutyMin   int `json:"min_remaining_duty_min"`
+	MinRemainingFlightMin int `json:"min_remaining_flight_min"`
+
+	// Son görev ve ondan sonraki minimum dinlenme; sonraki görevin son görevin bittiği meydanda başlayacağı varsayılır
+	LastDutyTripID     string     `json:"last_duty_trip_id,omitempty"`
+	LastDutyEnd        *time.Time `json:"last_duty_end,omitempty"`
+	RestLocation       string     `json:"rest_location,omitempty"`
+	MinRestRequiredMin int        `json:"min_rest_required_min"`
+	EarliestReportTime *time.Time `json:"earliest_report_time,omitempty"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/uptrace/bun"
+)
+
+// Boş gün hesabında kullanılan aktivite kodları (frontend rowRules.ts ile aynı liste).
+// Çalışma dışı kodlar günü çalışılmış saymaz; boş gün kodları günü boş gün sayar.
+var (
+	nonWorkingActivityCodes = map[string]bool{
+		"IHI": true, "IMZ": true, "III": true, "UHK": true, "IHK": true, "UDM": true, "IUS": true, "IPR": true,
+	}
+	offDayActivityCodes = map[string]bool{
+		"IHI": true, "IMZ": true, "III": true, "UHK": true, "IHK": true, "UDM": true, "IUS": true, "IPR": true,
+		"IAC": true, "IAV": true, "IBB": true, "IBC": true, "IBG": true, "IBE": true, "IBI": true, "IBM": true,
+		"IBU": true, "IBV": true, "IBY": true, "IOZ": true,
+	}
+)
+
+// IsNonWorkingActivityCode, aktivite kodunun günü çalışılmış saymayan bir kod olup olmadığını döndürür.
+func IsNonWorkingActivityCode(code string) bool {
+	return nonWorkingActivityCodes[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+// IsOffDayActivityCode, aktivite kodunun boş gün (izin, tatil vb.) sayılıp sayılmadığını döndürür.
+func IsOffDayActivityCode(code string) bool {
+	return offDayActivityCodes[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+// ParseOffDayDistribution, off_day_table.distribution metnindeki sayıları ardışık boş gün blokları olarak okur
+// (örn. "2+2+1" -> [2 2 1]). Metinde sayı yoksa nil döner ve dağılım kontrol edilmez.
+func ParseOffDayDistribution(distribution string) []int {
+	var blocks []int
+	for _, field := range strings.FieldsFunc(distribution, func(r rune) bool { return !unicode.IsDigit(r) }) {
+		if n, err := strconv.Atoi(field); err == nil && n > 0 {
+			blocks = append(blocks, n)
+		}
+	}
+	return blocks
+}
+
+// OffDayCompliance, bir ekip üyesinin bir dönemdeki (takvim ayı, ana üs yerel saati) çalışılan/boş gün sayılarını,
+// off_day_table'dan eşleşen hak edişi ve dağılım kontrolünün sonucunu tutar. Eksik boş günler FTL ihlalleriyle
+// aynı yapıda Violations alanında saklanır.
+type OffDayCompliance struct {
+	bun.BaseModel `bun:"table:off_day_compliance"`
+
+	DataID       int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	CrewMemberID string    `json:"crew_member_id" bun:"crew_member_id,notnull,unique:off_day_compliance_period"`
+	PeriodMonth  string    `json:"period_month" bun:"period_month,notnull,unique:off_day_compliance_period"` // "YYYY-MM"
+	PeriodStart  time.Time `json:"period_start" bun:"period_start,notnull"`
+	PeriodEnd    time.Time `json:"period_end" bun:"period_end,notnull"`
+	BaseLocation string    `json:"base_location,omitempty" bun:"base_location"`
+
+	WorkedDays          int    `json:"worked_days" bun:"worked_days"`
+	OffDays             int    `json:"off_days" bun:"off_days"`
+	EntitlementWorkDays int    `json:"entitlement_work_days" bun:"entitlement_work_days"` // Eşleşen off_day_table satırının work_days değeri
+	OffDayEntitlement   int    `json:"off_day_entitlement" bun:"off_day_entitlement"`
+	Distribution        string `json:"distribution,omitempty" bun:"distribution"`
+	RequiredBlocks      []int  `json:"required_blocks" bun:"required_blocks,type:jsonb,null"` // Dağılımdan okunan ardışık boş gün blokları
+	OffDayBlocks        []int  `json:"off_day_blocks" bun:"off_day_blocks,type:jsonb,null"`   // Dönemdeki ardışık boş gün blokları (kronolojik)
+	ShortfallDays       int    `json:"shortfall_days" bun:"shortfall_days"`
+	DistributionMet     bool   `json:"distribution_met" bun:"distribution_met"`
+
+	Violations []FTLViolation `json:"violations" bun:"violations,type:jsonb,null"`
+	CheckedAt  time.Time      `json:"checked_at" bun:"checked_at,default:current_timestamp"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// FTL mevzuat çerçeveleri (trip sonucunda uygulanan çerçeve olarak kaydedilir)
+const (
+	RegulationSHTFTL  = "SHT-FTL"      // Türkiye SHT-FTL (Tablo-5, versiyonlu kural setleri); varsayılan
+	RegulationEASA    = "EASA-ORO.FTL" // EASA ORO.FTL / CS FTL.1
+	RegulationFAA117  = "FAA-117"      // FAA 14 CFR Part 117
+	DefaultRegulation = RegulationSHTFTL
+)
+
+// Mevzuat atamasının konusu
+const (
+	RegulationSubjectCrew         = "crew"         // crew_info.person_id
+	RegulationSubjectRegistration = "registration" // Uçak tescili (actuals.plane_tail_name)
+)
+
+// RegulationAssignment, bir ekip üyesine veya uçak tesciline uygulanacak FTL mevzuat çerçevesini tutar.
+// Trip'in FLT sektörlerindeki tescile yapılan atama, ekip üyesine yapılan atamadan önceliklidir;
+// hiçbir atama yoksa DefaultRegulation uygulanır.
+type RegulationAssignment struct {
+	bun.BaseModel `bun:"table:regulation_assignments"`
+
+	DataID       int       `json:"data_id" bun:"data_id,pk,autoincrement"`
+	SubjectType  string    `json:"subject_type" bun:"subject_type,notnull,unique:regulation_subject"`   // "crew", "registration"
+	SubjectValue string    `json:"subject_value" bun:"subject_value,notnull,unique:regulation_subject"` // Örn: "12345", "TC-JFK"
+	Regulation   string    `json:"regulation" bun:"regulation,notnull"`
+	Note         string    `json:"note,omitempty" bun:"note"` // Örn: wet-lease sözleşme referansı
+	UpdatedAt    time.Time `json:"updated_at" bun:"updated_at,default:current_timestamp"`
+}
+
+// IsValidRegulation, verilen kodun tanımlı bir mevzuat çerçevesi olup olmadığını döndürür.
+func IsValidRegulation(code string) bool {
+	switch code {
+	case RegulationSHTFTL, RegulationEASA, RegulationFAA117:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Bölünmüş görev (split duty) mola tipleri
+const (
+	SplitDutyBreakAccommodation    = "accommodation"     // Otel/konaklama tesisi (≥ 6 saat veya WOCL'ye denk gelen mola)
+	SplitDutyBreakNonAccommodation = "non_accommodation" // Uygun dinlenme odası (6 saatten kısa, WOCL dışı mola)
+)
+
+// Bölünmüş görev karar kodları
+const (
+	SplitDutyApplied              = "applied"                 // UGS limiti mola süresine göre uzatıldı
+	SplitDutyBreakTooShort        = "break_too_short"         // Yer hizmetleri düşüldükten sonra mola 3 saatten kısa
+	SplitDutyNotWithAugmentedCrew = "not_with_augmented_crew" // Uzatılmış ekip limitiyle birlikte uygulanmaz
+)
+
+// SplitDutyDecision, trip içindeki en uzun yer molasının bölünmüş görev değerlendirmesini tutar.
+// Trip.SplitDuty içinde JSONB olarak saklanır; trip'te aday mola yoksa alan boş kalır.
+type SplitDutyDecision struct {
+	Decision          string    `json:"decision"`
+	BreakStart        time.Time `json:"break_start"`
+	BreakEnd          time.Time `json:"break_end"`
+	BreakDurationMin  int       `json:"break_duration_min"`  // İki aktivite arasındaki toplam boşluk
+	EffectiveBreakMin int       `json:"effective_break_min"` // Uçuş sonrası/öncesi görevler düşüldükten sonraki mola
+	BreakType         string    `json:"break_type"`
+	EncroachesWOCL    bool      `json:"encroaches_wocl"`
+	ExtensionMin      int       `json:"extension_min"` // UGS limitine eklenen süre
+}
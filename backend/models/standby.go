@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"sync"
+)
+
+// Yedek (standby) ve rezerv görev tipleri; activity_codes.standby_type sütununda saklanır
+const (
+	StandbyTypeAirport = "airport_standby" // Havalimanında yedek
+	StandbyTypeHome    = "home_standby"    // Evde/otelde yedek
+	StandbyTypeReserve = "reserve"         // Rezerv (görev ataması önceden bildirilir)
+)
+
+// Yedek ve rezerv aktiviteler için GetDutyTypeFromActual'ın döndürdüğü görev tipleri
+const (
+	DutyTypeAirportStandby = "Havalimanı Yedek"
+	DutyTypeHomeStandby    = "Evde Yedek"
+	DutyTypeReserve        = "Rezerv"
+)
+
+// Aktivite kodu → yedek tipi eşlemesi; yalnızca activity_codes.standby_type sütunundan yüklenir
+// (RegisterStandbyActivityCodes). Sütunu boş olan kodlar yedek sayılmaz.
+var (
+	standbyActivityCodes   = map[string]string{}
+	standbyActivityCodesMu sync.RWMutex
+)
+
+// IsValidStandbyType, verilen değerin tanımlı bir yedek/rezerv tipi (veya boş) olup olmadığını döndürür.
+func IsValidStandbyType(standbyType string) bool {
+	switch standbyType {
+	case "", StandbyTypeAirport, StandbyTypeHome, StandbyTypeReserve:
+		return true
+	}
+	return false
+}
+
+// RegisterStandbyActivityCodes, activity_codes tablosundan okunan aktivite kodu → yedek tipi
+// tanımlarını etkin eşlemenin yerine koyar.
+func RegisterStandbyActivityCodes(codes map[string]string) {
+	merged := make(map[string]string, len(codes))
+	for code, standbyType := range codes {
+		if standbyType != "" && IsValidStandbyType(standbyType) {
+			merged[strings.ToUpper(strings.TrimSpace(code))] = standbyType
+		}
+	}
+
+	standbyActivityCodesMu.Lock()
+	standbyActivityCodes = merged
+	standbyActivityCodesMu.Unlock()
+}
+
+// GetStandbyTypeFromActivityCode, aktivite kodunun yedek/rezerv tipini döndürür; yedek değilse boş döner.
+func GetStandbyTypeFromActivityCode(activityCode string) string {
+	standbyActivityCodesMu.RLock()
+	defer standbyActivityCodesMu.RUnlock()
+	return standbyActivityCodes[strings.ToUpper(strings.TrimSpace(activityCode))]
+}
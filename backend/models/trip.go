@@ -37,13 +37,82 @@ type Trip struct {
 	// Hesaplanan Uçuş Görev Süresi (UGS - Flight Duty Period) Detayları
 	CalculatedFlightDutyPeriodDurationMin int `json:"calculated_flight_duty_period_duration_min" bun:"calculated_flight_duty_period_duration_min"`
 
+	// Blok süresine dayalı uçuş süresi: DH hariç FLT sektörlerinin toplam blok süresi, sektör sayısı ve sektör dökümü.
+	// 28 gün, 12 ay ve takvim yılı uçuş süresi limitleri bu toplam üzerinden hesaplanır.
+	BlockTimeMin int               `json:"block_time_min" bun:"block_time_min,notnull,default:0"`
+	SectorCount  int               `json:"sector_count" bun:"sector_count,notnull,default:0"`
+	Sectors      []SectorBlockTime `json:"sectors" bun:"sectors,type:jsonb,null"`
+
 	// Hesaplanan Dinlenme Süresi Detayları (ÖNCEKİ görev ile bu görev arasındaki)
 	CalculatedRestPeriodStart       time.Time `json:"calculated_rest_period_start,omitempty" bun:"calculated_rest_period_start,null"`
 	CalculatedRestPeriodEnd         time.Time `json:"calculated_rest_period_end,omitempty" bun:"calculated_rest_period_end,null"`
 	CalculatedRestPeriodDurationMin int       `json:"calculated_rest_period_duration_min,omitempty" bun:"calculated_rest_period_duration_min,null"`
 
-	// FTL İhlalleri (birden fazla ihlal olabilir), JSONB olarak saklanacak
-	FTLViolations []string `json:"ftl_violations" bun:"ftl_violations,type:jsonb,null"`
+	// Dinlenmenin yeri (ana üs / ana üs dışı konaklama) ve uygulanan minimum: max(önceki görev süresi, kural seti minimumu)
+	RestLocation       string `json:"rest_location,omitempty" bun:"rest_location"`
+	MinRestRequiredMin int    `json:"min_rest_required_min" bun:"min_rest_required_min,notnull,default:0"`
+
+	// Saat dilimi geçişi: aktivite meydanlarının ana üsse göre en büyük farkı, rotasyon başından beri ana üsten
+	// uzakta geçen süre ve (önceki trip rotasyonu ana üste kapattıysa) bu trip'ten önce gereken yerel gece sayısı
+	MaxTimeZoneDiffMin     int `json:"max_time_zone_diff_min" bun:"max_time_zone_diff_min,notnull,default:0"`
+	TimeAwayFromBaseMin    int `json:"time_away_from_base_min" bun:"time_away_from_base_min,notnull,default:0"`
+	RequiredRecoveryNights int `json:"required_recovery_nights" bun:"required_recovery_nights,notnull,default:0"`
+
+	// Biyomatematik yorgunluk skoru: görev süresindeki en yüksek KSS eşdeğeri (1-9) ve zamanı
+	FatigueScore  float64    `json:"fatigue_score" bun:"fatigue_score,notnull,default:0"`
+	FatiguePeakAt *time.Time `json:"fatigue_peak_at,omitempty" bun:"fatigue_peak_at,nullzero"`
+
+	// FTL İhlalleri (birden fazla ihlal olabilir), yapısal kayıtlar olarak JSONB içinde saklanır
+	FTLViolations []FTLViolation `json:"ftl_violations" bun:"ftl_violations,type:jsonb,null"`
+
+	// Hesaplamada eşleşen brief/debrief kuralları (0 = kural eşleşmedi, varsayılan süre) ve kümülatif pencere
+	// toplamları; denetim izi trip yeniden hesaplanmadan bu kayıtlardan üretilir
+	BriefRuleID       int                     `json:"brief_rule_id" bun:"brief_rule_id,notnull,default:0"`
+	DebriefRuleID     int                     `json:"debrief_rule_id" bun:"debrief_rule_id,notnull,default:0"`
+	CumulativeWindows []CumulativeWindowTotal `json:"cumulative_windows,omitempty" bun:"cumulative_windows,type:jsonb,null"`
+
+	// Görev başlangıcındaki aklimatizasyon durumu ve UGS tablosunda kullanılan referans zaman dilimi
+	AcclimatisationState       string `json:"acclimatisation_state" bun:"acclimatisation_state"`               // "acclimatised_reference", "acclimatised_local", "unknown"
+	AcclimatisationReferenceTZ string `json:"acclimatisation_reference_tz" bun:"acclimatisation_reference_tz"` // Örn: "Europe/Istanbul"
+
+	// Uzatılmış ekip bilgisi: sektörlerdeki en düşük kokpit ekibi sayısı ve uçağın dinlenme tesisi sınıfı (0 = yok)
+	FlightCrewComplement int `json:"flight_crew_complement" bun:"flight_crew_complement,notnull,default:0"`
+	RestFacilityClass    int `json:"rest_facility_class" bun:"rest_facility_class,notnull,default:0"`
+
+	// Bölünmüş görev (split duty) değerlendirmesi; trip içinde aday yer molası yoksa boş kalır
+	SplitDuty *SplitDutyDecision `json:"split_duty,omitempty" bun:"split_duty,type:jsonb,null"`
+
+	// Yedek/rezerv görev bilgisi: trip içindeki veya trip'in çağrıldığı yedek görev
+	StandbyType            string `json:"standby_type,omitempty" bun:"standby_type"` // "airport_standby", "home_standby", "reserve"
+	StandbyDurationMin     int    `json:"standby_duration_min" bun:"standby_duration_min,notnull,default:0"`
+	StandbyCalledOut       bool   `json:"standby_called_out" bun:"standby_called_out,notnull,default:false"`
+	StandbyFDPReductionMin int    `json:"standby_fdp_reduction_min" bun:"standby_fdp_reduction_min,notnull,default:0"` // UGS limitinden düşülen süre
+
+	// Kümülatif görev pencerelerine sayılan süre (yedek süresi tipine göre kısmen sayılır)
+	CountedDutyMin int `json:"counted_duty_min" bun:"counted_duty_min,notnull,default:0"`
+
+	// Düzensiz görev işaretleri (ana üs yerel saatine göre); roster zaman çizelgesinde vurgulanır
+	EarlyStart     bool `json:"early_start" bun:"early_start,notnull,default:false"`
+	LateFinish     bool `json:"late_finish" bun:"late_finish,notnull,default:false"`
+	NightDuty      bool `json:"night_duty" bun:"night_duty,notnull,default:false"`
+	EncroachesWOCL bool `json:"encroaches_wocl" bun:"encroaches_wocl,notnull,default:false"`
+
+	// Azami UGS limitini aşan ve kayıtlı kaptan takdiriyle karşılanan süre (dakika)
+	CommanderDiscretionMin int `json:"commander_discretion_min" bun:"commander_discretion_min,notnull,default:0"`
+
+	// Uygulanan azami UGS limiti: tablo satırı, bölünmüş görev/yedek düzeltmeleri ve karşılaştırılan son limit
+	AppliedFDPLimit *FDPLimitExplanation `json:"applied_fdp_limit,omitempty" bun:"applied_fdp_limit,type:jsonb,null"`
+
+	// İhlalleri üreten FTL kural seti versiyonu (0 = yerleşik varsayılan limitler)
+	RuleSetVersion int `json:"rule_set_version" bun:"rule_set_version,notnull,default:0"`
+
+	// Uygulanan mevzuat çerçevesi (ör. "SHT-FTL", "EASA-ORO.FTL", "FAA-117")
+	Regulation string `json:"regulation" bun:"regulation"`
+
+	// Trip hesaplandıktan sonra etkileyen bir brief/debrief kuralı değişti; yeniden hesaplanana kadar true kalır
+	RecalcRequired bool `json:"recalc_required" bun:"recalc_required,notnull,default:false"`
+	// Son işaretlenme zamanı; bu zamandan önce başlamış bir hesaplamanın kaydı işareti silmez
+	RecalcFlaggedAt time.Time `json:"recalc_flagged_at" bun:"recalc_flagged_at,nullzero"`
 
 	// Oluşturulma ve Güncellenme zamanları (bun.BaseModel'den gelmiyorsa)
 	LastCalculatedAt time.Time `json:"last_calculated_at" bun:"last_calculated_at"`
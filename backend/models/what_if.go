@@ -0,0 +1,46 @@
+package models
+
+// What-if değişiklik tipleri
+const (
+	WhatIfAdd    = "add"    // Yeni trip ekle (activities zorunlu)
+	WhatIfRemove = "remove" // Mevcut trip'i kaldır
+	WhatIfMove   = "move"   // Mevcut trip'i from_crew_member_id'den crew_member_id'ye taşı
+	WhatIfShift  = "shift"  // Mevcut trip'in tüm zamanlarını shift_min kadar kaydır
+)
+
+// What-if sonucunda trip'in durumu
+const (
+	WhatIfTripUnchanged = "unchanged"
+	WhatIfTripAdded     = "added"
+	WhatIfTripRemoved   = "removed"
+	WhatIfTripModified  = "modified"
+)
+
+// WhatIfChange, planlamacının denemek istediği tek bir program değişikliğini tanımlar.
+type WhatIfChange struct {
+	Action           string   `json:"action"`
+	CrewMemberID     string   `json:"crew_member_id"`
+	FromCrewMemberID string   `json:"from_crew_member_id,omitempty"` // Yalnızca "move" için
+	TripID           string   `json:"trip_id"`
+	Activities       []Actual `json:"activities,omitempty"` // Yalnızca "add" için
+	ShiftMin         int      `json:"shift_min,omitempty"`  // Yalnızca "shift" için (negatif = öne alma)
+}
+
+// WhatIfTripResult, bir trip'in mevcut ve önerilen programdaki ihlallerini karşılaştırır.
+// NewViolations önerilen programda ortaya çıkan, ResolvedViolations ise ortadan kalkan ihlallerdir (kural koduna göre).
+type WhatIfTripResult struct {
+	TripID             string         `json:"trip_id"`
+	Status             string         `json:"status"`
+	CurrentViolations  []FTLViolation `json:"current_violations"`
+	ProposedViolations []FTLViolation `json:"proposed_violations"`
+	NewViolations      []FTLViolation `json:"new_violations"`
+	ResolvedViolations []FTLViolation `json:"resolved_violations"`
+}
+
+// WhatIfCrewResult, bir ekip üyesi için what-if değerlendirmesinin özetidir.
+type WhatIfCrewResult struct {
+	CrewMemberID           string             `json:"crew_member_id"`
+	CurrentViolationCount  int                `json:"current_violation_count"`
+	ProposedViolationCount int                `json:"proposed_violation_count"`
+	Trips                  []WhatIfTripResult `json:"trips"`
+}
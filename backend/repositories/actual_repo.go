@@ -122,3 +122,146 @@ func (r *ActualRepository) SearchActuals(ctx context.Context, filters map[string
 	}
 	return actuals, nil
 }
+
+// 🔹 6. Verilen uçuş sektörlerinde (flight_no + departure_port + departure_time) görev alan
+// tüm ekip üyelerinin FLT kayıtlarını getirir. Uzatılmış ekip tespiti için kullanılır.
+func (r *ActualRepository) GetSectorCrewActuals(ctx context.Context, sectors []models.Actual) ([]models.Actual, error) {
+	var actuals []models.Actual
+	if len(sectors) == 0 {
+		return actuals, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&actuals).
+		Column("person_id", "flight_no", "departure_port", "departure_time", "flight_position", "group_code").
+		Where("group_code = ?", "FLT").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, s := range sectors {
+				q = q.WhereOr("(flight_no = ? AND departure_port = ? AND departure_time = ?)", s.FlightNo, s.DeparturePort, s.DepartureTime)
+			}
+			return q
+		}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sektör ekip kayıtları alınamadı: %w", err)
+	}
+	return actuals, nil
+}
+
+// 🔹 Verilen dönemde (period_month) aktivitesi olan ekip üyelerinin person_id listesini getirir
+func (r *ActualRepository) GetPersonIDsByPeriodMonth(ctx context.Context, periodMonth string) ([]string, error) {
+	var personIDs []string
+	err := r.db.NewSelect().
+		Model((*models.Actual)(nil)).
+		ColumnExpr("DISTINCT person_id").
+		Where("period_month = ?", periodMonth).
+		Where("person_id <> ''").
+		Order("person_id ASC").
+		Scan(ctx, &personIDs)
+	if err != nil {
+		return nil, fmt.Errorf("dönem ekip üyeleri alınamadı (period_month=%s): %w", periodMonth, err)
+	}
+	return personIDs, nil
+}
+
+// 🔹 Belirli person_id için [from, to) aralığıyla kesişen aktiviteleri (kalkış/iniş zamanına göre) getirir
+func (r *ActualRepository) GetActualsInRange(ctx context.Context, personID string, from, to time.Time) ([]models.Actual, error) {
+	var actuals []models.Actual
+	err := r.db.NewSelect().
+		Model(&actuals).
+		Where("person_id = ?", personID).
+		Where("departure_time < ?", to).
+		Where("arrival_time >= ?", from).
+		Order("departure_time ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aralıktaki actuals alınamadı (person_id=%s): %w", personID, err)
+	}
+	return actuals, nil
+}
+
+// 🔹 [from, to) aralığında aktivitesi olan ekip üyelerinin person_id listesini getirir
+func (r *ActualRepository) GetPersonIDsInRange(ctx context.Context, from, to time.Time) ([]string, error) {
+	var personIDs []string
+	err := r.db.NewSelect().
+		Model((*models.Actual)(nil)).
+		ColumnExpr("DISTINCT person_id").
+		Where("departure_time < ?", to).
+		Where("arrival_time >= ?", from).
+		Where("person_id <> ''").
+		Order("person_id ASC").
+		Scan(ctx, &personIDs)
+	if err != nil {
+		return nil, fmt.Errorf("aralıktaki ekip üyeleri alınamadı: %w", err)
+	}
+	return personIDs, nil
+}
+
+// UnmappedCmsType, actuals'ta geçen ancak aircraft_types tablosunda tanımlı olmayan bir CMS uçak tipini özetler.
+type UnmappedCmsType struct {
+	PlaneCmsType  string    `json:"plane_cms_type" bun:"plane_cms_type"`
+	ActivityCount int       `json:"activity_count" bun:"activity_count"`
+	TripCount     int       `json:"trip_count" bun:"trip_count"`
+	CrewCount     int       `json:"crew_count" bun:"crew_count"`
+	FirstSeen     time.Time `json:"first_seen" bun:"first_seen"`
+	LastSeen      time.Time `json:"last_seen" bun:"last_seen"`
+}
+
+// 🔹 actuals'ta geçen ancak aircraft_types tablosunda tanımlı olmayan CMS tiplerini getirir (periodMonth boş değilse yalnızca o dönem)
+func (r *ActualRepository) GetUnmappedCmsTypes(ctx context.Context, periodMonth string) ([]UnmappedCmsType, error) {
+	var rows []UnmappedCmsType
+	query := r.db.NewSelect().
+		Model((*models.Actual)(nil)).
+		ColumnExpr("plane_cms_type").
+		ColumnExpr("COUNT(*) AS activity_count").
+		ColumnExpr("COUNT(DISTINCT NULLIF(trip_id, '')) AS trip_count").
+		ColumnExpr("COUNT(DISTINCT person_id) AS crew_count").
+		ColumnExpr("MIN(departure_time) AS first_seen").
+		ColumnExpr("MAX(departure_time) AS last_seen").
+		Where("COALESCE(plane_cms_type, '') <> ''").
+		Where("UPPER(plane_cms_type) NOT IN (SELECT cms_type FROM aircraft_types)").
+		Group("plane_cms_type").
+		OrderExpr("activity_count DESC, plane_cms_type ASC")
+	if periodMonth != "" {
+		query = query.Where("period_month = ?", periodMonth)
+	}
+	if err := query.Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("tanımsız CMS uçak tipleri alınamadı: %w", err)
+	}
+	return rows, nil
+}
+
+// UnmappedFlightPosition, actuals'ta geçen ancak crew_positions tablosunda tanımlı olmayan bir uçuş pozisyonunu özetler.
+type UnmappedFlightPosition struct {
+	FlightPosition string    `json:"flight_position" bun:"flight_position"`
+	ActivityCount  int       `json:"activity_count" bun:"activity_count"`
+	TripCount      int       `json:"trip_count" bun:"trip_count"`
+	CrewCount      int       `json:"crew_count" bun:"crew_count"`
+	FirstSeen      time.Time `json:"first_seen" bun:"first_seen"`
+	LastSeen       time.Time `json:"last_seen" bun:"last_seen"`
+}
+
+// 🔹 actuals'ta geçen ancak crew_positions tablosunda tanımlı olmayan uçuş pozisyonlarını getirir (DH hariç; periodMonth boş değilse yalnızca o dönem)
+func (r *ActualRepository) GetUnmappedFlightPositions(ctx context.Context, periodMonth string) ([]UnmappedFlightPosition, error) {
+	var rows []UnmappedFlightPosition
+	query := r.db.NewSelect().
+		Model((*models.Actual)(nil)).
+		ColumnExpr("flight_position").
+		ColumnExpr("COUNT(*) AS activity_count").
+		ColumnExpr("COUNT(DISTINCT NULLIF(trip_id, '')) AS trip_count").
+		ColumnExpr("COUNT(DISTINCT person_id) AS crew_count").
+		ColumnExpr("MIN(COALESCE(departure_time, duty_start)) AS first_seen").
+		ColumnExpr("MAX(COALESCE(departure_time, duty_start)) AS last_seen").
+		Where("COALESCE(flight_position, '') <> ''").
+		Where("UPPER(flight_position) <> ?", models.DeadheadFlightPosition).
+		Where("UPPER(flight_position) NOT IN (SELECT position_code FROM crew_positions)").
+		Group("flight_position").
+		OrderExpr("activity_count DESC, flight_position ASC")
+	if periodMonth != "" {
+		query = query.Where("period_month = ?", periodMonth)
+	}
+	if err := query.Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("tanımsız uçuş pozisyonları alınamadı: %w", err)
+	}
+	return rows, nil
+}
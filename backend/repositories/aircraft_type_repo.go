@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type AircraftTypeRepository struct {
+	db *bun.DB
+}
+
+func NewAircraftTypeRepository(db *bun.DB) *AircraftTypeRepository {
+	return &AircraftTypeRepository{db: db}
+}
+
+// 🔹 Tüm uçak tiplerini getirir (bodyClass boş değilse yalnızca o gövde sınıfındakiler)
+func (r *AircraftTypeRepository) ListAircraftTypes(ctx context.Context, bodyClass string) ([]models.AircraftType, error) {
+	var types []models.AircraftType
+	query := r.db.NewSelect().
+		Model(&types).
+		Order("cms_type ASC")
+	if bodyClass != "" {
+		query = query.Where("body_class = ?", bodyClass)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("uçak tipleri alınamadı: %w", err)
+	}
+	return types, nil
+}
+
+// 🔹 Uçak tiplerini CMS tipine göre ekler veya günceller
+func (r *AircraftTypeRepository) UpsertAircraftTypes(ctx context.Context, types []models.AircraftType) error {
+	if len(types) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range types {
+		types[i].UpdatedAt = now
+	}
+	_, err := r.db.NewInsert().
+		Model(&types).
+		On("CONFLICT (cms_type) DO UPDATE").
+		Set("body_class = EXCLUDED.body_class").
+		Set("icao_type = EXCLUDED.icao_type").
+		Set("is_cargo = EXCLUDED.is_cargo").
+		Set("rest_facility_class = EXCLUDED.rest_facility_class").
+		Set("description = EXCLUDED.description").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("data_id").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("uçak tipleri kaydedilemedi: %w", err)
+	}
+	return nil
+}
+
+// 🔹 Uçak tipini siler (silinen satır yoksa false döner)
+func (r *AircraftTypeRepository) DeleteAircraftType(ctx context.Context, cmsType string) (bool, error) {
+	res, err := r.db.NewDelete().
+		Model((*models.AircraftType)(nil)).
+		Where("cms_type = ?", cmsType).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("uçak tipi silinemedi (cms_type=%s): %w", cmsType, err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// 🔹 Tüm uçak tipi kayıtlarını siler (import öncesi reset=true için)
+func (r *AircraftTypeRepository) DeleteAllAircraftTypes(ctx context.Context) error {
+	_, err := r.db.NewDelete().
+		Model((*models.AircraftType)(nil)).
+		Where("TRUE").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("uçak tipleri silinemedi: %w", err)
+	}
+	return nil
+}
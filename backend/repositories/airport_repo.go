@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type AirportRepository struct {
+	db *bun.DB
+}
+
+func NewAirportRepository(db *bun.DB) *AirportRepository {
+	return &AirportRepository{db: db}
+}
+
+// 🔹 Tüm meydanları IATA koduna göre sıralı getirir
+func (r *AirportRepository) ListAirports(ctx context.Context) ([]models.Airport, error) {
+	var airports []models.Airport
+	err := r.db.NewSelect().
+		Model(&airports).
+		Order("iata_code ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("meydanlar alınamadı: %w", err)
+	}
+	return airports, nil
+}
+
+// 🔹 IATA veya ICAO koduyla meydanı getirir (bulunamazsa nil döner)
+func (r *AirportRepository) GetAirportByCode(ctx context.Context, code string) (*models.Airport, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	var airport models.Airport
+	err := r.db.NewSelect().
+		Model(&airport).
+		Where("iata_code = ? OR icao_code = ?", code, code).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("meydan alınamadı (code=%s): %w", code, err)
+	}
+	return &airport, nil
+}
+
+// 🔹 Meydanları IATA koduna göre ekler veya günceller; ana üs bayrağı kalkan meydanların grup eşlemeleri silinir
+func (r *AirportRepository) UpsertAirports(ctx context.Context, airports []models.Airport) error {
+	if len(airports) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range airports {
+		airports[i].UpdatedAt = now
+	}
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(&airports).
+			On("CONFLICT (iata_code) DO UPDATE").
+			Set("icao_code = EXCLUDED.icao_code").
+			Set("name = EXCLUDED.name").
+			Set("city = EXCLUDED.city").
+			Set("country = EXCLUDED.country").
+			Set("time_zone = EXCLUDED.time_zone").
+			Set("latitude = EXCLUDED.latitude").
+			Set("longitude = EXCLUDED.longitude").
+			Set("is_base = EXCLUDED.is_base").
+			Set("updated_at = EXCLUDED.updated_at").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("meydanlar kaydedilemedi: %w", err)
+		}
+
+		// Ana üs bayrağı kaldırılan meydanların ana üs grup eşlemeleri de silinir
+		_, err = tx.NewDelete().
+			Model((*models.CrewBaseAirport)(nil)).
+			Where("airport_code NOT IN (SELECT iata_code FROM airports WHERE is_base = TRUE)").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("ana üs dışı meydanların grup eşlemeleri silinemedi: %w", err)
+		}
+		return nil
+	})
+}
+
+// 🔹 Meydanları filtreleyerek getirir. search IATA/ICAO kodu, ad ve şehirde aranır;
+// sortBy izin verilen sütunlardan biri değilse iata_code kullanılır.
+func (r *AirportRepository) QueryAirports(ctx context.Context, search, country string, baseOnly bool, sortBy, sortOrder string) ([]models.Airport, error) {
+	var airports []models.Airport
+	query := r.db.NewSelect().Model(&airports)
+	if search != "" {
+		s := "%" + strings.ToLower(search) + "%"
+		query = query.Where(
+			"LOWER(iata_code) LIKE ? OR LOWER(COALESCE(icao_code, '')) LIKE ? OR LOWER(COALESCE(name, '')) LIKE ? OR LOWER(COALESCE(city, '')) LIKE ?",
+			s, s, s, s,
+		)
+	}
+	if country != "" {
+		query = query.Where("country = ?", strings.ToUpper(country))
+	}
+	if baseOnly {
+		query = query.Where("is_base = TRUE")
+	}
+
+	allowedSortCols := map[string]bool{"iata_code": true, "icao_code": true, "name": true, "city": true, "country": true, "time_zone": true}
+	if !allowedSortCols[sortBy] {
+		sortBy = "iata_code"
+	}
+	if sortOrder != "desc" {
+		sortOrder = "asc"
+	}
+	query = query.OrderExpr(sortBy + " " + sortOrder)
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("meydan sorgusu başarısız: %w", err)
+	}
+	return airports, nil
+}
+
+// 🔹 Tüm meydan kayıtlarını siler (import öncesi reset=true için)
+func (r *AirportRepository) DeleteAllAirports(ctx context.Context) error {
+	_, err := r.db.NewDelete().
+		Model((*models.Airport)(nil)).
+		Where("TRUE").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("meydanlar silinemedi: %w", err)
+	}
+	return nil
+}
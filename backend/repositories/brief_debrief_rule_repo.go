@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"mini_CMS_Desktop_App/models"
 
@@ -51,3 +53,122 @@ func (r *BriefDebriefRuleRepository) FindMatchingRules(
 	}
 	return rules, nil
 }
+
+// 🔹 ID'ye göre kuralı getirir (bulunamazsa nil döner)
+func (r *BriefDebriefRuleRepository) GetRuleByID(ctx context.Context, id int) (*models.BriefDebriefRule, error) {
+	var rule models.BriefDebriefRule
+	err := r.db.NewSelect().
+		Model(&rule).
+		Where("data_id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("📛 brief/debrief kuralı alınamadı (ID %d): %w", id, err)
+	}
+	return &rule, nil
+}
+
+// 🔹 Kuralla aynı senaryo, uçak tipi, ekip tipi ve meydana sahip, geçerlilik aralığı çakışan başka bir versiyonu getirir (yoksa nil)
+func (r *BriefDebriefRuleRepository) FindOverlappingRule(ctx context.Context, rule *models.BriefDebriefRule) (*models.BriefDebriefRule, error) {
+	return findOverlappingRule(ctx, r.db, rule)
+}
+
+func findOverlappingRule(ctx context.Context, db bun.IDB, rule *models.BriefDebriefRule) (*models.BriefDebriefRule, error) {
+	var other models.BriefDebriefRule
+	err := db.NewSelect().
+		Model(&other).
+		Where("data_id <> ?", rule.DataID).
+		Where("LOWER(scenario_type) = LOWER(?)", rule.ScenarioType).
+		Where("LOWER(aircraft_type) = LOWER(?)", rule.AircraftType).
+		Where("LOWER(crew_type) = LOWER(?)", rule.CrewType).
+		Where("LOWER(duty_start_airport) = LOWER(?)", rule.DutyStartAirport).
+		Where("COALESCE(valid_from, '-infinity'::date) <= COALESCE(?::date, 'infinity'::date)", rule.ValidTo).
+		Where("COALESCE(valid_to, 'infinity'::date) >= COALESCE(?::date, '-infinity'::date)", rule.ValidFrom).
+		OrderExpr("data_id ASC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("📛 çakışan brief/debrief kuralı sorgusu başarısız: %w", err)
+	}
+	return &other, nil
+}
+
+// 🔹 Yeni kural ekler
+func (r *BriefDebriefRuleRepository) CreateRule(ctx context.Context, rule *models.BriefDebriefRule) error {
+	rule.UpdatedAt = time.Now()
+	if _, err := r.db.NewInsert().Model(rule).Returning("data_id").Exec(ctx); err != nil {
+		return fmt.Errorf("📛 brief/debrief kuralı eklenemedi: %w", err)
+	}
+	return nil
+}
+
+// 🔹 Kuralı günceller (güncellenen satır yoksa false döner)
+func (r *BriefDebriefRuleRepository) UpdateRule(ctx context.Context, rule *models.BriefDebriefRule) (bool, error) {
+	rule.UpdatedAt = time.Now()
+	res, err := r.db.NewUpdate().Model(rule).WherePK().Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("📛 brief/debrief kuralı güncellenemedi (ID %d): %w", rule.DataID, err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// 🔹 Kuralı siler (silinen satır yoksa false döner)
+func (r *BriefDebriefRuleRepository) DeleteRule(ctx context.Context, id int) (bool, error) {
+	res, err := r.db.NewDelete().
+		Model((*models.BriefDebriefRule)(nil)).
+		Where("data_id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("📛 brief/debrief kuralı silinemedi (ID %d): %w", id, err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// 🔹 Kuralları tek işlemde içe aktarır: DataID dolu olanlar güncellenir, diğerleri eklenir; reset true ise önce tüm kurallar silinir.
+// Geçerlilik aralığı çakışan veya bulunamayan bir kural olursa hiçbir değişiklik uygulanmaz.
+func (r *BriefDebriefRuleRepository) ImportRules(ctx context.Context, rules []models.BriefDebriefRule, reset bool) error {
+	now := time.Now()
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if reset {
+			if _, err := tx.NewDelete().Model((*models.BriefDebriefRule)(nil)).Where("TRUE").Exec(ctx); err != nil {
+				return fmt.Errorf("📛 brief/debrief kuralları silinemedi: %w", err)
+			}
+		}
+		for i := range rules {
+			rule := &rules[i]
+			rule.UpdatedAt = now
+			if rule.DataID != 0 && !reset {
+				res, err := tx.NewUpdate().Model(rule).WherePK().Exec(ctx)
+				if err != nil {
+					return fmt.Errorf("📛 brief/debrief kuralı güncellenemedi (ID %d): %w", rule.DataID, err)
+				}
+				if affected, _ := res.RowsAffected(); affected == 0 {
+					return fmt.Errorf("brief/debrief kuralı bulunamadı (ID %d)", rule.DataID)
+				}
+			} else {
+				rule.DataID = 0
+				if _, err := tx.NewInsert().Model(rule).Returning("data_id").Exec(ctx); err != nil {
+					return fmt.Errorf("📛 brief/debrief kuralı eklenemedi: %w", err)
+				}
+			}
+		}
+		// Çakışma kontrolü tüm satırlar uygulandıktan sonra yapılır; böylece versiyonların sırası önemsizdir
+		for i := range rules {
+			other, err := findOverlappingRule(ctx, tx, &rules[i])
+			if err != nil {
+				return err
+			}
+			if other != nil {
+				return fmt.Errorf("kural ID %d, geçerlilik aralığı çakışan kural ID %d ile aynı senaryo/uçak tipi/ekip tipi/meydan için tanımlı", rules[i].DataID, other.DataID)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type CommanderDiscretionRepository struct {
+	db *bun.DB
+}
+
+func NewCommanderDiscretionRepository(db *bun.DB) *CommanderDiscretionRepository {
+	return &CommanderDiscretionRepository{db: db}
+}
+
+// 🔹 Yeni bir kaptan takdiri kaydı ekler
+func (r *CommanderDiscretionRepository) CreateDiscretion(ctx context.Context, discretion *models.CommanderDiscretion) error {
+	discretion.CreatedAt = time.Now()
+	if _, err := r.db.NewInsert().Model(discretion).Exec(ctx); err != nil {
+		return fmt.Errorf("kaptan takdiri kaydedilemedi (trip_id=%s): %w", discretion.TripID, err)
+	}
+	return nil
+}
+
+// 🔹 Trip için kaydedilmiş en son kaptan takdirini getirir (yoksa nil döner)
+func (r *CommanderDiscretionRepository) GetLatestDiscretionByTripID(ctx context.Context, tripID string) (*models.CommanderDiscretion, error) {
+	var discretions []models.CommanderDiscretion
+	err := r.db.NewSelect().
+		Model(&discretions).
+		Where("trip_id = ?", tripID).
+		Order("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kaptan takdiri alınamadı (trip_id=%s): %w", tripID, err)
+	}
+	if len(discretions) == 0 {
+		return nil, nil
+	}
+	return &discretions[0], nil
+}
+
+// 🔹 Verilen tripler için kaydedilmiş en son kaptan takdirlerini trip_id → takdir haritası olarak getirir
+func (r *CommanderDiscretionRepository) GetLatestDiscretionsByTripIDs(ctx context.Context, tripIDs []string) (map[string]models.CommanderDiscretion, error) {
+	latest := map[string]models.CommanderDiscretion{}
+	if len(tripIDs) == 0 {
+		return latest, nil
+	}
+	var discretions []models.CommanderDiscretion
+	err := r.db.NewSelect().
+		Model(&discretions).
+		DistinctOn("trip_id").
+		Where("trip_id IN (?)", bun.In(tripIDs)).
+		OrderExpr("trip_id, created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kaptan takdirleri alınamadı: %w", err)
+	}
+	for _, d := range discretions {
+		latest[d.TripID] = d
+	}
+	return latest, nil
+}
+
+// 🔹 Kaptan takdiri kayıtlarını listeler (tripID boşsa tümü, en yeni önce)
+func (r *CommanderDiscretionRepository) ListDiscretions(ctx context.Context, tripID string) ([]models.CommanderDiscretion, error) {
+	var discretions []models.CommanderDiscretion
+	query := r.db.NewSelect().
+		Model(&discretions).
+		Order("created_at DESC")
+	if tripID != "" {
+		query = query.Where("trip_id = ?", tripID)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("kaptan takdiri kayıtları alınamadı: %w", err)
+	}
+	return discretions, nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrBaseAirportNotDefined, ana üs grubuna eklenmek istenen meydan airports tablosunda yoksa döner.
+var ErrBaseAirportNotDefined = errors.New("meydan airports tablosunda tanımlı değil")
+
+type CrewBaseAirportRepository struct {
+	db *bun.DB
+}
+
+func NewCrewBaseAirportRepository(db *bun.DB) *CrewBaseAirportRepository {
+	return &CrewBaseAirportRepository{db: db}
+}
+
+// 🔹 Tüm meydan → ana üs eşlemelerini getirir
+func (r *CrewBaseAirportRepository) ListBaseAirports(ctx context.Context) ([]models.CrewBaseAirport, error) {
+	var airports []models.CrewBaseAirport
+	err := r.db.NewSelect().
+		Model(&airports).
+		Order("base_code ASC", "airport_code ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ana üs meydanları alınamadı: %w", err)
+	}
+	return airports, nil
+}
+
+// 🔹 Meydanın ana üs eşlemesini ekler veya günceller; meydan airports tablosunda ana üs olarak işaretlenir.
+// Meydan airports tablosunda yoksa ErrBaseAirportNotDefined döner.
+func (r *CrewBaseAirportRepository) UpsertBaseAirport(ctx context.Context, airport *models.CrewBaseAirport) error {
+	airport.UpdatedAt = time.Now()
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Model((*models.Airport)(nil)).
+			Set("is_base = TRUE").
+			Where("iata_code = ?", airport.AirportCode).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("meydan ana üs olarak işaretlenemedi (airport_code=%s): %w", airport.AirportCode, err)
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return fmt.Errorf("%w (airport_code=%s)", ErrBaseAirportNotDefined, airport.AirportCode)
+		}
+
+		_, err = tx.NewInsert().
+			Model(airport).
+			On("CONFLICT (airport_code) DO UPDATE").
+			Set("base_code = EXCLUDED.base_code").
+			Set("updated_at = EXCLUDED.updated_at").
+			Returning("data_id").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("ana üs meydanı kaydedilemedi (airport_code=%s): %w", airport.AirportCode, err)
+		}
+		return nil
+	})
+}
+
+// 🔹 Meydanın ana üs eşlemesini siler (silinen satır yoksa false döner)
+func (r *CrewBaseAirportRepository) DeleteBaseAirport(ctx context.Context, airportCode string) (bool, error) {
+	res, err := r.db.NewDelete().
+		Model((*models.CrewBaseAirport)(nil)).
+		Where("airport_code = ?", airportCode).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ana üs meydanı silinemedi (airport_code=%s): %w", airportCode, err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// 🔹 Meydan kodu → ana üs kodu haritasını getirir. Yalnızca airports tablosunda ana üs olarak işaretlenmiş
+// meydanlar döner; crew_base_airports'ta grubu olmayan ana üsler kendi kodlarıyla tek meydanlı grup sayılır.
+func (r *CrewBaseAirportRepository) GetBaseAirportMap(ctx context.Context) (map[string]string, error) {
+	var rows []struct {
+		AirportCode string `bun:"airport_code"`
+		BaseCode    string `bun:"base_code"`
+	}
+	err := r.db.NewSelect().
+		TableExpr("airports AS a").
+		ColumnExpr("a.iata_code AS airport_code").
+		ColumnExpr("COALESCE(c.base_code, a.iata_code) AS base_code").
+		Join("LEFT JOIN crew_base_airports AS c ON c.airport_code = a.iata_code").
+		Where("a.is_base = TRUE").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("ana üs meydanları alınamadı: %w", err)
+	}
+	bases := make(map[string]string, len(rows))
+	for _, row := range rows {
+		bases[row.AirportCode] = row.BaseCode
+	}
+	return bases, nil
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type CrewInfoRepository struct {
+	db *bun.DB
+}
+
+func NewCrewInfoRepository(db *bun.DB) *CrewInfoRepository {
+	return &CrewInfoRepository{db: db}
+}
+
+// 🔹 person_id ile ekip bilgisini getirir (bulunamazsa nil döner)
+func (r *CrewInfoRepository) GetCrewInfoByPersonID(ctx context.Context, personID string) (*models.CrewInfo, error) {
+	var crewInfo models.CrewInfo
+	err := r.db.NewSelect().
+		Model(&crewInfo).
+		Where("person_id = ?", personID).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ekip bilgisi alınamadı (person_id=%s): %w", personID, err)
+	}
+	return &crewInfo, nil
+}
+
+// 🔹 Ana üssü (base_location) veya filosu (base_filo) verilen değer olan ekip üyelerinin person_id listesini getirir.
+// column yalnızca "base_location" veya "base_filo" olabilir.
+func (r *CrewInfoRepository) GetPersonIDsBy(ctx context.Context, column, value string) ([]string, error) {
+	if column != "base_location" && column != "base_filo" {
+		return nil, fmt.Errorf("desteklenmeyen ekip filtresi: %s", column)
+	}
+	var personIDs []string
+	err := r.db.NewSelect().
+		Model((*models.CrewInfo)(nil)).
+		ColumnExpr("DISTINCT person_id").
+		Where("? = ?", bun.Ident(column), value).
+		Where("person_id <> ''").
+		Order("person_id ASC").
+		Scan(ctx, &personIDs)
+	if err != nil {
+		return nil, fmt.Errorf("ekip üyeleri alınamadı (%s=%s): %w", column, value, err)
+	}
+	return personIDs, nil
+}
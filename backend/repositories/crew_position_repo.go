@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type CrewPositionRepository struct {
+	db *bun.DB
+}
+
+func NewCrewPositionRepository(db *bun.DB) *CrewPositionRepository {
+	return &CrewPositionRepository{db: db}
+}
+
+// 🔹 Tüm pozisyonları kıdem sırasına göre getirir (crewType boş değilse yalnızca o ekip tipindekiler)
+func (r *CrewPositionRepository) ListCrewPositions(ctx context.Context, crewType string) ([]models.CrewPosition, error) {
+	var positions []models.CrewPosition
+	query := r.db.NewSelect().
+		Model(&positions).
+		Order("rank_order ASC", "position_code ASC")
+	if crewType != "" {
+		query = query.Where("crew_type = ?", crewType)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("ekip pozisyonları alınamadı: %w", err)
+	}
+	return positions, nil
+}
+
+// 🔹 Pozisyonları pozisyon koduna göre ekler veya günceller
+func (r *CrewPositionRepository) UpsertCrewPositions(ctx context.Context, positions []models.CrewPosition) error {
+	if len(positions) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range positions {
+		positions[i].UpdatedAt = now
+	}
+	_, err := r.db.NewInsert().
+		Model(&positions).
+		On("CONFLICT (position_code) DO UPDATE").
+		Set("crew_type = EXCLUDED.crew_type").
+		Set("rank_order = EXCLUDED.rank_order").
+		Set("crew_need_column = EXCLUDED.crew_need_column").
+		Set("description = EXCLUDED.description").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("data_id").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("ekip pozisyonları kaydedilemedi: %w", err)
+	}
+	return nil
+}
+
+// 🔹 Pozisyonu siler (silinen satır yoksa false döner)
+func (r *CrewPositionRepository) DeleteCrewPosition(ctx context.Context, positionCode string) (bool, error) {
+	res, err := r.db.NewDelete().
+		Model((*models.CrewPosition)(nil)).
+		Where("position_code = ?", positionCode).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ekip pozisyonu silinemedi (position_code=%s): %w", positionCode, err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// 🔹 Tüm pozisyon kayıtlarını siler (import öncesi reset=true için)
+func (r *CrewPositionRepository) DeleteAllCrewPositions(ctx context.Context) error {
+	_, err := r.db.NewDelete().
+		Model((*models.CrewPosition)(nil)).
+		Where("TRUE").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("ekip pozisyonları silinemedi: %w", err)
+	}
+	return nil
+}
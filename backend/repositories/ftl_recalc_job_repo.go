@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type FTLRecalcJobRepository struct {
+	db *bun.DB
+}
+
+func NewFTLRecalcJobRepository(db *bun.DB) *FTLRecalcJobRepository {
+	return &FTLRecalcJobRepository{db: db}
+}
+
+// 🔹 Yeni toplu yeniden hesaplama işini kaydeder
+func (r *FTLRecalcJobRepository) CreateJob(ctx context.Context, job *models.FTLRecalcJob) error {
+	if _, err := r.db.NewInsert().Model(job).Exec(ctx); err != nil {
+		return fmt.Errorf("yeniden hesaplama işi kaydedilemedi (job_id=%s): %w", job.JobID, err)
+	}
+	return nil
+}
+
+// 🔹 İşin durum ve sayaçlarını günceller
+func (r *FTLRecalcJobRepository) UpdateJob(ctx context.Context, job *models.FTLRecalcJob) error {
+	_, err := r.db.NewUpdate().
+		Model(job).
+		Column("status", "total_crew", "crew_processed", "trips_saved", "violations_found", "error_count", "errors", "finished_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("yeniden hesaplama işi güncellenemedi (job_id=%s): %w", job.JobID, err)
+	}
+	return nil
+}
+
+// 🔹 job_id ile işi getirir (bulunamazsa nil döner)
+func (r *FTLRecalcJobRepository) GetJob(ctx context.Context, jobID string) (*models.FTLRecalcJob, error) {
+	var job models.FTLRecalcJob
+	err := r.db.NewSelect().
+		Model(&job).
+		Where("job_id = ?", jobID).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("yeniden hesaplama işi alınamadı (job_id=%s): %w", jobID, err)
+	}
+	return &job, nil
+}
+
+// 🔹 Son işleri başlangıç zamanına göre azalan sırada getirir
+func (r *FTLRecalcJobRepository) ListJobs(ctx context.Context, limit int) ([]models.FTLRecalcJob, error) {
+	var jobs []models.FTLRecalcJob
+	query := r.db.NewSelect().
+		Model(&jobs).
+		Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("yeniden hesaplama işleri alınamadı: %w", err)
+	}
+	return jobs, nil
+}
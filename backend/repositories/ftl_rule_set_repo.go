@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type FTLRuleSetRepository struct {
+	db *bun.DB
+}
+
+func NewFTLRuleSetRepository(db *bun.DB) *FTLRuleSetRepository {
+	return &FTLRuleSetRepository{db: db}
+}
+
+// 🔹 Tüm kural seti versiyonlarını getirir (en yeni versiyon önce)
+func (r *FTLRuleSetRepository) ListRuleSets(ctx context.Context) ([]models.FTLRuleSet, error) {
+	var ruleSets []models.FTLRuleSet
+	err := r.db.NewSelect().
+		Model(&ruleSets).
+		Order("version DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("FTL kural setleri alınamadı: %w", err)
+	}
+	return ruleSets, nil
+}
+
+// 🔹 ID ile tek bir kural seti getirir (bulunamazsa nil döner)
+func (r *FTLRuleSetRepository) GetRuleSetByID(ctx context.Context, id int) (*models.FTLRuleSet, error) {
+	var ruleSet models.FTLRuleSet
+	err := r.db.NewSelect().
+		Model(&ruleSet).
+		Where("data_id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("FTL kural seti alınamadı (id=%d): %w", id, err)
+	}
+	return &ruleSet, nil
+}
+
+// 🔹 Versiyon numarasıyla tek bir kural seti getirir (bulunamazsa nil döner)
+func (r *FTLRuleSetRepository) GetRuleSetByVersion(ctx context.Context, version int) (*models.FTLRuleSet, error) {
+	var ruleSet models.FTLRuleSet
+	err := r.db.NewSelect().
+		Model(&ruleSet).
+		Where("version = ?", version).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("FTL kural seti alınamadı (version=%d): %w", version, err)
+	}
+	return &ruleSet, nil
+}
+
+// 🔹 Yeni bir kural seti versiyonu oluşturur. Versiyon numarası otomatik atanır,
+// yeni versiyon pasif olarak kaydedilir ve ActivateRuleSet ile devreye alınır.
+func (r *FTLRuleSetRepository) CreateRuleSet(ctx context.Context, ruleSet *models.FTLRuleSet) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var maxVersion int
+		err := tx.NewSelect().
+			Model((*models.FTLRuleSet)(nil)).
+			ColumnExpr("COALESCE(MAX(version), 0)").
+			Scan(ctx, &maxVersion)
+		if err != nil {
+			return fmt.Errorf("FTL kural seti versiyonu belirlenemedi: %w", err)
+		}
+
+		ruleSet.DataID = 0
+		ruleSet.Version = maxVersion + 1
+		ruleSet.IsActive = false
+		ruleSet.ActivatedAt = nil
+
+		if _, err := tx.NewInsert().Model(ruleSet).Exec(ctx); err != nil {
+			return fmt.Errorf("FTL kural seti kaydedilemedi: %w", err)
+		}
+		return nil
+	})
+}
+
+// 🔹 Kural setini aktive eder. Aynı yürürlük başlangıcına sahip diğer aktif versiyonlar
+// pasife alınır; farklı tarihli versiyonlar (ör. geçmiş dönem kuralları) etkilenmez.
+func (r *FTLRuleSetRepository) ActivateRuleSet(ctx context.Context, id int) (*models.FTLRuleSet, error) {
+	var activated models.FTLRuleSet
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := tx.NewSelect().Model(&activated).Where("data_id = ?", id).For("UPDATE").Scan(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.NewUpdate().
+			Model((*models.FTLRuleSet)(nil)).
+			Set("is_active = FALSE").
+			Where("data_id <> ?", id).
+			Where("effective_from = ?", activated.EffectiveFrom).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		activated.IsActive = true
+		activated.ActivatedAt = &now
+		_, err = tx.NewUpdate().
+			Model(&activated).
+			Column("is_active", "activated_at").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("FTL kural seti aktive edilemedi (id=%d): %w", id, err)
+	}
+	return &activated, nil
+}
+
+// 🔹 Aktif kural setlerini GetRuleSetForDate ile aynı seçim sırasıyla (en geç yürürlüğe giren, eşitlikte en yüksek
+// versiyon önce) getirir
+func (r *FTLRuleSetRepository) ListActiveRuleSets(ctx context.Context) ([]models.FTLRuleSet, error) {
+	var ruleSets []models.FTLRuleSet
+	err := r.db.NewSelect().
+		Model(&ruleSets).
+		Where("is_active = TRUE").
+		OrderExpr("effective_from DESC, version DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aktif FTL kural setleri alınamadı: %w", err)
+	}
+	return ruleSets, nil
+}
+
+// 🔹 Verilen anda yürürlükte olan aktif kural setini getirir.
+// Birden fazla aday varsa en geç yürürlüğe gireni, eşitlikte en yüksek versiyonu seçer.
+func (r *FTLRuleSetRepository) GetRuleSetForDate(ctx context.Context, at time.Time) (*models.FTLRuleSet, error) {
+	var ruleSet models.FTLRuleSet
+	err := r.db.NewSelect().
+		Model(&ruleSet).
+		Where("is_active = TRUE").
+		Where("effective_from <= ?", at).
+		Where("effective_to IS NULL OR effective_to > ?", at).
+		OrderExpr("effective_from DESC, version DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("yürürlükteki FTL kural seti alınamadı: %w", err)
+	}
+	return &ruleSet, nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type OffDayComplianceRepository struct {
+	db *bun.DB
+}
+
+func NewOffDayComplianceRepository(db *bun.DB) *OffDayComplianceRepository {
+	return &OffDayComplianceRepository{db: db}
+}
+
+// 🔹 Ekip üyesinin dönem sonucunu ekler veya günceller
+func (r *OffDayComplianceRepository) UpsertCompliance(ctx context.Context, compliance *models.OffDayCompliance) error {
+	compliance.CheckedAt = time.Now()
+	_, err := r.db.NewInsert().
+		Model(compliance).
+		On("CONFLICT (crew_member_id, period_month) DO UPDATE").
+		Set("period_start = EXCLUDED.period_start").
+		Set("period_end = EXCLUDED.period_end").
+		Set("base_location = EXCLUDED.base_location").
+		Set("worked_days = EXCLUDED.worked_days").
+		Set("off_days = EXCLUDED.off_days").
+		Set("entitlement_work_days = EXCLUDED.entitlement_work_days").
+		Set("off_day_entitlement = EXCLUDED.off_day_entitlement").
+		Set("distribution = EXCLUDED.distribution").
+		Set("required_blocks = EXCLUDED.required_blocks").
+		Set("off_day_blocks = EXCLUDED.off_day_blocks").
+		Set("shortfall_days = EXCLUDED.shortfall_days").
+		Set("distribution_met = EXCLUDED.distribution_met").
+		Set("violations = EXCLUDED.violations").
+		Set("checked_at = EXCLUDED.checked_at").
+		Returning("data_id").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("boş gün uygunluk sonucu kaydedilemedi (crew=%s): %w", compliance.CrewMemberID, err)
+	}
+	return nil
+}
+
+// 🔹 fromMonth ile toMonth (dahil, "YYYY-MM") arasındaki dönem sonuçlarını getirir.
+// crewMemberID boşsa tüm ekip üyeleri dahil edilir; violationsOnly ile yalnızca ihlalli sonuçlar döner.
+func (r *OffDayComplianceRepository) ListCompliance(ctx context.Context, fromMonth, toMonth, crewMemberID string, violationsOnly bool) ([]models.OffDayCompliance, error) {
+	var rows []models.OffDayCompliance
+	query := r.db.NewSelect().
+		Model(&rows).
+		Where("period_month >= ?", fromMonth).
+		Where("period_month <= ?", toMonth).
+		Order("period_month ASC", "crew_member_id ASC")
+	if crewMemberID != "" {
+		query = query.Where("crew_member_id = ?", crewMemberID)
+	}
+	if violationsOnly {
+		query = query.
+			Where("violations IS NOT NULL").
+			Where("jsonb_array_length(violations) > 0")
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("boş gün uygunluk sonuçları alınamadı: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type OffDayTableRepository struct {
+	db *bun.DB
+}
+
+func NewOffDayTableRepository(db *bun.DB) *OffDayTableRepository {
+	return &OffDayTableRepository{db: db}
+}
+
+// 🔹 Boş gün hak ediş satırlarını work_days değerine göre azalan sırada getirir
+func (r *OffDayTableRepository) ListEntitlements(ctx context.Context) ([]models.OffDayTable, error) {
+	var rows []models.OffDayTable
+	err := r.db.NewSelect().
+		Model(&rows).
+		Order("work_days DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("boş gün hak ediş tablosu alınamadı: %w", err)
+	}
+	return rows, nil
+}
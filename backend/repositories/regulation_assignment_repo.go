@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	"github.com/uptrace/bun"
+)
+
+type RegulationAssignmentRepository struct {
+	db *bun.DB
+}
+
+func NewRegulationAssignmentRepository(db *bun.DB) *RegulationAssignmentRepository {
+	return &RegulationAssignmentRepository{db: db}
+}
+
+// 🔹 Tüm mevzuat atamalarını getirir (subjectType boş değilse yalnızca o konudakiler)
+func (r *RegulationAssignmentRepository) ListAssignments(ctx context.Context, subjectType string) ([]models.RegulationAssignment, error) {
+	var assignments []models.RegulationAssignment
+	query := r.db.NewSelect().
+		Model(&assignments).
+		Order("subject_type ASC", "subject_value ASC")
+	if subjectType != "" {
+		query = query.Where("subject_type = ?", subjectType)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("mevzuat atamaları alınamadı: %w", err)
+	}
+	return assignments, nil
+}
+
+// 🔹 Mevzuat atamasını ekler veya günceller
+func (r *RegulationAssignmentRepository) UpsertAssignment(ctx context.Context, assignment *models.RegulationAssignment) error {
+	assignment.UpdatedAt = time.Now()
+	_, err := r.db.NewInsert().
+		Model(assignment).
+		On("CONFLICT (subject_type, subject_value) DO UPDATE").
+		Set("regulation = EXCLUDED.regulation").
+		Set("note = EXCLUDED.note").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("data_id").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("mevzuat ataması kaydedilemedi (%s=%s): %w", assignment.SubjectType, assignment.SubjectValue, err)
+	}
+	return nil
+}
+
+// 🔹 Mevzuat atamasını siler (silinen satır yoksa false döner)
+func (r *RegulationAssignmentRepository) DeleteAssignment(ctx context.Context, subjectType, subjectValue string) (bool, error) {
+	res, err := r.db.NewDelete().
+		Model((*models.RegulationAssignment)(nil)).
+		Where("subject_type = ?", subjectType).
+		Where("subject_value = ?", subjectValue).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("mevzuat ataması silinemedi (%s=%s): %w", subjectType, subjectValue, err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// 🔹 Konu tipi → konu değeri → mevzuat kodu haritasını getirir
+func (r *RegulationAssignmentRepository) GetAssignmentMap(ctx context.Context) (map[string]map[string]string, error) {
+	assignments, err := r.ListAssignments(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]map[string]string{}
+	for _, a := range assignments {
+		if result[a.SubjectType] == nil {
+			result[a.SubjectType] = map[string]string{}
+		}
+		result[a.SubjectType][a.SubjectValue] = a.Regulation
+	}
+	return result, nil
+}
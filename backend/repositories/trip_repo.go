@@ -7,6 +7,7 @@ import (
 	"database/sql" // sql.ErrNoRows için hala gerekli, sql.NullTime artık doğrudan kullanılmasa da kalsın
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"mini_CMS_Desktop_App/models"
@@ -52,7 +53,40 @@ func (r *TripRepository) SaveTrip(trip *models.Trip) error {
 		Set("calculated_rest_period_start = EXCLUDED.calculated_rest_period_start").
 		Set("calculated_rest_period_end = EXCLUDED.calculated_rest_period_end").
 		Set("calculated_rest_period_duration_min = EXCLUDED.calculated_rest_period_duration_min").
+		Set("rest_location = EXCLUDED.rest_location").
+		Set("min_rest_required_min = EXCLUDED.min_rest_required_min").
+		Set("max_time_zone_diff_min = EXCLUDED.max_time_zone_diff_min").
+		Set("time_away_from_base_min = EXCLUDED.time_away_from_base_min").
+		Set("required_recovery_nights = EXCLUDED.required_recovery_nights").
+		Set("fatigue_score = EXCLUDED.fatigue_score").
+		Set("fatigue_peak_at = EXCLUDED.fatigue_peak_at").
+		Set("block_time_min = EXCLUDED.block_time_min").
+		Set("sector_count = EXCLUDED.sector_count").
+		Set("sectors = EXCLUDED.sectors").
 		Set("ftl_violations = EXCLUDED.ftl_violations").
+		Set("brief_rule_id = EXCLUDED.brief_rule_id").
+		Set("debrief_rule_id = EXCLUDED.debrief_rule_id").
+		Set("cumulative_windows = EXCLUDED.cumulative_windows").
+		Set("rule_set_version = EXCLUDED.rule_set_version").
+		Set("regulation = EXCLUDED.regulation").
+		// Hesaplama başladıktan sonra işaretlenen trip'in işareti korunur
+		Set("recalc_required = CASE WHEN trips.recalc_flagged_at > EXCLUDED.last_calculated_at THEN trips.recalc_required ELSE EXCLUDED.recalc_required END").
+		Set("acclimatisation_state = EXCLUDED.acclimatisation_state").
+		Set("acclimatisation_reference_tz = EXCLUDED.acclimatisation_reference_tz").
+		Set("flight_crew_complement = EXCLUDED.flight_crew_complement").
+		Set("rest_facility_class = EXCLUDED.rest_facility_class").
+		Set("split_duty = EXCLUDED.split_duty").
+		Set("standby_type = EXCLUDED.standby_type").
+		Set("standby_duration_min = EXCLUDED.standby_duration_min").
+		Set("standby_called_out = EXCLUDED.standby_called_out").
+		Set("standby_fdp_reduction_min = EXCLUDED.standby_fdp_reduction_min").
+		Set("counted_duty_min = EXCLUDED.counted_duty_min").
+		Set("early_start = EXCLUDED.early_start").
+		Set("late_finish = EXCLUDED.late_finish").
+		Set("night_duty = EXCLUDED.night_duty").
+		Set("encroaches_wocl = EXCLUDED.encroaches_wocl").
+		Set("commander_discretion_min = EXCLUDED.commander_discretion_min").
+		Set("applied_fdp_limit = EXCLUDED.applied_fdp_limit").
 		Set("activities = EXCLUDED.activities").
 		Set("last_calculated_at = EXCLUDED.last_calculated_at").
 		Set("updated_at = NOW()").
@@ -101,3 +135,128 @@ func (r *TripRepository) GetTripsByCrewMemberID(crewMemberID string, fromTime ti
 	// bun, Nullable alanları ve JSONB alanlarını otomatik olarak yönetir.
 	return trips, nil
 }
+
+// GetTripsWithViolations, verilen görev başlangıcı aralığında en az bir FTL ihlali bulunan tripleri getirir.
+// crewMemberID boşsa tüm ekip üyeleri dahil edilir.
+func (r *TripRepository) GetTripsWithViolations(ctx context.Context, from, to time.Time, crewMemberID string) ([]models.Trip, error) {
+	var trips []models.Trip
+	query := r.db.NewSelect().
+		Model(&trips).
+		Where("ftl_violations IS NOT NULL").
+		Where("jsonb_array_length(ftl_violations) > 0").
+		Where("calculated_duty_period_start >= ?", from).
+		Where("calculated_duty_period_start < ?", to).
+		Order("calculated_duty_period_start ASC")
+
+	if crewMemberID != "" {
+		query = query.Where("crew_member_id = ?", crewMemberID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("ihlalli tripler çekilirken hata: %w", err)
+	}
+	return trips, nil
+}
+
+// GetTripsWithCommanderDiscretion, verilen görev başlangıcı aralığında kaptan takdiri kullanılmış tripleri getirir.
+func (r *TripRepository) GetTripsWithCommanderDiscretion(ctx context.Context, from, to time.Time) ([]models.Trip, error) {
+	var trips []models.Trip
+	err := r.db.NewSelect().
+		Model(&trips).
+		Where("commander_discretion_min > 0").
+		Where("calculated_duty_period_start >= ?", from).
+		Where("calculated_duty_period_start < ?", to).
+		Order("calculated_duty_period_start ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kaptan takdiri kullanılan tripler çekilirken hata: %w", err)
+	}
+	return trips, nil
+}
+
+// GetHighestFatigueTrips, verilen görev başlangıcı aralığında yorgunluk skoru en yüksek tripleri (en fazla limit adet) getirir.
+// Aktiviteler yüklenmez. crewMemberID boşsa tüm ekip üyeleri dahil edilir.
+func (r *TripRepository) GetHighestFatigueTrips(ctx context.Context, from, to time.Time, crewMemberID string, minScore float64, limit int) ([]models.Trip, error) {
+	var trips []models.Trip
+	query := r.db.NewSelect().
+		Model(&trips).
+		ExcludeColumn("activities", "ftl_violations", "split_duty", "sectors", "cumulative_windows").
+		Where("fatigue_score > 0").
+		Where("fatigue_score >= ?", minScore).
+		Where("calculated_duty_period_start >= ?", from).
+		Where("calculated_duty_period_start < ?", to).
+		Order("fatigue_score DESC", "calculated_duty_period_start ASC").
+		Limit(limit)
+
+	if crewMemberID != "" {
+		query = query.Where("crew_member_id = ?", crewMemberID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("yorgunluk skoru en yüksek tripler çekilirken hata: %w", err)
+	}
+	return trips, nil
+}
+
+// FlagTripsForBriefDebriefRules, verilen brief/debrief kurallarından (değişiklik öncesi ve sonrası versiyonlar) etkilenebilecek
+// tripleri recalc_required olarak işaretler ve işaretlenen trip sayısını döndürür. Eşleştirme BriefDebriefCalculator ile
+// aynı joker değerleri ("Hepsi", "Diğer") kullanır; geçerlilik aralığı saat dilimi farkları için bir gün genişletilir.
+// Zaten işaretli triplerin recalc_flagged_at değeri de yenilenir; böylece o sırada süren bir hesaplama işareti silemez.
+func (r *TripRepository) FlagTripsForBriefDebriefRules(ctx context.Context, rules []models.BriefDebriefRule) (int, error) {
+	total := 0
+	flaggedAt := time.Now()
+	for _, rule := range rules {
+		res, err := r.flagTripsForBriefDebriefRuleQuery(rule, flaggedAt).Exec(ctx)
+		if err != nil {
+			return total, fmt.Errorf("brief/debrief kuralından etkilenen tripler işaretlenirken hata (kural ID %d): %w", rule.DataID, err)
+		}
+		affected, _ := res.RowsAffected()
+		total += int(affected)
+	}
+	return total, nil
+}
+
+// flagTripsForBriefDebriefRuleQuery, kuraldan etkilenebilecek tripleri işaretleyen sorguyu kurar. Joker alanlar
+// filtre eklemez; tüm alanları joker ve süresiz bir kural bütün tripleri işaretler.
+func (r *TripRepository) flagTripsForBriefDebriefRuleQuery(rule models.BriefDebriefRule, flaggedAt time.Time) *bun.UpdateQuery {
+	query := r.db.NewUpdate().
+		Model((*models.Trip)(nil)).
+		Set("recalc_required = TRUE").
+		Set("recalc_flagged_at = ?", flaggedAt).
+		Where("TRUE")
+	if !strings.EqualFold(rule.CrewType, "Hepsi") {
+		query = query.Where("LOWER(crew_type) = LOWER(?)", rule.CrewType)
+	}
+	if !strings.EqualFold(rule.ScenarioType, "Hepsi") {
+		query = query.Where("(LOWER(brief_trip_type) = LOWER(?) OR LOWER(debrief_trip_type) = LOWER(?))", rule.ScenarioType, rule.ScenarioType)
+	}
+	if !strings.EqualFold(rule.AircraftType, "Hepsi") && !strings.EqualFold(rule.AircraftType, "Uçuş Ekibi") {
+		query = query.Where("(LOWER(brief_aircraft_type) = LOWER(?) OR LOWER(debrief_aircraft_type) = LOWER(?))", rule.AircraftType, rule.AircraftType)
+	}
+	if !strings.EqualFold(rule.DutyStartAirport, "Hepsi") && !strings.EqualFold(rule.DutyStartAirport, "Diğer") {
+		query = query.Where("LOWER(duty_start_airport) = LOWER(?)", rule.DutyStartAirport)
+	}
+	if rule.ValidFrom != nil {
+		query = query.Where("calculated_duty_period_start >= ?", rule.ValidFrom.AddDate(0, 0, -1))
+	}
+	if rule.ValidTo != nil {
+		query = query.Where("calculated_duty_period_start < ?", rule.ValidTo.AddDate(0, 0, 2))
+	}
+	return query
+}
+
+// GetCrewIDsWithRecalcRequired, yeniden hesaplanması gereken (recalc_required) trip'i olan ekip üyelerini getirir.
+func (r *TripRepository) GetCrewIDsWithRecalcRequired(ctx context.Context) ([]string, error) {
+	var crewIDs []string
+	err := r.db.NewSelect().
+		Model((*models.Trip)(nil)).
+		Distinct().
+		Column("crew_member_id").
+		Where("recalc_required = TRUE").
+		Order("crew_member_id ASC").
+		Scan(ctx, &crewIDs)
+	if err != nil {
+		return nil, fmt.Errorf("yeniden hesaplanacak ekip üyeleri çekilirken hata: %w", err)
+	}
+	return crewIDs, nil
+}
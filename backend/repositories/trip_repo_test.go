@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+
+	_ "github.com/lib/pq" // PostgreSQL sürücüsü (bağlantı açılmaz, yalnızca sorgu metni üretilir)
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestFlagTripsForBriefDebriefRuleQuery(t *testing.T) {
+	sqlDB, err := sql.Open("postgres", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	db := bun.NewDB(sqlDB, pgdialect.New())
+	defer db.Close()
+	repo := NewTripRepository(db)
+
+	validFrom := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	flaggedAt := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     models.BriefDebriefRule
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "tüm alanları joker ve süresiz kural bütün tripleri işaretler",
+			rule: models.BriefDebriefRule{
+				DataID: 1, CrewType: "Hepsi", ScenarioType: "Hepsi", AircraftType: "Hepsi", DutyStartAirport: "Diğer",
+			},
+			contains: []string{"WHERE (TRUE)"},
+			excludes: []string{"crew_type", "duty_start_airport", "calculated_duty_period_start"},
+		},
+		{
+			name: "Uçuş Ekibi uçak tipi ve Hepsi meydanı filtre eklemez",
+			rule: models.BriefDebriefRule{
+				DataID: 2, CrewType: "Hepsi", ScenarioType: "Hepsi", AircraftType: "Uçuş Ekibi", DutyStartAirport: "Hepsi",
+			},
+			contains: []string{"WHERE (TRUE)"},
+			excludes: []string{"brief_aircraft_type", "duty_start_airport"},
+		},
+		{
+			name: "belirli alanlar ve geçerlilik başlangıcı filtrelenir",
+			rule: models.BriefDebriefRule{
+				DataID: 3, CrewType: models.CrewTypeCabin, ScenarioType: "Simülatör", AircraftType: models.AircraftBodyWide,
+				DutyStartAirport: "IST", ValidFrom: &validFrom,
+			},
+			contains: []string{
+				"LOWER(crew_type) = LOWER('Kabin Ekibi')",
+				"LOWER(brief_trip_type) = LOWER('Simülatör')",
+				"LOWER(brief_aircraft_type) = LOWER('GENİŞ GÖVDE')",
+				"LOWER(duty_start_airport) = LOWER('IST')",
+				"calculated_duty_period_start >= '2025-02-28",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := repo.flagTripsForBriefDebriefRuleQuery(tt.rule, flaggedAt).AppendQuery(db.Formatter(), nil)
+			if err != nil {
+				t.Fatalf("sorgu kurulamadı: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(query), want) {
+					t.Errorf("sorguda %q yok:\n%s", want, query)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(string(query), unwanted) {
+					t.Errorf("sorguda beklenmeyen %q var:\n%s", unwanted, query)
+				}
+			}
+		})
+	}
+}
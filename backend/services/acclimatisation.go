@@ -0,0 +1,122 @@
+package services
+
+import (
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Aklimatizasyon durumları (SHT-FTL / ORO.FTL.105 tablosundaki B, D ve X durumları)
+const (
+	AcclimatisedToReference = "acclimatised_reference" // B: referans zaman dilimine aklimatize
+	AcclimatisedToLocal     = "acclimatised_local"     // D: bir sonraki görevin başladığı yerel saate aklimatize
+	AcclimatisationUnknown  = "unknown"                // X: durumu bilinmiyor (aklimatize değil)
+)
+
+// AcclimatisationTracker, bir ekip üyesinin zaman dilimi geçişlerine göre aklimatizasyon durumunu
+// trip sırası boyunca izleyen durum makinesidir. Triplere görev başlangıç sırasıyla Advance çağrılmalıdır.
+type AcclimatisationTracker struct {
+	reference  *time.Location // Ekibin aklimatize olduğu zaman dilimi
+	clockStart time.Time      // Referans zaman diliminde son raporlama anı (geçen süre bu andan ölçülür)
+}
+
+// NewAcclimatisationTracker, ekip üyesinin ana üs zaman diliminde aklimatize olduğunu varsayarak başlar.
+func NewAcclimatisationTracker(base *time.Location) *AcclimatisationTracker {
+	if base == nil {
+		base = time.UTC
+	}
+	return &AcclimatisationTracker{reference: base}
+}
+
+// Advance, verilen raporlama anı ve görev başlangıç meydanı için aklimatizasyon durumunu belirler
+// ve bir sonraki trip için iç durumu günceller. Dönen referans, UGS tablosunda kullanılacak zaman dilimidir.
+func (a *AcclimatisationTracker) Advance(reportTime time.Time, dutyStartAirport string) (string, *time.Location) {
+	departureLoc, ok := models.GetAirportLocation(dutyStartAirport)
+	if !ok {
+		// Meydanın zaman dilimi bilinmiyorsa referans zaman diliminde olunduğu varsayılır.
+		departureLoc = a.reference
+	}
+
+	diff := timeZoneDifference(a.reference, departureLoc, reportTime)
+	if diff == 0 || a.clockStart.IsZero() {
+		// İlk raporlamada veya referans saatinde raporlamada geçen süre bu andan yeniden ölçülür
+		a.clockStart = reportTime
+	}
+	elapsed := reportTime.Sub(a.clockStart)
+
+	switch acclimatisationTableState(diff, elapsed) {
+	case AcclimatisedToLocal:
+		a.reference = departureLoc
+		a.clockStart = reportTime
+		return AcclimatisedToLocal, departureLoc
+	case AcclimatisedToReference:
+		return AcclimatisedToReference, a.reference
+	default:
+		return AcclimatisationUnknown, a.reference
+	}
+}
+
+// acclimatisationTableState, referans ile yerel saat arasındaki fark ve referans zaman diliminde raporlamadan
+// bu yana geçen süreye göre tablodaki B/D/X durumunu döndürür.
+func acclimatisationTableState(diff, elapsed time.Duration) string {
+	// Geçen süre sütunları: <48, 48-71:59, 72-95:59, 96-119:59, ≥120 saat
+	column := 0
+	switch {
+	case elapsed < 48*time.Hour:
+		column = 0
+	case elapsed < 72*time.Hour:
+		column = 1
+	case elapsed < 96*time.Hour:
+		column = 2
+	case elapsed < 120*time.Hour:
+		column = 3
+	default:
+		column = 4
+	}
+
+	// Satırlar: <4, ≤6, ≤9, ≤12 saat fark (12 saati aşan farklar 12 saat satırıyla değerlendirilir)
+	table := [4][5]string{
+		{AcclimatisedToReference, AcclimatisedToLocal, AcclimatisedToLocal, AcclimatisedToLocal, AcclimatisedToLocal},
+		{AcclimatisedToReference, AcclimatisationUnknown, AcclimatisedToLocal, AcclimatisedToLocal, AcclimatisedToLocal},
+		{AcclimatisedToReference, AcclimatisationUnknown, AcclimatisationUnknown, AcclimatisedToLocal, AcclimatisedToLocal},
+		{AcclimatisedToReference, AcclimatisationUnknown, AcclimatisationUnknown, AcclimatisationUnknown, AcclimatisedToLocal},
+	}
+
+	row := 3
+	switch {
+	case diff < 4*time.Hour:
+		row = 0
+	case diff <= 6*time.Hour:
+		row = 1
+	case diff <= 9*time.Hour:
+		row = 2
+	}
+	return table[row][column]
+}
+
+// timeZoneDifference, iki zaman dilimi arasındaki mutlak UTC ofset farkını verilen anda hesaplar.
+// Fark 12 saati aşarsa diğer yönden ölçülür (ör. UTC+10 ile UTC-8 arasındaki 18 saat, 6 saat olarak alınır).
+func timeZoneDifference(a, b *time.Location, at time.Time) time.Duration {
+	_, offsetA := at.In(a).Zone()
+	_, offsetB := at.In(b).Zone()
+	diff := offsetA - offsetB
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > 12*3600 {
+		diff = 24*3600 - diff
+	}
+	return time.Duration(diff) * time.Second
+}
+
+// tripReportTime, trip'in raporlama (görev başlangıç) anını döndürür. Trip henüz hesaplanmamışsa
+// ilk aktivitenin DutyStart değeri, o da yoksa ilk bacak kalkış zamanı kullanılır.
+func tripReportTime(trip *models.Trip) time.Time {
+	if !trip.CalculatedDutyPeriodStart.IsZero() {
+		return trip.CalculatedDutyPeriodStart
+	}
+	if len(trip.Activities) > 0 && !trip.Activities[0].DutyStart.IsZero() {
+		return trip.Activities[0].DutyStart
+	}
+	return trip.FirstLegDepartureTime
+}
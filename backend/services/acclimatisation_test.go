@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcclimatisationTableState(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    time.Duration
+		elapsed time.Duration
+		want    string
+	}{
+		{"<4 saat fark, 48 saatten az: B", 3 * time.Hour, 47 * time.Hour, AcclimatisedToReference},
+		{"<4 saat fark, 48 saat: D", 3 * time.Hour, 48 * time.Hour, AcclimatisedToLocal},
+		{"<4 saat fark, 120 saat: D", 2 * time.Hour, 130 * time.Hour, AcclimatisedToLocal},
+		{"≤6 saat fark, 48 saatten az: B", 4 * time.Hour, 10 * time.Hour, AcclimatisedToReference},
+		{"≤6 saat fark, 48-71:59 saat: X", 6 * time.Hour, 60 * time.Hour, AcclimatisationUnknown},
+		{"≤6 saat fark, 72 saat: D", 6 * time.Hour, 72 * time.Hour, AcclimatisedToLocal},
+		{"≤9 saat fark, 72-95:59 saat: X", 9 * time.Hour, 90 * time.Hour, AcclimatisationUnknown},
+		{"≤9 saat fark, 96 saat: D", 7 * time.Hour, 96 * time.Hour, AcclimatisedToLocal},
+		{"≤12 saat fark, 96-119:59 saat: X", 12 * time.Hour, 110 * time.Hour, AcclimatisationUnknown},
+		{"≤12 saat fark, 120 saat: D", 10 * time.Hour, 120 * time.Hour, AcclimatisedToLocal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acclimatisationTableState(tt.diff, tt.elapsed); got != tt.want {
+				t.Errorf("durum = %q, beklenen %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcclimatisationTrackerAdvance(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Fatalf("zaman dilimi yüklenemedi: %v", err)
+	}
+	start := time.Date(2025, time.March, 10, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		steps  []string        // Sırayla raporlanan görev başlangıç meydanları
+		gaps   []time.Duration // Her adımın önceki raporlamadan sonraki süresi
+		want   string
+		wantTZ string
+	}{
+		{
+			name:   "3 saat farklı meydanda 48 saat sonra yerel saate aklimatize olunur",
+			steps:  []string{"IST", "LHR", "LHR"},
+			gaps:   []time.Duration{0, 4 * time.Hour, 50 * time.Hour},
+			want:   AcclimatisedToLocal,
+			wantTZ: "Europe/London",
+		},
+		{
+			name:   "3 saat farklı meydanda 48 saatten önce referans saat geçerlidir",
+			steps:  []string{"IST", "LHR", "LHR"},
+			gaps:   []time.Duration{0, 4 * time.Hour, 20 * time.Hour},
+			want:   AcclimatisedToReference,
+			wantTZ: "Europe/Istanbul",
+		},
+		{
+			name:   "7 saat farklı meydanda 48-95:59 saat arası durum bilinmez",
+			steps:  []string{"IST", "JFK", "JFK"},
+			gaps:   []time.Duration{0, 12 * time.Hour, 50 * time.Hour},
+			want:   AcclimatisationUnknown,
+			wantTZ: "Europe/Istanbul",
+		},
+		{
+			name:   "referans saatinde raporlama geçen süreyi sıfırlar",
+			steps:  []string{"IST", "LHR", "IST", "LHR"},
+			gaps:   []time.Duration{0, 4 * time.Hour, 40 * time.Hour, 20 * time.Hour},
+			want:   AcclimatisedToReference,
+			wantTZ: "Europe/Istanbul",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewAcclimatisationTracker(istanbul)
+			at := start
+			var state string
+			var reference *time.Location
+			for i, airport := range tt.steps {
+				at = at.Add(tt.gaps[i])
+				state, reference = tracker.Advance(at, airport)
+			}
+			if state != tt.want || reference.String() != tt.wantTZ {
+				t.Errorf("durum = %q (%s), beklenen %q (%s)", state, reference, tt.want, tt.wantTZ)
+			}
+		})
+	}
+}
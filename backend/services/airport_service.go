@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+)
+
+// AirportService, meydan referans verisine (zaman dilimi, koordinatlar, ana üs bayrağı) erişim ve
+// meydan yerel saati dönüşümleri sağlar. Sorgular bellekteki kayıttan (models.RegisterAirports) yapılır.
+type AirportService struct {
+	repo *repositories.AirportRepository
+}
+
+// NewAirportService, AirportService'in yeni bir örneğini oluşturur.
+func NewAirportService(repo *repositories.AirportRepository) *AirportService {
+	return &AirportService{repo: repo}
+}
+
+// Reload, airports tablosunu yeniden okuyup bellekteki meydan kaydını günceller (import sonrası çağrılır).
+func (s *AirportService) Reload(ctx context.Context) error {
+	airports, err := s.repo.ListAirports(ctx)
+	if err != nil {
+		return err
+	}
+	models.RegisterAirports(airports)
+	return nil
+}
+
+// Lookup, IATA veya ICAO koduyla meydanı döndürür. Tabloda olmayan ancak varsayılan zaman dilimi
+// haritasında bulunan meydanlar için yalnızca kod ve zaman dilimi dolu bir kayıt döner.
+func (s *AirportService) Lookup(code string) (models.Airport, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if a, ok := models.GetAirport(code); ok {
+		return a, true
+	}
+	if tz, ok := models.DefaultAirportTimeZones()[code]; ok {
+		return models.Airport{IATACode: code, TimeZone: tz}, true
+	}
+	return models.Airport{}, false
+}
+
+// LocalTime, verilen anı meydanın yerel saatine çevirir. Meydan bilinmiyorsa ikinci dönüş değeri false olur.
+func (s *AirportService) LocalTime(code string, at time.Time) (time.Time, bool) {
+	loc, ok := models.GetAirportLocation(code)
+	if !ok {
+		return at, false
+	}
+	return at.In(loc), true
+}
+
+// UTCOffsetMin, meydanın verilen andaki UTC farkını dakika olarak döndürür.
+func (s *AirportService) UTCOffsetMin(code string, at time.Time) (int, bool) {
+	local, ok := s.LocalTime(code, at)
+	if !ok {
+		return 0, false
+	}
+	_, offset := local.Zone()
+	return offset / 60, true
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Uzatılmış ekip limitlerinin uygulanabileceği azami sektör sayısı (CS FTL.1.205(c)(1))
+const maxAugmentedSectors = 3
+
+// GetMaxDailyUGSAugmented, uçuş içi dinlenme tesisi sınıfı ve kokpit ekibi sayısına göre
+// uzatılmış ekip azami UGS limitini (dakika) döndürür. Uzatılmış limit uygulanamıyorsa false döner.
+func (f *FTLCalculator) GetMaxDailyUGSAugmented(restFacilityClass, numPilots, numSectors int) (int, bool) {
+	if numPilots < 3 || numSectors > maxAugmentedSectors {
+		return 0, false
+	}
+
+	// Sütunlar: 3 pilot, 4 pilot
+	table := map[int][2]int{
+		models.RestFacilityClass1: {16 * 60, 17 * 60},
+		models.RestFacilityClass2: {15 * 60, 16*60 + 30},
+		models.RestFacilityClass3: {14 * 60, 15 * 60},
+	}
+	limits, ok := table[restFacilityClass]
+	if !ok {
+		return 0, false
+	}
+	if numPilots >= 4 {
+		return limits[1], true
+	}
+	return limits[0], true
+}
+
+// determineCrewComplement, trip'teki her FLT sektöründe görev alan kokpit ekibi sayısını ve
+// uçak tipinin dinlenme tesisi sınıfını bulur. Trip boyunca en düşük ekip sayısı ve en zayıf
+// dinlenme tesisi sınıfı esas alınır; sektör ekip verisi çekilemezse (0, RestFacilityNone) döner.
+func (f *FTLCalculator) determineCrewComplement(trip *models.Trip) (int, int) {
+	var sectors []models.Actual
+	cmsTypeSet := map[string]bool{}
+	for _, act := range trip.Activities {
+		if act.GroupCode == "FLT" && act.FlightPosition != "DH" {
+			sectors = append(sectors, act)
+			cmsTypeSet[act.PlaneCmsType] = true
+		}
+	}
+	if len(sectors) == 0 || f.actualRepo == nil {
+		return 0, models.RestFacilityNone
+	}
+
+	// Dinlenme tesisi sınıfları aircraft_types tablosunun önbelleğinden okunur
+	restFacilityClass := models.RestFacilityClass1
+	for cmsType := range cmsTypeSet {
+		class := models.GetRestFacilityClass(cmsType)
+		if class == models.RestFacilityNone {
+			restFacilityClass = models.RestFacilityNone
+			break
+		}
+		if class > restFacilityClass {
+			restFacilityClass = class
+		}
+	}
+
+	crewActuals, err := f.actualRepo.GetSectorCrewActuals(context.Background(), sectors)
+	if err != nil {
+		log.Printf("Uyarı: Trip %s için sektör ekip bilgisi çekilemedi: %v. Temel UGS limitleri uygulanacak.", trip.TripID, err)
+		return 0, restFacilityClass
+	}
+
+	pilotsBySector := map[string]map[string]bool{}
+	for _, act := range crewActuals {
+		if !models.IsFlightCrewPosition(act.FlightPosition) {
+			continue
+		}
+		key := sectorKey(act)
+		if pilotsBySector[key] == nil {
+			pilotsBySector[key] = map[string]bool{}
+		}
+		pilotsBySector[key][act.PersonID] = true
+	}
+
+	numPilots := -1
+	for _, s := range sectors {
+		count := len(pilotsBySector[sectorKey(s)])
+		if numPilots < 0 || count < numPilots {
+			numPilots = count
+		}
+	}
+	return numPilots, restFacilityClass
+}
+
+// sectorKey, bir FLT kaydını ait olduğu uçuş sektörüyle eşleştirmek için anahtar üretir.
+func sectorKey(act models.Actual) string {
+	return act.FlightNo + "|" + act.DeparturePort + "|" + act.DepartureTime.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"strings"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// isBlockTimeSector, aktivitenin uçuş süresine sayılan bir sektör olup olmadığını döndürür:
+// yalnızca FLT aktiviteleri sayılır, konumlandırma (DH) pozisyonları hariç tutulur.
+func isBlockTimeSector(act *models.Actual) bool {
+	return act.GroupCode == "FLT" && !strings.EqualFold(strings.TrimSpace(act.FlightPosition), "DH")
+}
+
+// applyBlockTime, trip'in DH hariç FLT sektörlerinin blok sürelerini (DepartureTime-ArrivalTime) hesaplar;
+// toplam blok süresini, sektör sayısını ve sektör dökümünü trip'e yazar. Varış zamanı kalkıştan önce
+// olan (eksik/hatalı) sektörler sayılmaz.
+func applyBlockTime(trip *models.Trip) {
+	trip.BlockTimeMin = 0
+	trip.SectorCount = 0
+	trip.Sectors = nil
+
+	for i := range trip.Activities {
+		act := &trip.Activities[i]
+		if !isBlockTimeSector(act) || act.DepartureTime.IsZero() || !act.ArrivalTime.After(act.DepartureTime) {
+			continue
+		}
+		blockMin := int(act.ArrivalTime.Sub(act.DepartureTime).Minutes())
+		trip.Sectors = append(trip.Sectors, models.SectorBlockTime{
+			FlightNo:      act.FlightNo,
+			DeparturePort: act.DeparturePort,
+			ArrivalPort:   act.ArrivalPort,
+			DepartureTime: act.DepartureTime,
+			ArrivalTime:   act.ArrivalTime,
+			BlockMin:      blockMin,
+		})
+		trip.BlockTimeMin += blockMin
+		trip.SectorCount++
+	}
+}
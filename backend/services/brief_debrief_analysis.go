@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// analysisOtherAirport, kurallarda adı geçmeyen meydanları temsil eden kombinasyon değeridir. Yalnızca "Hepsi"
+// ve "Diğer" meydanlı kurallar bu değerle eşleşir.
+const analysisOtherAirport = "Diğer"
+
+// isRuleWildcard, kural alanındaki joker değerleri ("Hepsi", "Diğer", aircraft_type için "Uçuş Ekibi") tanır.
+func isRuleWildcard(value string) bool {
+	return strings.EqualFold(value, "Hepsi") || strings.EqualFold(value, "Diğer") || strings.EqualFold(value, "Uçuş Ekibi")
+}
+
+// appendUnique, değer listede (büyük/küçük harf duyarsız) yoksa ekler.
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// analysisCombinations, hesaplayıcının üretebileceği ekip tipi × görev tipi × gövde sınıfı × meydan kombinasyonlarını
+// döndürür. Meydan boyutu kurallarda adı geçen meydanlar ve diğer meydanlar temsilcisinden oluşur; adı geçmeyen
+// meydanlar kural seçiminde birbirinden ayırt edilemediğinden tek temsilciyle yeterince kapsanır.
+func analysisCombinations(rules []models.BriefDebriefRule) []models.BriefDebriefCombination {
+	crewTypes := []string{models.CrewTypeFlight, models.CrewTypeCabin, models.CrewTypeUnknown}
+	dutyTypes := models.DutyTypes()
+	aircraftTypes := []string{models.AircraftBodyNarrow, models.AircraftBodyWide, models.AircraftTypeUnknown}
+
+	var airports []string
+	for _, rule := range rules {
+		if airport := strings.ToUpper(strings.TrimSpace(rule.DutyStartAirport)); airport != "" && !isRuleWildcard(rule.DutyStartAirport) {
+			airports = appendUnique(airports, airport)
+		}
+	}
+	sort.Strings(airports)
+	airports = append(airports, analysisOtherAirport)
+
+	combinations := make([]models.BriefDebriefCombination, 0, len(crewTypes)*len(dutyTypes)*len(aircraftTypes)*len(airports))
+	for _, crewType := range crewTypes {
+		for _, dutyType := range dutyTypes {
+			for _, aircraftType := range aircraftTypes {
+				for _, airport := range airports {
+					combinations = append(combinations, models.BriefDebriefCombination{
+						CrewType: crewType, ScenarioType: dutyType, AircraftType: aircraftType, DutyStartAirport: airport,
+					})
+				}
+			}
+		}
+	}
+	return combinations
+}
+
+// AnalyseRules, verilen tarihte yürürlükte olan kuralları ResolveBriefDebrief ile aynı eşleştirme ve sıralamayla
+// tüm kombinasyonlara uygular; eşleşmesiz kombinasyonları, hiç kazanamayan kuralları ve eşit öncelikli
+// çakışmaları raporlar.
+func (c *BriefDebriefCalculator) AnalyseRules(ctx context.Context, on time.Time) (*models.BriefDebriefAnalysis, error) {
+	allRules, err := c.ruleRepo.GetAllRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return analyseBriefDebriefRules(allRules, on), nil
+}
+
+// analyseBriefDebriefRules, AnalyseRules'un veritabanından bağımsız kısmıdır; allRules eşleştirme sırasında olmalıdır.
+func analyseBriefDebriefRules(allRules []models.BriefDebriefRule, on time.Time) *models.BriefDebriefAnalysis {
+	// GetAllRules eşleştirme sırasını (öncelik azalan, ID artan) korur
+	rules := make([]models.BriefDebriefRule, 0, len(allRules))
+	for _, rule := range allRules {
+		if rule.InForceOn(on) {
+			rules = append(rules, rule)
+		}
+	}
+
+	combinations := analysisCombinations(rules)
+	analysis := &models.BriefDebriefAnalysis{
+		AnalysedOn:        time.Date(on.Year(), on.Month(), on.Day(), 0, 0, 0, 0, time.UTC),
+		RuleCount:         len(rules),
+		CombinationCount:  len(combinations),
+		DefaultBriefMin:   DefaultBriefMin,
+		DefaultDebriefMin: DefaultDebriefMin,
+		Unmatched:         []models.BriefDebriefCombination{},
+		NeverWinning:      []models.BriefDebriefNeverWinningRule{},
+		Ties:              []models.BriefDebriefTie{},
+	}
+
+	matchedCount := map[int]int{}
+	wonCount := map[int]int{}
+	beatenBy := map[int]map[int]bool{}
+	tieIndex := map[string]int{}
+
+	for _, combo := range combinations {
+		var matched []models.BriefDebriefRule
+		for _, rule := range rules {
+			if ruleMatches(rule, combo.CrewType, combo.ScenarioType, combo.AircraftType, combo.DutyStartAirport) {
+				matched = append(matched, rule)
+			}
+		}
+		if len(matched) == 0 {
+			analysis.Unmatched = append(analysis.Unmatched, combo)
+			continue
+		}
+
+		winner := matched[0]
+		wonCount[winner.DataID]++
+		for _, rule := range matched {
+			matchedCount[rule.DataID]++
+			if rule.DataID != winner.DataID {
+				if beatenBy[rule.DataID] == nil {
+					beatenBy[rule.DataID] = map[int]bool{}
+				}
+				beatenBy[rule.DataID][winner.DataID] = true
+			}
+		}
+
+		// Kazananla aynı öncelikteki diğer eşleşen kurallar
+		tied := []int{winner.DataID}
+		durationsDiffer := false
+		for _, rule := range matched[1:] {
+			if rule.Priority != winner.Priority {
+				break
+			}
+			tied = append(tied, rule.DataID)
+			if rule.BriefDurationMin != winner.BriefDurationMin || rule.DebriefDurationMin != winner.DebriefDurationMin {
+				durationsDiffer = true
+			}
+		}
+		if len(tied) < 2 {
+			continue
+		}
+		key := fmt.Sprint(winner.Priority, tied)
+		idx, ok := tieIndex[key]
+		if !ok {
+			idx = len(analysis.Ties)
+			tieIndex[key] = idx
+			analysis.Ties = append(analysis.Ties, models.BriefDebriefTie{
+				Priority: winner.Priority, RuleIDs: tied, WinnerRuleID: winner.DataID, DurationsDiffer: durationsDiffer,
+			})
+		}
+		analysis.Ties[idx].Combinations = append(analysis.Ties[idx].Combinations, combo)
+	}
+
+	for _, rule := range rules {
+		if wonCount[rule.DataID] > 0 {
+			continue
+		}
+		entry := models.BriefDebriefNeverWinningRule{Rule: rule, MatchedCombinations: matchedCount[rule.DataID]}
+		if entry.MatchedCombinations == 0 {
+			entry.Reason = models.RuleNeverWinsUnreachable
+		} else {
+			entry.Reason = models.RuleNeverWinsShadowed
+			for id := range beatenBy[rule.DataID] {
+				entry.ShadowedBy = append(entry.ShadowedBy, id)
+			}
+			sort.Ints(entry.ShadowedBy)
+		}
+		analysis.NeverWinning = append(analysis.NeverWinning, entry)
+	}
+	return analysis
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// analysisRule, test kuralı oluşturur; kurallar eşleştirme sırasında (öncelik azalan, ID artan) verilmelidir.
+func analysisRule(id, priority int, crewType, scenarioType, aircraftType, airport string, briefMin, debriefMin int) models.BriefDebriefRule {
+	return models.BriefDebriefRule{
+		DataID: id, Priority: priority, CrewType: crewType, ScenarioType: scenarioType, AircraftType: aircraftType,
+		DutyStartAirport: airport, BriefDurationMin: briefMin, DebriefDurationMin: debriefMin,
+	}
+}
+
+func TestAnalyseBriefDebriefRules(t *testing.T) {
+	on := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	expired := time.Date(2025, time.March, 9, 0, 0, 0, 0, time.UTC)
+	started := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	// Kombinasyon sayısı: 3 ekip tipi × görev tipi sayısı × 3 gövde sınıfı × meydan sayısı
+	perAirport := 3 * len(models.DutyTypes()) * 3
+
+	type neverWinning struct {
+		reason     string
+		matched    int
+		shadowedBy []int
+	}
+	type tie struct {
+		ruleIDs         []int
+		winner          int
+		durationsDiffer bool
+		combinations    int
+	}
+
+	tests := []struct {
+		name             string
+		rules            []models.BriefDebriefRule
+		wantRuleCount    int
+		wantCombinations int
+		wantUnmatched    int
+		unmatched        []models.BriefDebriefCombination // Eşleşmesiz listede bulunması gereken kombinasyonlar
+		wantNeverWinning map[int]neverWinning
+		wantTies         []tie
+	}{
+		{
+			name: "Hepsi kuralı tüm kombinasyonları kapsar ve alttaki kuralı gölgeler",
+			rules: []models.BriefDebriefRule{
+				analysisRule(1, 100, "Hepsi", "Hepsi", "Hepsi", "Hepsi", 60, 30),
+				analysisRule(2, 50, models.CrewTypeFlight, "Yolculu Uçuşlar", models.AircraftBodyNarrow, "IST", 75, 30),
+			},
+			wantRuleCount:    2,
+			wantCombinations: perAirport * 2, // IST ve Diğer
+			wantUnmatched:    0,
+			wantNeverWinning: map[int]neverWinning{
+				2: {reason: models.RuleNeverWinsShadowed, matched: 1, shadowedBy: []int{1}},
+			},
+		},
+		{
+			name: "üretilmeyen ekip tipi ulaşılamaz, tüm kombinasyonlar eşleşmesiz kalır",
+			rules: []models.BriefDebriefRule{
+				analysisRule(1, 100, "Kargo Uçuş Ekibi", "Hepsi", "Hepsi", "Hepsi", 60, 30),
+			},
+			wantRuleCount:    1,
+			wantCombinations: perAirport, // Yalnızca Diğer
+			wantUnmatched:    perAirport,
+			unmatched: []models.BriefDebriefCombination{
+				{CrewType: models.CrewTypeCabin, ScenarioType: "Simülatör", AircraftType: models.AircraftBodyWide, DutyStartAirport: "Diğer"},
+			},
+			wantNeverWinning: map[int]neverWinning{
+				1: {reason: models.RuleNeverWinsUnreachable, matched: 0},
+			},
+		},
+		{
+			name: "eşit öncelikte küçük ID kazanır ve farklı süreler raporlanır",
+			rules: []models.BriefDebriefRule{
+				analysisRule(1, 80, models.CrewTypeCabin, "Simülatör", "Hepsi", "Hepsi", 60, 60),
+				analysisRule(2, 80, models.CrewTypeCabin, "Simülatör", "Hepsi", "Hepsi", 45, 60),
+			},
+			wantRuleCount:    2,
+			wantCombinations: perAirport,
+			wantUnmatched:    perAirport - 3,
+			wantNeverWinning: map[int]neverWinning{
+				2: {reason: models.RuleNeverWinsShadowed, matched: 3, shadowedBy: []int{1}},
+			},
+			wantTies: []tie{{ruleIDs: []int{1, 2}, winner: 1, durationsDiffer: true, combinations: 3}},
+		},
+		{
+			name: "adı geçen meydanda meydan kuralı, diğerlerinde Diğer kuralı kazanır; Uçuş Ekibi uçak tipi yalnızca bilinmeyen tipi kapsar",
+			rules: []models.BriefDebriefRule{
+				analysisRule(1, 90, models.CrewTypeFlight, "Yolculu Uçuşlar", models.AircraftBodyNarrow, "IST", 75, 30),
+				analysisRule(2, 80, models.CrewTypeFlight, "Yolculu Uçuşlar", models.AircraftBodyNarrow, "Diğer", 60, 30),
+				analysisRule(3, 70, models.CrewTypeFlight, "Simülatör", "Uçuş Ekibi", "Hepsi", 60, 60),
+			},
+			wantRuleCount:    3,
+			wantCombinations: perAirport * 2,
+			wantUnmatched:    perAirport*2 - 4,
+			unmatched: []models.BriefDebriefCombination{
+				{CrewType: models.CrewTypeFlight, ScenarioType: "Yolculu Uçuşlar", AircraftType: models.AircraftBodyWide, DutyStartAirport: "IST"},
+				{CrewType: models.CrewTypeFlight, ScenarioType: "Simülatör", AircraftType: models.AircraftBodyNarrow, DutyStartAirport: "Diğer"},
+			},
+			wantNeverWinning: map[int]neverWinning{},
+		},
+		{
+			name: "daha yüksek öncelikli Diğer meydan kuralı adı geçen meydan kuralını gölgeler",
+			rules: []models.BriefDebriefRule{
+				analysisRule(1, 90, models.CrewTypeCabin, "Açık Mesai", "Hepsi", "Diğer", 60, 15),
+				analysisRule(2, 80, models.CrewTypeCabin, "Açık Mesai", "Hepsi", "SAW", 45, 15),
+			},
+			wantRuleCount:    2,
+			wantCombinations: perAirport * 2,
+			wantUnmatched:    perAirport*2 - 6,
+			wantNeverWinning: map[int]neverWinning{
+				2: {reason: models.RuleNeverWinsShadowed, matched: 3, shadowedBy: []int{1}},
+			},
+		},
+		{
+			name: "yürürlükte olmayan kural analize girmez",
+			rules: []models.BriefDebriefRule{
+				{DataID: 1, Priority: 100, CrewType: "Hepsi", ScenarioType: "Hepsi", AircraftType: "Hepsi", DutyStartAirport: "IST", ValidTo: &expired},
+				{DataID: 2, Priority: 10, CrewType: "Hepsi", ScenarioType: "Hepsi", AircraftType: "Hepsi", DutyStartAirport: "Hepsi", ValidFrom: &started},
+			},
+			wantRuleCount:    1,
+			wantCombinations: perAirport, // IST yalnızca yürürlükten kalkmış kuralda geçiyor
+			wantUnmatched:    0,
+			wantNeverWinning: map[int]neverWinning{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := analyseBriefDebriefRules(tt.rules, on)
+
+			if analysis.RuleCount != tt.wantRuleCount {
+				t.Errorf("kural sayısı = %d, beklenen %d", analysis.RuleCount, tt.wantRuleCount)
+			}
+			if analysis.CombinationCount != tt.wantCombinations {
+				t.Errorf("kombinasyon sayısı = %d, beklenen %d", analysis.CombinationCount, tt.wantCombinations)
+			}
+			if len(analysis.Unmatched) != tt.wantUnmatched {
+				t.Errorf("eşleşmesiz kombinasyon sayısı = %d, beklenen %d", len(analysis.Unmatched), tt.wantUnmatched)
+			}
+			for _, want := range tt.unmatched {
+				found := false
+				for _, got := range analysis.Unmatched {
+					if got == want {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("eşleşmesiz listede %+v yok", want)
+				}
+			}
+
+			got := map[int]neverWinning{}
+			for _, entry := range analysis.NeverWinning {
+				got[entry.Rule.DataID] = neverWinning{reason: entry.Reason, matched: entry.MatchedCombinations, shadowedBy: entry.ShadowedBy}
+			}
+			if !reflect.DeepEqual(got, tt.wantNeverWinning) {
+				t.Errorf("hiç kazanamayan kurallar = %+v, beklenen %+v", got, tt.wantNeverWinning)
+			}
+
+			if len(analysis.Ties) != len(tt.wantTies) {
+				t.Fatalf("çakışma sayısı = %d, beklenen %d", len(analysis.Ties), len(tt.wantTies))
+			}
+			for i, want := range tt.wantTies {
+				gotTie := analysis.Ties[i]
+				if !reflect.DeepEqual(gotTie.RuleIDs, want.ruleIDs) || gotTie.WinnerRuleID != want.winner ||
+					gotTie.DurationsDiffer != want.durationsDiffer || len(gotTie.Combinations) != want.combinations {
+					t.Errorf("çakışma %d = %+v, beklenen %+v", i, gotTie, want)
+				}
+			}
+		})
+	}
+}
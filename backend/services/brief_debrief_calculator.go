@@ -3,8 +3,10 @@ package services
 import (
 	"context"
 	"log"
+	"mini_CMS_Desktop_App/models"
 	"mini_CMS_Desktop_App/repositories"
 	"strings"
+	"time"
 )
 
 // BriefDebriefCalculator hesaplayıcı yapı
@@ -17,44 +19,99 @@ func NewBriefDebriefCalculator(ruleRepo *repositories.BriefDebriefRuleRepository
 	return &BriefDebriefCalculator{ruleRepo: ruleRepo}
 }
 
-// Brief ve debrief sürelerini hesaplar
+// Hiçbir kural eşleşmediğinde uygulanan brief/debrief süreleri (dakika)
+const (
+	DefaultBriefMin   = 60
+	DefaultDebriefMin = 30
+)
+
+// Brief ve debrief sürelerini görev tarihinde yürürlükte olan kural versiyonlarına göre hesaplar
 func (c *BriefDebriefCalculator) GetBriefDebriefDurations(
 	ctx context.Context,
 	crewType string,
 	dutyType string,
 	aircraftType string,
 	dutyStartAirport string,
+	dutyDate time.Time,
 ) (briefMin, debriefMin int) {
+	briefMin, debriefMin, _ = c.ResolveBriefDebrief(ctx, crewType, dutyType, aircraftType, dutyStartAirport, dutyDate)
+	return briefMin, debriefMin
+}
+
+// BriefDebriefDutyDate, kural versiyonu seçiminde kullanılan görev tarihini (görev başlangıcının başlangıç
+// meydanındaki yerel takvim günü) döndürür.
+func BriefDebriefDutyDate(trip *models.Trip) time.Time {
+	start := trip.FirstLegDepartureTime
+	if len(trip.Activities) > 0 && !trip.Activities[0].DutyStart.IsZero() {
+		start = trip.Activities[0].DutyStart
+	}
+	if loc, ok := models.GetAirportLocation(trip.DutyStartAirport); ok {
+		return start.In(loc)
+	}
+	return start
+}
 
+// ResolveBriefDebrief, GetBriefDebriefDurations ile aynı eşleştirmeyi yapar ve eşleşen kuralın ID'sini de döndürür.
+// Görev tarihinde yürürlükte olmayan versiyonlar atlanır (tarih verilmezse bugün esas alınır). Hiçbir kural
+// eşleşmezse veya kurallar yüklenemezse varsayılan süreler ve 0 ID döner.
+func (c *BriefDebriefCalculator) ResolveBriefDebrief(
+	ctx context.Context,
+	crewType string,
+	dutyType string,
+	aircraftType string,
+	dutyStartAirport string,
+	dutyDate time.Time,
+) (briefMin, debriefMin, ruleID int) {
 	// Kuralları önceden sıralı şekilde getir
-	rules, err := c.ruleRepo.GetAllRules(ctx)
+	rules, err := c.loadRules(ctx)
 	if err != nil {
 		log.Printf("[BriefDebriefCalc] ❗ Kural yükleme hatası: %v — Varsayılan değerler kullanılacak.", err)
-		return 60, 30
+		return DefaultBriefMin, DefaultDebriefMin, 0
+	}
+	return resolveBriefDebrief(rules, crewType, dutyType, aircraftType, dutyStartAirport, dutyDate)
+}
+
+// loadRules, eşleştirmede kullanılan kuralları öncelik sırasıyla (öncelik azalan, ID artan) getirir. Aynı kurallarla
+// birden fazla trip hesaplanacaksa kurallar bir kez yüklenip resolveBriefDebrief ile kullanılır.
+func (c *BriefDebriefCalculator) loadRules(ctx context.Context) ([]models.BriefDebriefRule, error) {
+	return c.ruleRepo.GetAllRules(ctx)
+}
+
+// resolveBriefDebrief, öncelik sırasıyla verilen kurallardan görev tarihinde yürürlükte olan ilk eşleşeni seçer.
+// Hiçbir kural eşleşmezse varsayılan süreler ve 0 ID döner.
+func resolveBriefDebrief(
+	rules []models.BriefDebriefRule,
+	crewType string,
+	dutyType string,
+	aircraftType string,
+	dutyStartAirport string,
+	dutyDate time.Time,
+) (briefMin, debriefMin, ruleID int) {
+	if dutyDate.IsZero() {
+		dutyDate = time.Now()
 	}
 
 	// Kuralları sırayla değerlendir (öncelik yüksek → düşük)
 	for _, rule := range rules {
-		if !matches(rule.CrewType, crewType) {
-			continue
-		}
-		if !matches(rule.ScenarioType, dutyType) {
-			continue
-		}
-		if !airportMatches(rule.DutyStartAirport, dutyStartAirport) {
-			continue
-		}
-		if !aircraftMatches(rule.AircraftType, aircraftType) {
+		if !rule.InForceOn(dutyDate) || !ruleMatches(rule, crewType, dutyType, aircraftType, dutyStartAirport) {
 			continue
 		}
 
 		// Eşleşme bulundu
 		log.Printf("[BriefDebriefCalc] ✅ Kural eşleşti → ID:%d, Brief:%d dk, Debrief:%d dk", rule.DataID, rule.BriefDurationMin, rule.DebriefDurationMin)
-		return rule.BriefDurationMin, rule.DebriefDurationMin
+		return rule.BriefDurationMin, rule.DebriefDurationMin, rule.DataID
 	}
 
 	log.Printf("[BriefDebriefCalc] ⚠️ Hiçbir kural eşleşmedi. Varsayılan değerler uygulanacak.")
-	return 60, 30
+	return DefaultBriefMin, DefaultDebriefMin, 0
+}
+
+// ruleMatches, kuralın verilen girdilerle eşleşip eşleşmediğini döndürür (geçerlilik tarihi hariç).
+func ruleMatches(rule models.BriefDebriefRule, crewType, dutyType, aircraftType, dutyStartAirport string) bool {
+	return matches(rule.CrewType, crewType) &&
+		matches(rule.ScenarioType, dutyType) &&
+		airportMatches(rule.DutyStartAirport, dutyStartAirport) &&
+		aircraftMatches(rule.AircraftType, aircraftType)
 }
 
 // Metin karşılaştırmalarında eşleşme kontrolü
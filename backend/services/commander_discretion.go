@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Kaptan takdiriyle UGS'ye eklenebilecek azami süre (ORO.FTL.205(f)(1)(i))
+const (
+	maxCommanderDiscretionMin          = 2 * 60
+	maxCommanderDiscretionAugmentedMin = 3 * 60 // Uzatılmış ekipte
+)
+
+// latestDiscretions, verilen tripler için kaydedilmiş en son kaptan takdirlerini getirir. Depo tanımlı değilse veya
+// sorgu başarısız olursa nil döner (limit aşımları ihlal olarak raporlanır).
+func (f *FTLCalculator) latestDiscretions(ctx context.Context, trips []*models.Trip) map[string]models.CommanderDiscretion {
+	if f.discretionRepo == nil {
+		return nil
+	}
+	tripIDs := make([]string, 0, len(trips))
+	for _, t := range trips {
+		tripIDs = append(tripIDs, t.TripID)
+	}
+	discretions, err := f.discretionRepo.GetLatestDiscretionsByTripIDs(ctx, tripIDs)
+	if err != nil {
+		log.Printf("Uyarı: Kaptan takdirleri çekilemedi: %v. Limit aşımları ihlal olarak raporlanacak.", err)
+		return nil
+	}
+	return discretions
+}
+
+// coveredByCommanderDiscretion, UGS limit aşımının trip için kaydedilmiş en son kaptan takdiri (yoksa nil) ile
+// karşılanıp karşılanmadığını döndürür. Aşım hem kaydedilen uzatmayı hem de yasal üst sınırı geçemez.
+func coveredByCommanderDiscretion(discretion *models.CommanderDiscretion, excessMin int, augmented bool) bool {
+	if discretion == nil {
+		return false
+	}
+
+	maxMin := maxCommanderDiscretionMin
+	if augmented {
+		maxMin = maxCommanderDiscretionAugmentedMin
+	}
+	return excessMin <= discretion.ExtensionMin && excessMin <= maxMin
+}
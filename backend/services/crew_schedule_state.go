@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// crewScheduleState, bir ekip üyesinin programı görev sırasıyla hesaplanırken önceki triplerden taşınan
+// durumdur: aklimatizasyon, saat dilimi rotasyonu, uzatılmış dinlenme döngüsü, ardışık gece serisi, kümülatif
+// pencereler ve yorgunluk geçmişi. Her trip hesaplandıktan sonra duruma katılır; böylece tüm program trip başına
+// listeyi baştan taramadan tek geçişte hesaplanır. Trip'ten bağımsız referans veriler (kural setleri, mevzuat
+// atamaları, brief/debrief kuralları, kaptan takdirleri) program başında bir kez yüklenir.
+type crewScheduleState struct {
+	crewBaseAirport   string
+	baseLocation      *time.Location
+	preferredLocation *time.Location
+	baseAirports      map[string]string
+
+	loadedAt              time.Time // Referans veriler okunmadan önceki an; trip'lerin hesaplanma zamanı olarak kaydedilir
+	ruleSets              []models.FTLRuleSet
+	regulationAssignments map[string]map[string]string
+	briefDebriefRules     []models.BriefDebriefRule
+	discretions           map[string]models.CommanderDiscretion // trip_id → en son kaptan takdiri
+
+	acclimatisation *AcclimatisationTracker
+	rotation        timeZoneRotation
+	cycle           recoveryCycle
+	nights          nightDutySeries
+	engine          *CumulativeWindowEngine
+	history         []*models.Trip // Hesaplanmış tripler, görev başlangıcı sırasıyla
+	prev            *models.Trip
+}
+
+// newCrewScheduleState, ekip üyesinin ana üssü, ana üs meydanları ve trips için referans verilerle boş bir program
+// durumu oluşturur. Ekip ana üssünde aklimatize başlar.
+func (f *FTLCalculator) newCrewScheduleState(ctx context.Context, crewID string, trips []*models.Trip) *crewScheduleState {
+	loadedAt := time.Now()
+	preferredLocation := f.preferredLocation(crewID)
+	crewBaseAirport, baseLocation := f.crewBase(ctx, crewID, preferredLocation)
+	return &crewScheduleState{
+		crewBaseAirport:       crewBaseAirport,
+		baseLocation:          baseLocation,
+		preferredLocation:     preferredLocation,
+		baseAirports:          f.baseAirportMap(ctx),
+		loadedAt:              loadedAt,
+		ruleSets:              f.activeRuleSets(ctx),
+		regulationAssignments: f.regulationAssignments(ctx),
+		briefDebriefRules:     f.briefDebriefRules(ctx),
+		discretions:           f.latestDiscretions(ctx, trips),
+		acclimatisation:       NewAcclimatisationTracker(baseLocation),
+		engine:                NewCumulativeWindowEngine(nil),
+	}
+}
+
+// discretionFor, trip için program başında yüklenen en son kaptan takdirini döndürür (yoksa nil).
+func (s *crewScheduleState) discretionFor(tripID string) *models.CommanderDiscretion {
+	d, ok := s.discretions[tripID]
+	if !ok {
+		return nil
+	}
+	return &d
+}
+
+// commit, hesaplanmış trip'i sonraki tripler için rotasyon, yorgunluk geçmişi ve önceki trip durumuna katar.
+// Aklimatizasyon, döngü, gece serisi ve kümülatif pencereler trip hesaplanırken güncellenir.
+func (s *crewScheduleState) commit(t *models.Trip) {
+	if !t.CalculatedDutyPeriodStart.IsZero() {
+		s.rotation.add(t, s.crewBaseAirport, s.baseAirports, s.baseLocation)
+		s.history = append(s.history, t)
+	}
+	s.prev = t
+}
+
+// replayUntil, listede trip'ten önce gelen ve daha erken başlayan kayıtlı tripleri yeniden hesaplamadan
+// (kayıtlı değerleriyle) duruma katar. Tek trip hesabında geçmiş bu şekilde bir kez taranır.
+func (s *crewScheduleState) replayUntil(trip *models.Trip, allCrewTrips []*models.Trip) {
+	tripStart := tripReportTime(trip)
+	for _, t := range allCrewTrips {
+		if t.TripID == trip.TripID {
+			break
+		}
+		reportTime := tripReportTime(t)
+		if !reportTime.Before(tripStart) {
+			break
+		}
+		s.acclimatisation.Advance(reportTime, t.DutyStartAirport)
+		if !t.CalculatedDutyPeriodStart.IsZero() {
+			s.cycle.step(t, s.baseLocation, 0)
+			s.nights.step(t, s.baseLocation, 0)
+			s.engine.Add(t)
+		}
+		s.commit(t)
+	}
+}
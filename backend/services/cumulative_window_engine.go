@@ -0,0 +1,207 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// windowEntry, kümülatif pencereye giren tek bir trip'in anahtar anını ve katkısını tutar.
+type windowEntry struct {
+	at     time.Time
+	value  int
+	order  int // Trip'in listeye eklenme sırası (katkıda bulunan trip ID'lerini liste sırasıyla döndürmek için)
+	tripID string
+}
+
+// windowSeries, anahtar anına göre sıralı trip katkılarını ve önek toplamlarını tutar.
+// prefix[i], entries[0:i] katkılarının toplamıdır; aralık toplamı iki ikili arama ile bulunur.
+type windowSeries struct {
+	entries []windowEntry
+	prefix  []int
+}
+
+// add, kaydı sıralı konumuna ekler. Kayıtlar çoğunlukla artan sırada geldiği için ekleme genellikle
+// sona yapılır; sıra dışı bir kayıtta önek toplamları yalnızca ekleme noktasından itibaren yeniden hesaplanır.
+func (s *windowSeries) add(e windowEntry) {
+	if s.prefix == nil {
+		s.prefix = []int{0}
+	}
+	i := len(s.entries)
+	if i > 0 && e.at.Before(s.entries[i-1].at) {
+		i = sort.Search(len(s.entries), func(k int) bool { return s.entries[k].at.After(e.at) })
+	}
+	s.entries = append(s.entries, windowEntry{})
+	copy(s.entries[i+1:], s.entries[i:])
+	s.entries[i] = e
+
+	s.prefix = s.prefix[:i+1]
+	for k := i; k < len(s.entries); k++ {
+		s.prefix = append(s.prefix, s.prefix[k]+s.entries[k].value)
+	}
+}
+
+// rebuild, kayıtları anahtar anına göre (eşitlikte ekleme sırasını koruyarak) sıralar ve önek toplamlarını hesaplar.
+func (s *windowSeries) rebuild() {
+	sort.SliceStable(s.entries, func(i, j int) bool { return s.entries[i].at.Before(s.entries[j].at) })
+	s.prefix = make([]int, len(s.entries)+1)
+	for i, e := range s.entries {
+		s.prefix[i+1] = s.prefix[i] + e.value
+	}
+}
+
+// openRange, from < at < to koşulunu sağlayan kayıtların [lo, hi) indeks aralığını döndürür.
+func (s *windowSeries) openRange(from, to time.Time) (int, int) {
+	lo := sort.Search(len(s.entries), func(k int) bool { return s.entries[k].at.After(from) })
+	hi := sort.Search(len(s.entries), func(k int) bool { return !s.entries[k].at.Before(to) })
+	return lo, hi
+}
+
+// halfOpenRange, from <= at < to koşulunu sağlayan kayıtların [lo, hi) indeks aralığını döndürür.
+func (s *windowSeries) halfOpenRange(from, to time.Time) (int, int) {
+	lo := sort.Search(len(s.entries), func(k int) bool { return !s.entries[k].at.Before(from) })
+	hi := sort.Search(len(s.entries), func(k int) bool { return !s.entries[k].at.Before(to) })
+	return lo, hi
+}
+
+// sum, [lo, hi) aralığındaki katkıların toplamını döndürür.
+func (s *windowSeries) sum(lo, hi int) int {
+	if hi <= lo {
+		return 0
+	}
+	return s.prefix[hi] - s.prefix[lo]
+}
+
+// tripIDs, [lo, hi) aralığındaki trip ID'lerini listeye eklenme sırasıyla döndürür.
+func (s *windowSeries) tripIDs(lo, hi int) []string {
+	if hi <= lo {
+		return nil
+	}
+	window := s.entries[lo:hi]
+	for k := 1; k < len(window); k++ {
+		if window[k].order < window[k-1].order {
+			window = append([]windowEntry(nil), window...)
+			sort.Slice(window, func(i, j int) bool { return window[i].order < window[j].order })
+			break
+		}
+	}
+	ids := make([]string, len(window))
+	for i, e := range window {
+		ids[i] = e.tripID
+	}
+	return ids
+}
+
+// CumulativeWindowEngine, 7/14/28 günlük, yıllık ve 12 aylık kümülatif görev ve uçuş sürelerini önek toplamlarıyla
+// hesaplar. Her pencere iki ikili arama ile bulunduğundan bir ekibin tüm programı, trip başına tüm listeyi
+// yeniden taramadan tek geçişte hesaplanır.
+type CumulativeWindowEngine struct {
+	duty   windowSeries // Anahtar: CalculatedDutyPeriodEnd, katkı: sayılan görev süresi
+	flight windowSeries // Anahtar: LastLegArrivalTime, katkı: blok süresi (DH hariç)
+	added  int
+}
+
+// NewCumulativeWindowEngine, verilen trip listesinin mevcut değerleriyle bir motor oluşturur.
+// Boş listeyle oluşturulan motor, trip'ler hesaplandıkça Add ile doldurulur.
+func NewCumulativeWindowEngine(trips []*models.Trip) *CumulativeWindowEngine {
+	e := &CumulativeWindowEngine{}
+	for _, t := range trips {
+		e.append(t)
+	}
+	e.duty.rebuild()
+	e.flight.rebuild()
+	return e
+}
+
+func (e *CumulativeWindowEngine) append(t *models.Trip) {
+	e.duty.entries = append(e.duty.entries, windowEntry{at: t.CalculatedDutyPeriodEnd, value: countedDutyMin(t), order: e.added, tripID: t.TripID})
+	e.flight.entries = append(e.flight.entries, windowEntry{at: t.LastLegArrivalTime, value: t.BlockTimeMin, order: e.added, tripID: t.TripID})
+	e.added++
+}
+
+// Add, hesaplanmış bir trip'i motora ekler. Trip'ler program sırasıyla eklendiğinde her sorgu,
+// o ana kadar hesaplanmış trip'ler üzerinden yapılır.
+func (e *CumulativeWindowEngine) Add(t *models.Trip) {
+	e.duty.add(windowEntry{at: t.CalculatedDutyPeriodEnd, value: countedDutyMin(t), order: e.added, tripID: t.TripID})
+	e.flight.add(windowEntry{at: t.LastLegArrivalTime, value: t.BlockTimeMin, order: e.added, tripID: t.TripID})
+	e.added++
+}
+
+// windowVisitor, bir kümülatif pencerenin seri üzerindeki [lo, hi) aralığını, limitini ve sınırlarını alır.
+type windowVisitor func(series *windowSeries, ruleCode string, lo, hi, limit int, windowStart, windowStop time.Time)
+
+// visitWindows, trip'in görev bitişinde sona eren tüm kümülatif pencereleri sırayla visit'e verir.
+// Kayan pencereler (bitiş-N gün, bitiş+1 dk) açık aralığı, yıllık pencereler loc'a göre takvim yılını kapsar.
+// Limiti 0 olan (mevzuat çerçevesinde bulunmayan) pencereler atlanır.
+func (e *CumulativeWindowEngine) visitWindows(trip *models.Trip, ruleSet models.FTLRuleSet, loc *time.Location, visit windowVisitor) {
+	now := trip.CalculatedDutyPeriodEnd.In(loc)
+	windowEnd := now.Add(1 * time.Minute)
+	yearStart := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+	twentyEightDaysAgo := now.AddDate(0, 0, -28)
+
+	check := func(series *windowSeries, ruleCode string, lo, hi, limit int, windowStart, windowStop time.Time) {
+		if limit <= 0 { // Mevzuat çerçevesinde bulunmayan limit
+			return
+		}
+		visit(series, ruleCode, lo, hi, limit, windowStart, windowStop)
+	}
+
+	for _, w := range []struct {
+		days     int
+		ruleCode string
+		limit    int
+	}{
+		{7, models.RuleMaxDutyPeriod7DaysExceeded, ruleSet.MaxDuty7DaysMin},
+		{14, models.RuleMaxDutyPeriod14DaysExceeded, ruleSet.MaxDuty14DaysMin},
+		{28, models.RuleMaxDutyPeriod28DaysExceeded, ruleSet.MaxDuty28DaysMin},
+	} {
+		from := now.AddDate(0, 0, -w.days)
+		lo, hi := e.duty.openRange(from, windowEnd)
+		check(&e.duty, w.ruleCode, lo, hi, w.limit, from, now)
+	}
+
+	lo, hi := e.duty.halfOpenRange(yearStart, yearEnd)
+	check(&e.duty, models.RuleMaxDutyPeriodYearExceeded, lo, hi, ruleSet.MaxDutyYearMin, yearStart, yearEnd)
+
+	lo, hi = e.flight.openRange(twentyEightDaysAgo, windowEnd)
+	check(&e.flight, models.RuleMaxFlightTime28DaysExceeded, lo, hi, ruleSet.MaxFlight28DaysMin, twentyEightDaysAgo, now)
+
+	twelveMonthsAgo := now.AddDate(0, -12, 0)
+	lo, hi = e.flight.openRange(twelveMonthsAgo, windowEnd)
+	check(&e.flight, models.RuleMaxFlightTime12MonthsExceeded, lo, hi, ruleSet.MaxFlight12MonthsMin, twelveMonthsAgo, now)
+
+	lo, hi = e.flight.halfOpenRange(yearStart, yearEnd)
+	check(&e.flight, models.RuleMaxFlightTimeYearExceeded, lo, hi, ruleSet.MaxFlightYearMin, yearStart, yearEnd)
+}
+
+// Violations, trip'in görev bitişinde sona eren kümülatif pencerelerdeki limit aşımlarını döndürür.
+func (e *CumulativeWindowEngine) Violations(trip *models.Trip, ruleSet models.FTLRuleSet, loc *time.Location) []models.FTLViolation {
+	var violations []models.FTLViolation
+	e.visitWindows(trip, ruleSet, loc, func(series *windowSeries, ruleCode string, lo, hi, limit int, windowStart, windowStop time.Time) {
+		if total := series.sum(lo, hi); total > limit {
+			violations = append(violations, newCumulativeViolation(ruleCode, windowStart, windowStop, total, limit, series.tripIDs(lo, hi)))
+		}
+	})
+	return violations
+}
+
+// Windows, limit aşılmasa da trip'in görev bitişinde sona eren tüm kümülatif pencerelerin toplamını ve
+// toplama katkı veren tripleri döndürür (denetim izi için).
+func (e *CumulativeWindowEngine) Windows(trip *models.Trip, ruleSet models.FTLRuleSet, loc *time.Location) []models.CumulativeWindowTotal {
+	var windows []models.CumulativeWindowTotal
+	e.visitWindows(trip, ruleSet, loc, func(series *windowSeries, ruleCode string, lo, hi, limit int, windowStart, windowStop time.Time) {
+		total := series.sum(lo, hi)
+		windows = append(windows, models.CumulativeWindowTotal{
+			RuleCode:            ruleCode,
+			WindowStart:         windowStart,
+			WindowEnd:           windowStop,
+			TotalMin:            total,
+			LimitMin:            limit,
+			Exceeded:            total > limit,
+			ContributingTripIDs: series.tripIDs(lo, hi),
+		})
+	})
+	return windows
+}
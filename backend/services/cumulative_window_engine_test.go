@@ -0,0 +1,170 @@
+package services
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// syntheticSchedule, 10-40 saat arası dinlenmelerle ayrılmış 4-13 saatlik görevlerden oluşan bir program üretir.
+func syntheticSchedule(rng *rand.Rand, crewID string, days int, loc *time.Location) []*models.Trip {
+	var trips []*models.Trip
+	start := time.Date(2024, time.January, 1, 6, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, days)
+	for at := start; at.Before(end); {
+		dutyMin := 240 + rng.Intn(540)
+		flightMin := dutyMin - 60 - rng.Intn(30)
+		dutyEnd := at.Add(time.Duration(dutyMin) * time.Minute)
+		trips = append(trips, &models.Trip{
+			TripID:                          crewID + "-" + strconv.Itoa(len(trips)),
+			CrewMemberID:                    crewID,
+			FirstLegDepartureTime:           at.Add(time.Hour),
+			LastLegArrivalTime:              dutyEnd.Add(-30 * time.Minute),
+			CalculatedDutyPeriodStart:       at,
+			CalculatedDutyPeriodEnd:         dutyEnd,
+			CalculatedDutyPeriodDurationMin: dutyMin,
+			CountedDutyMin:                  dutyMin,
+			BlockTimeMin:                    flightMin,
+		})
+		at = dutyEnd.Add(time.Duration(600+rng.Intn(1800)) * time.Minute)
+	}
+	return trips
+}
+
+// syntheticSchedules, karşılaştırma ve ölçüm için sabit tohumlu ekip programları ile ihlal üreten dalları da
+// kapsayan sıkı bir kural seti döndürür.
+func syntheticSchedules(tb testing.TB, crews, days int) ([][]*models.Trip, models.FTLRuleSet, *time.Location) {
+	tb.Helper()
+	loc, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		tb.Skipf("zaman dilimi yüklenemedi: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	schedules := make([][]*models.Trip, crews)
+	for i := range schedules {
+		schedules[i] = syntheticSchedule(rng, strconv.Itoa(i), days, loc)
+	}
+
+	ruleSet := models.DefaultFTLRuleSet()
+	ruleSet.MaxDuty7DaysMin = 40 * 60
+	ruleSet.MaxFlight28DaysMin = 80 * 60
+	return schedules, ruleSet, loc
+}
+
+// legacyCumulativeViolations, CumulativeWindowEngine öncesindeki pencere başına tam tarama yöntemidir: her pencere
+// için trip listesi baştan taranır ve her zaman damgası yeniden loc'a çevrilir. Katkı değerleri motorla aynıdır
+// (sayılan görev süresi ve DH hariç blok süresi); karşılaştırılan yalnızca pencere ve toplama yöntemidir.
+func legacyCumulativeViolations(trip *models.Trip, allCrewTrips []*models.Trip, ruleSet models.FTLRuleSet, loc *time.Location) []models.FTLViolation {
+	var violations []models.FTLViolation
+	now := trip.CalculatedDutyPeriodEnd.In(loc)
+	windowEnd := now.Add(1 * time.Minute)
+	currentYear := now.Year()
+	yearStart := time.Date(currentYear, time.January, 1, 0, 0, 0, 0, loc)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+
+	rolling := func(ruleCode string, from time.Time, limit int, flight bool) {
+		total := 0
+		var ids []string
+		for _, t := range allCrewTrips {
+			at, value := t.CalculatedDutyPeriodEnd, countedDutyMin(t)
+			if flight {
+				at, value = t.LastLegArrivalTime, t.BlockTimeMin
+			}
+			if at.In(loc).After(from) && at.In(loc).Before(windowEnd) {
+				total += value
+				ids = append(ids, t.TripID)
+			}
+		}
+		if total > limit {
+			violations = append(violations, newCumulativeViolation(ruleCode, from, now, total, limit, ids))
+		}
+	}
+	yearly := func(ruleCode string, limit int, flight bool) {
+		total := 0
+		var ids []string
+		for _, t := range allCrewTrips {
+			at, value := t.CalculatedDutyPeriodEnd, countedDutyMin(t)
+			if flight {
+				at, value = t.LastLegArrivalTime, t.BlockTimeMin
+			}
+			if at.In(loc).Year() == currentYear {
+				total += value
+				ids = append(ids, t.TripID)
+			}
+		}
+		if total > limit {
+			violations = append(violations, newCumulativeViolation(ruleCode, yearStart, yearEnd, total, limit, ids))
+		}
+	}
+
+	rolling(models.RuleMaxDutyPeriod7DaysExceeded, now.AddDate(0, 0, -7), ruleSet.MaxDuty7DaysMin, false)
+	rolling(models.RuleMaxDutyPeriod14DaysExceeded, now.AddDate(0, 0, -14), ruleSet.MaxDuty14DaysMin, false)
+	rolling(models.RuleMaxDutyPeriod28DaysExceeded, now.AddDate(0, 0, -28), ruleSet.MaxDuty28DaysMin, false)
+	yearly(models.RuleMaxDutyPeriodYearExceeded, ruleSet.MaxDutyYearMin, false)
+	rolling(models.RuleMaxFlightTime28DaysExceeded, now.AddDate(0, 0, -28), ruleSet.MaxFlight28DaysMin, true)
+	rolling(models.RuleMaxFlightTime12MonthsExceeded, now.AddDate(0, -12, 0), ruleSet.MaxFlight12MonthsMin, true)
+	yearly(models.RuleMaxFlightTimeYearExceeded, ruleSet.MaxFlightYearMin, true)
+	return violations
+}
+
+// normalizeViolations, zaman değerlerini UTC'ye çevirerek karşılaştırmayı konumdan bağımsız yapar.
+func normalizeViolations(violations []models.FTLViolation) []models.FTLViolation {
+	out := make([]models.FTLViolation, len(violations))
+	for i, v := range violations {
+		v.WindowStart, v.WindowEnd = v.WindowStart.UTC(), v.WindowEnd.UTC()
+		out[i] = v
+	}
+	return out
+}
+
+func TestCumulativeWindowEngineMatchesLegacy(t *testing.T) {
+	schedules, ruleSet, loc := syntheticSchedules(t, 20, 400)
+
+	violationCount := 0
+	for _, trips := range schedules {
+		engine := NewCumulativeWindowEngine(nil)
+		for i, trip := range trips {
+			engine.Add(trip)
+			want := legacyCumulativeViolations(trip, trips[:i+1], ruleSet, loc)
+			got := engine.Violations(trip, ruleSet, loc)
+			if !reflect.DeepEqual(normalizeViolations(got), normalizeViolations(want)) {
+				t.Fatalf("trip %s için sonuçlar farklı:\n eski: %+v\n yeni: %+v", trip.TripID, want, got)
+			}
+			violationCount += len(got)
+		}
+	}
+	if violationCount == 0 {
+		t.Fatal("sentetik programlar hiç ihlal üretmedi; karşılaştırma ihlal dallarını kapsamıyor")
+	}
+}
+
+func BenchmarkCumulativeWindowEngine(b *testing.B) {
+	schedules, ruleSet, loc := syntheticSchedules(b, 50, 400)
+
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for _, trips := range schedules {
+				for i, trip := range trips {
+					legacyCumulativeViolations(trip, trips[:i+1], ruleSet, loc)
+				}
+			}
+		}
+	})
+	b.Run("engine", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for _, trips := range schedules {
+				engine := NewCumulativeWindowEngine(nil)
+				for _, trip := range trips {
+					engine.Add(trip)
+					engine.Violations(trip, ruleSet, loc)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Düzensiz görev (disruptive schedule) pencereleri, ana üs yerel saatine göre (ORO.FTL.105)
+const (
+	earlyStartFromHour, earlyStartToHour = 5, 6  // Erken başlama: 05:00-05:59 arasında başlayan görev
+	lateFinishFromHour, lateFinishToHour = 23, 2 // Geç bitiş: 23:00-01:59 arasında biten görev
+	nightDutyFromHour, nightDutyToHour   = 2, 5  // Gece görevi: 02:00-04:59 ile kesişen görev
+)
+
+// DisruptiveFlags, bir görevin düzensiz görev sınıflandırmasıdır.
+type DisruptiveFlags struct {
+	EarlyStart     bool
+	LateFinish     bool
+	NightDuty      bool
+	EncroachesWOCL bool
+}
+
+// Any, görevin herhangi bir düzensiz görev tipine girip girmediğini döndürür.
+func (d DisruptiveFlags) Any() bool {
+	return d.EarlyStart || d.LateFinish || d.NightDuty
+}
+
+// classifyDisruptiveDuty, [start, end] görevini verilen yerel saatte erken başlama, geç bitiş,
+// gece görevi ve WOCL (02:00-05:59) kesişimi açısından sınıflandırır.
+func classifyDisruptiveDuty(start, end time.Time, loc *time.Location) DisruptiveFlags {
+	if loc == nil {
+		loc = time.UTC
+	}
+	start, end = start.In(loc), end.In(loc)
+
+	flags := DisruptiveFlags{
+		EarlyStart:     start.Hour() >= earlyStartFromHour && start.Hour() < earlyStartToHour,
+		LateFinish:     end.Hour() >= lateFinishFromHour || end.Hour() < lateFinishToHour,
+		EncroachesWOCL: encroachesWOCL(start, end),
+	}
+
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	for !day.After(end) {
+		nightStart := day.Add(nightDutyFromHour * time.Hour)
+		nightEnd := day.Add(nightDutyToHour * time.Hour)
+		if start.Before(nightEnd) && end.After(nightStart) {
+			flags.NightDuty = true
+			break
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return flags
+}
+
+// isDisruptiveDuty, görevin ana üs yerel saatine göre erken başlama, geç bitiş veya gece görevi olup olmadığını döndürür.
+func isDisruptiveDuty(start, end time.Time, loc *time.Location) bool {
+	return classifyDisruptiveDuty(start, end, loc).Any()
+}
+
+// applyDisruptiveFlags, trip'in düzensiz görev işaretlerini ana üs yerel saatine göre günceller.
+func applyDisruptiveFlags(trip *models.Trip, base *time.Location) {
+	flags := classifyDisruptiveDuty(trip.CalculatedDutyPeriodStart, trip.CalculatedDutyPeriodEnd, base)
+	trip.EarlyStart = flags.EarlyStart
+	trip.LateFinish = flags.LateFinish
+	trip.NightDuty = flags.NightDuty
+	trip.EncroachesWOCL = flags.EncroachesWOCL
+}
+
+// nightDutySeries, ardışık gece görevi serisinin trip sırası boyunca taşınan durumudur.
+type nightDutySeries struct {
+	tripIDs []string
+	start   time.Time // Serinin ilk görevinin başlangıcı
+	prev    *models.Trip
+}
+
+// step, trip'i ardışık gece görevi serisine katar ve seri maxConsecutive'i aşıyorsa ihlal döndürür.
+// Aralarında uzatılmış dinlenme (≥ 36 saat) veya gece olmayan bir görev bulunan görevler ardışık sayılmaz.
+// Triplere görev başlangıç sırasıyla çağrılmalıdır.
+func (n *nightDutySeries) step(t *models.Trip, base *time.Location, maxConsecutive int) []models.FTLViolation {
+	nightDuty := classifyDisruptiveDuty(t.CalculatedDutyPeriodStart, t.CalculatedDutyPeriodEnd, base).NightDuty
+	restBroken := n.prev != nil && t.CalculatedDutyPeriodStart.Sub(n.prev.CalculatedDutyPeriodEnd) >= extendedRecoveryRestMinMin*time.Minute
+	n.prev = t
+	if restBroken || !nightDuty {
+		n.tripIDs = nil
+	}
+	if !nightDuty {
+		return nil
+	}
+	if len(n.tripIDs) == 0 {
+		n.start = t.CalculatedDutyPeriodStart
+	}
+	n.tripIDs = append(n.tripIDs, t.TripID)
+
+	if maxConsecutive <= 0 || len(n.tripIDs) <= maxConsecutive {
+		return nil
+	}
+	return []models.FTLViolation{{
+		RuleCode:            models.RuleMaxConsecutiveNightDutiesExceeded,
+		Severity:            models.SeverityViolation,
+		WindowStart:         n.start,
+		WindowEnd:           t.CalculatedDutyPeriodEnd,
+		MeasuredValue:       float64(len(n.tripIDs)),
+		Limit:               float64(maxConsecutive),
+		Unit:                models.UnitCount,
+		ContributingTripIDs: append([]string(nil), n.tripIDs...),
+	}}
+}
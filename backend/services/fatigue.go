@@ -0,0 +1,174 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Basitleştirilmiş üç süreçli uyku-uyanıklık modeli (Åkerstedt & Folkard TPM) parametreleri.
+// Uyanıklık = S (homeostatik) + C (sirkadiyen) + W (uyku ataleti); yorgunluk skoru KSS (1-9) eşdeğeridir.
+const (
+	fatigueHistoryWindow = 72 * time.Hour   // Trip öncesinde simüle edilen geçmiş
+	fatigueStep          = 15 * time.Minute // Simülasyon adımı
+	fatigueInitialS      = 12.0             // Simülasyon başında (dinlenmiş) homeostatik seviye
+
+	fatigueSWakeAsymptote  = 2.4    // Uyanıkken S'nin yaklaştığı alt sınır
+	fatigueSWakeRate       = 0.0353 // Uyanıkken S azalma hızı (1/saat)
+	fatigueSSleepAsymptote = 14.3   // Uykuda S'nin yaklaştığı üst sınır
+	fatigueSSleepRate      = 0.381  // Uykuda S toparlanma hızı (1/saat)
+	fatigueCAmplitude      = 2.5    // Sirkadiyen genlik
+	fatigueCAcrophaseHour  = 16.8   // Sirkadiyen tepe saati (ana üs yerel saati)
+	fatigueWInitial        = -5.72  // Uyanıştaki uyku ataleti
+	fatigueWRate           = 1.51   // Uyku ataleti sönüm hızı (1/saat)
+
+	fatigueSleepLatency      = 60 * time.Minute // Görev bitişi ile uykuya dalma arası (yolculuk, hazırlık)
+	fatigueWakeBeforeReport  = 90 * time.Minute // Uyanış ile raporlama arası
+	fatigueMaxAnchorSleep    = 8 * time.Hour    // Gece dışı dinlenmede alınan en uzun uyku
+	fatigueMinUsefulSleep    = 2 * time.Hour    // Daha kısa uyku fırsatları hesaba katılmaz
+	fatigueNightSleepStartHr = 23               // Uzun dinlenmelerde gece uykusu: 23:00-07:00
+	fatigueNightSleepEndHr   = 7
+)
+
+type sleepInterval struct {
+	start, end time.Time
+}
+
+// fatigueKSS, TPM uyanıklık değerini Karolinska Uykululuk Ölçeği (1-9) eşdeğerine çevirir.
+func fatigueKSS(alertness float64) float64 {
+	kss := 10.6 - 0.6*alertness
+	return math.Max(1, math.Min(9, kss))
+}
+
+// circadian, ana üs yerel saatine göre sirkadiyen bileşeni döndürür.
+func circadian(at time.Time, base *time.Location) float64 {
+	local := at.In(base)
+	hour := float64(local.Hour()) + float64(local.Minute())/60
+	return fatigueCAmplitude * math.Cos(2*math.Pi*(hour-fatigueCAcrophaseHour)/24)
+}
+
+// restSleepIntervals, iki görev arasındaki dinlenmede varsayılan uyku aralıklarını döndürür: dinlenme içindeki
+// her gece penceresi (23:00-07:00) uyku kabul edilir; gece uykusu alınamıyorsa dinlenme başında tek bir çapa uykusu varsayılır.
+func restSleepIntervals(restStart, restEnd time.Time, base *time.Location) []sleepInterval {
+	from, to := restStart.Add(fatigueSleepLatency), restEnd.Add(-fatigueWakeBeforeReport)
+	if to.Sub(from) < fatigueMinUsefulSleep {
+		return nil
+	}
+
+	var sleeps []sleepInterval
+	local := from.In(base)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, base).AddDate(0, 0, -1)
+	for !day.After(to) {
+		nightStart := time.Date(day.Year(), day.Month(), day.Day(), fatigueNightSleepStartHr, 0, 0, 0, base)
+		next := day.AddDate(0, 0, 1)
+		nightEnd := time.Date(next.Year(), next.Month(), next.Day(), fatigueNightSleepEndHr, 0, 0, 0, base)
+		s, e := nightStart, nightEnd
+		if s.Before(from) {
+			s = from
+		}
+		if e.After(to) {
+			e = to
+		}
+		if e.Sub(s) >= fatigueMinUsefulSleep {
+			sleeps = append(sleeps, sleepInterval{s, e})
+		}
+		day = next
+	}
+	if len(sleeps) > 0 {
+		return sleeps
+	}
+
+	end := from.Add(fatigueMaxAnchorSleep)
+	if end.After(to) {
+		end = to
+	}
+	return []sleepInterval{{from, end}}
+}
+
+// scoreTripFatigue, ekip üyesinin trip öncesi 72 saatlik görev/dinlenme geçmişinden uyku aralıklarını türetir,
+// uyanıklığı 15 dakikalık adımlarla simüle eder ve trip'in görev süresindeki en yüksek yorgunluk skorunu
+// (KSS eşdeğeri) ve zamanını döndürür. history, trip'ten önce hesaplanmış tripleri görev başlangıcı sırasıyla
+// içermelidir; görevler örtüşmediğinden bitişler de sıralıdır ve pencere başı ikili aramayla bulunur.
+func scoreTripFatigue(trip *models.Trip, history []*models.Trip, base *time.Location) (float64, time.Time) {
+	dutyStart, dutyEnd := trip.CalculatedDutyPeriodStart, trip.CalculatedDutyPeriodEnd
+	if dutyStart.IsZero() || !dutyEnd.After(dutyStart) {
+		return 0, time.Time{}
+	}
+	simStart := dutyStart.Add(-fatigueHistoryWindow)
+
+	// Simülasyon penceresindeki önceki görevler (mevcut trip'in güncel hali kullanılır)
+	type duty struct{ start, end time.Time }
+	var duties []duty
+	first := sort.Search(len(history), func(k int) bool { return history[k].CalculatedDutyPeriodEnd.After(simStart) })
+	for _, t := range history[first:] {
+		if !t.CalculatedDutyPeriodStart.Before(dutyStart) {
+			break
+		}
+		if t.TripID == trip.TripID || t.CalculatedDutyPeriodStart.IsZero() {
+			continue
+		}
+		duties = append(duties, duty{t.CalculatedDutyPeriodStart, t.CalculatedDutyPeriodEnd})
+	}
+	duties = append(duties, duty{dutyStart, dutyEnd})
+	sort.Slice(duties, func(i, j int) bool { return duties[i].start.Before(duties[j].start) })
+
+	// Görevler arası dinlenmelerdeki uyku aralıkları; ilk görevden önceki dönem 07:00 uyanışlı gece uykusu kabul edilir
+	sleeps := restSleepIntervals(simStart.Add(-24*time.Hour), duties[0].start, base)
+	for i := 1; i < len(duties); i++ {
+		if duties[i].start.After(duties[i-1].end) {
+			sleeps = append(sleeps, restSleepIntervals(duties[i-1].end, duties[i].start, base)...)
+		}
+	}
+
+	s := fatigueInitialS
+	var lastWake time.Time
+	asleep := false
+	worst, peakAt := 0.0, time.Time{}
+	sleepIdx := 0
+	stepHours := fatigueStep.Hours()
+
+	for at := simStart; !at.After(dutyEnd); at = at.Add(fatigueStep) {
+		for sleepIdx < len(sleeps) && !sleeps[sleepIdx].end.After(at) {
+			if asleep {
+				asleep = false
+				lastWake = sleeps[sleepIdx].end
+			}
+			sleepIdx++
+		}
+		inSleep := sleepIdx < len(sleeps) && !at.Before(sleeps[sleepIdx].start)
+		if inSleep {
+			asleep = true
+			s = fatigueSSleepAsymptote - (fatigueSSleepAsymptote-s)*math.Exp(-fatigueSSleepRate*stepHours)
+			continue
+		}
+		if asleep {
+			asleep = false
+			lastWake = at
+		}
+		s = fatigueSWakeAsymptote + (s-fatigueSWakeAsymptote)*math.Exp(-fatigueSWakeRate*stepHours)
+
+		if at.Before(dutyStart) {
+			continue
+		}
+		alertness := s + circadian(at, base)
+		if !lastWake.IsZero() {
+			alertness += fatigueWInitial * math.Exp(-fatigueWRate*at.Sub(lastWake).Hours())
+		}
+		if kss := fatigueKSS(alertness); kss > worst {
+			worst, peakAt = kss, at
+		}
+	}
+	return math.Round(worst*100) / 100, peakAt
+}
+
+// applyFatigueScore, trip'in yorgunluk skorunu ve en yüksek yorgunluk anını hesaplayıp trip'e yazar.
+func applyFatigueScore(trip *models.Trip, history []*models.Trip, base *time.Location) {
+	score, peakAt := scoreTripFatigue(trip, history, base)
+	trip.FatigueScore = score
+	trip.FatiguePeakAt = nil
+	if !peakAt.IsZero() {
+		trip.FatiguePeakAt = &peakAt
+	}
+}
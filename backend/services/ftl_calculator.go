@@ -5,18 +5,25 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strings"
 	"time"
 
 	"mini_CMS_Desktop_App/models"
 	"mini_CMS_Desktop_App/repositories"
 )
 
-// FTLCalculator, SHT-FTL kurallarını uygular ve uçuş/görev/dinlenme sürelerini hesaplar.
+// FTLCalculator, trip'e atanan mevzuat çerçevesinin (varsayılan SHT-FTL) kurallarını uygular ve
+// uçuş/görev/dinlenme sürelerini hesaplar.
 type FTLCalculator struct {
 	briefDebriefCalc *BriefDebriefCalculator
 	tripRepo         *repositories.TripRepository
 	actualRepo       *repositories.ActualRepository
 	userPrefRepo     *repositories.UserPreferenceRepository
+	ruleSetRepo      *repositories.FTLRuleSetRepository
+	crewInfoRepo     *repositories.CrewInfoRepository
+	discretionRepo   *repositories.CommanderDiscretionRepository
+	baseAirportRepo  *repositories.CrewBaseAirportRepository
+	regulationRepo   *repositories.RegulationAssignmentRepository
 }
 
 // NewFTLCalculator, FTLCalculator'ın yeni bir örneğini oluşturur.
@@ -25,52 +32,146 @@ func NewFTLCalculator(
 	tripRepo *repositories.TripRepository,
 	actualRepo *repositories.ActualRepository,
 	userPrefRepo *repositories.UserPreferenceRepository,
+	ruleSetRepo *repositories.FTLRuleSetRepository,
+	crewInfoRepo *repositories.CrewInfoRepository,
+	discretionRepo *repositories.CommanderDiscretionRepository,
+	baseAirportRepo *repositories.CrewBaseAirportRepository,
+	regulationRepo *repositories.RegulationAssignmentRepository,
 ) *FTLCalculator {
 	return &FTLCalculator{
 		briefDebriefCalc: briefDebriefCalc,
 		tripRepo:         tripRepo,
 		actualRepo:       actualRepo,
 		userPrefRepo:     userPrefRepo,
+		ruleSetRepo:      ruleSetRepo,
+		crewInfoRepo:     crewInfoRepo,
+		discretionRepo:   discretionRepo,
+		baseAirportRepo:  baseAirportRepo,
+		regulationRepo:   regulationRepo,
 	}
 }
 
-// CalculateFTLForTrip (mevcut hali, önceki yanıtta verilmişti)
-func (f *FTLCalculator) CalculateFTLForTrip(trip *models.Trip, allCrewTrips []*models.Trip) error {
-	trip.FTLViolations = []string{}
+// crewBase, ekip üyesinin crew_info.base_location meydanını ve bu meydanın zaman dilimini döndürür.
+// Ekip bilgisi bulunamazsa boş meydan, zaman dilimi bilinmiyorsa fallback döner.
+func (f *FTLCalculator) crewBase(ctx context.Context, crewID string, fallback *time.Location) (string, *time.Location) {
+	if f.crewInfoRepo == nil {
+		return "", fallback
+	}
+	crewInfo, err := f.crewInfoRepo.GetCrewInfoByPersonID(ctx, crewID)
+	if err != nil {
+		log.Printf("Uyarı: Ekip %s için ana üs bilgisi çekilemedi: %v. Tercih edilen zaman dilimi kullanılıyor.", crewID, err)
+		return "", fallback
+	}
+	if crewInfo == nil || crewInfo.BaseLocation == "" {
+		return "", fallback
+	}
+	base := strings.ToUpper(strings.TrimSpace(crewInfo.BaseLocation))
+	loc, ok := models.GetAirportLocation(base)
+	if !ok {
+		log.Printf("Uyarı: Ekip %s için ana üs '%s' zaman dilimi bilinmiyor. Tercih edilen zaman dilimi kullanılıyor.", crewID, crewInfo.BaseLocation)
+		return base, fallback
+	}
+	return base, loc
+}
 
-	var preferredLocation *time.Location = time.UTC
-	userIDForPref := trip.CrewMemberID
+// preferredLocation, ekip üyesinin zaman dilimi tercihini döndürür; tercih yoksa veya geçersizse UTC döner.
+func (f *FTLCalculator) preferredLocation(userID string) *time.Location {
+	userPref, err := f.userPrefRepo.GetPreferenceByUserID(userID)
+	if err != nil {
+		log.Printf("Uyarı: Kullanıcı %s için zaman dilimi tercihi çekilemedi: %v. Varsayılan UTC kullanılıyor.", userID, err)
+		return time.UTC
+	}
+	if userPref == nil || userPref.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(userPref.TimeZone)
+	if err != nil {
+		log.Printf("Uyarı: Geçersiz zaman dilimi tercihi '%s' için kullanıcı %s: %v. Varsayılan UTC kullanılıyor.", userPref.TimeZone, userID, err)
+		return time.UTC
+	}
+	return loc
+}
 
-	userPref, err := f.userPrefRepo.GetPreferenceByUserID(userIDForPref)
+// activeRuleSets, aktif FTL kural setlerini ruleSetForDate'in seçim sırasıyla getirir. Depo tanımlı değilse veya
+// sorgu başarısız olursa nil döner (tüm tarihlerde yerleşik varsayılan limitler kullanılır).
+func (f *FTLCalculator) activeRuleSets(ctx context.Context) []models.FTLRuleSet {
+	if f.ruleSetRepo == nil {
+		return nil
+	}
+	ruleSets, err := f.ruleSetRepo.ListActiveRuleSets(ctx)
 	if err != nil {
-		log.Printf("Uyarı: Kullanıcı %s için zaman dilimi tercihi çekilemedi: %v. Varsayılan UTC kullanılıyor.", userIDForPref, err)
-	} else if userPref != nil && userPref.TimeZone != "" {
-		loc, err := time.LoadLocation(userPref.TimeZone)
-		if err != nil {
-			log.Printf("Uyarı: Geçersiz zaman dilimi tercihi '%s' için kullanıcı %s: %v. Varsayılan UTC kullanılıyor.", userPref.TimeZone, userIDForPref, err)
-		} else {
-			preferredLocation = loc
+		log.Printf("Uyarı: Aktif FTL kural setleri çekilemedi: %v. Varsayılan limitler kullanılıyor.", err)
+		return nil
+	}
+	if len(ruleSets) == 0 {
+		log.Printf("Uyarı: Aktif FTL kural seti yok. Varsayılan limitler kullanılıyor.")
+	}
+	return ruleSets
+}
+
+// ruleSetForDate, verilen anda yürürlükte olan FTL kural setini activeRuleSets sonucundan seçer (en geç yürürlüğe
+// giren, eşitlikte en yüksek versiyon). Uygun bir set yoksa yerleşik varsayılan limitler kullanılır.
+func ruleSetForDate(ruleSets []models.FTLRuleSet, at time.Time) models.FTLRuleSet {
+	for _, ruleSet := range ruleSets {
+		if !ruleSet.EffectiveFrom.After(at) && (ruleSet.EffectiveTo == nil || ruleSet.EffectiveTo.After(at)) {
+			return ruleSet
 		}
 	}
+	if len(ruleSets) > 0 {
+		log.Printf("Uyarı: %s tarihinde yürürlükte aktif FTL kural seti yok. Varsayılan limitler kullanılıyor.", at.Format(time.RFC3339))
+	}
+	return models.DefaultFTLRuleSet()
+}
 
-	// Fix: Add context.Background() as the first argument
-	briefOnlyMin, _ := f.briefDebriefCalc.GetBriefDebriefDurations(
-		context.Background(),
+// briefDebriefRules, brief/debrief kurallarını eşleştirme sırasıyla getirir. Kurallar yüklenemezse nil döner
+// (tüm triplerde varsayılan süreler uygulanır).
+func (f *FTLCalculator) briefDebriefRules(ctx context.Context) []models.BriefDebriefRule {
+	rules, err := f.briefDebriefCalc.loadRules(ctx)
+	if err != nil {
+		log.Printf("[BriefDebriefCalc] ❗ Kural yükleme hatası: %v — Varsayılan değerler kullanılacak.", err)
+		return nil
+	}
+	return rules
+}
+
+// CalculateFTLForTrip, trip'i ekibin trip listesinde kendisinden önce gelen triplerin kayıtlı değerleriyle hesaplar.
+// Tüm program hesaplanacaksa trip başına bu fonksiyon yerine EvaluateSchedule kullanılmalıdır.
+func (f *FTLCalculator) CalculateFTLForTrip(ctx context.Context, trip *models.Trip, allCrewTrips []*models.Trip) error {
+	state := f.newCrewScheduleState(ctx, trip.CrewMemberID, []*models.Trip{trip})
+	state.replayUntil(trip, allCrewTrips)
+	return f.calculateFTLForTrip(trip, state)
+}
+
+// calculateFTLForTrip, trip'i ekip program durumuna göre hesaplar ve ardından duruma katar. state, program
+// sırasıyla trip'ten önceki tripleri içermelidir.
+func (f *FTLCalculator) calculateFTLForTrip(trip *models.Trip, state *crewScheduleState) error {
+	// Kural değişikliği işaretiyle karşılaştırılacak zaman, kurallar program başında okunmadan önce alınmıştır
+	calculatedAt := state.loadedAt
+	trip.FTLViolations = []models.FTLViolation{}
+
+	preferredLocation := state.preferredLocation
+	dutyDate := BriefDebriefDutyDate(trip)
+
+	briefOnlyMin, _, briefRuleID := resolveBriefDebrief(
+		state.briefDebriefRules,
 		trip.CrewType,
 		trip.BriefTripType,
 		trip.BriefAircraftType, // <<<< BURADA KULLANILIYOR
 		trip.DutyStartAirport,
+		dutyDate,
 	)
 	trip.CalculatedBriefDurationMin = briefOnlyMin
+	trip.BriefRuleID = briefRuleID
 
-	// Fix: Add context.Background() as the first argument
-	_, debriefOnlyMin := f.briefDebriefCalc.GetBriefDebriefDurations(
-		context.Background(),
+	_, debriefOnlyMin, debriefRuleID := resolveBriefDebrief(
+		state.briefDebriefRules,
 		trip.CrewType,
 		trip.DebriefTripType,
 		trip.DebriefAircraftType, // <<<< BURADA KULLANILIYOR
 		trip.DutyStartAirport,
+		dutyDate,
 	)
+	trip.DebriefRuleID = debriefRuleID
 	trip.CalculatedDebriefDurationMin = debriefOnlyMin
 
 	firstLegDepInPrefLoc := trip.FirstLegDepartureTime.In(preferredLocation)
@@ -90,18 +191,25 @@ func (f *FTLCalculator) CalculateFTLForTrip(trip *models.Trip, allCrewTrips []*m
 	trip.CalculatedDutyPeriodDurationMin = int(trip.CalculatedDutyPeriodEnd.Sub(trip.CalculatedDutyPeriodStart).Minutes())
 
 	trip.CalculatedFlightDutyPeriodDurationMin = int(lastLegArrInPrefLoc.Sub(trip.CalculatedDutyPeriodStart).Minutes())
+	applyBlockTime(trip)
 
-	var prevTrip *models.Trip
-	for i, t := range allCrewTrips {
-		if t.TripID == trip.TripID {
-			if i > 0 {
-				prevTrip = allCrewTrips[i-1]
-			}
-			break
-		}
-	}
+	ruleSet := ruleSetForDate(state.ruleSets, trip.CalculatedDutyPeriodStart)
+	trip.RuleSetVersion = ruleSet.Version
+	trip.Regulation = selectRegulation(trip, state.regulationAssignments)
+	regulation := f.regulationFor(trip.Regulation)
+	ruleSet = regulation.CumulativeLimits(ruleSet)
+
+	crewBaseAirport, baseLocation, baseAirports := state.crewBaseAirport, state.baseLocation, state.baseAirports
+	acclimatisationState, referenceLocation := state.acclimatisation.Advance(trip.CalculatedDutyPeriodStart, trip.DutyStartAirport)
+	trip.AcclimatisationState = acclimatisationState
+	trip.AcclimatisationReferenceTZ = referenceLocation.String()
+
+	prevTrip := state.prev
+	calledOutFromPrevStandby := applyStandbyAccounting(trip, prevTrip)
+	trip.RestLocation = ""
+	trip.MinRestRequiredMin = 0
 
-	if prevTrip != nil {
+	if prevTrip != nil && !calledOutFromPrevStandby {
 		prevTripEndInPrefLoc := prevTrip.CalculatedDutyPeriodEnd.In(preferredLocation)
 		currentTripStartInPrefLoc := trip.CalculatedDutyPeriodStart.In(preferredLocation)
 
@@ -109,125 +217,68 @@ func (f *FTLCalculator) CalculateFTLForTrip(trip *models.Trip, allCrewTrips []*m
 		trip.CalculatedRestPeriodEnd = currentTripStartInPrefLoc
 		trip.CalculatedRestPeriodDurationMin = int(trip.CalculatedRestPeriodEnd.Sub(trip.CalculatedRestPeriodStart).Minutes())
 
-		minRestExpectedMin := 0
-		if trip.DutyStartAirport == "IST" || trip.DutyStartAirport == "SAW" || trip.DutyStartAirport == "ISL" {
-			minRestExpectedMin = 12 * 60
-		} else {
-			minRestExpectedMin = 10 * 60
-		}
+		restLocation, minRestExpectedMin := requiredMinRest(trip, prevTrip, crewBaseAirport, baseAirports, ruleSet, regulation)
+		trip.RestLocation = restLocation
+		trip.MinRestRequiredMin = minRestExpectedMin
 
 		if trip.CalculatedRestPeriodDurationMin < minRestExpectedMin {
-			trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MinRestPeriodViolated: Ekip %s için dinlenme süresi %.0f dk, beklenen minimum %.0f dk",
-				trip.CrewMemberID, float64(trip.CalculatedRestPeriodDurationMin), float64(minRestExpectedMin)))
-		}
+			trip.FTLViolations = append(trip.FTLViolations, models.FTLViolation{
+				RuleCode:            models.RuleMinRestPeriodViolated,
+				Severity:            models.SeverityViolation,
+				WindowStart:         trip.CalculatedRestPeriodStart,
+				WindowEnd:           trip.CalculatedRestPeriodEnd,
+				MeasuredValue:       float64(trip.CalculatedRestPeriodDurationMin),
+				Limit:               float64(minRestExpectedMin),
+				Unit:                models.UnitMinutes,
+				ContributingTripIDs: []string{prevTrip.TripID, trip.TripID},
+			})
+		}
+		trip.FTLViolations = append(trip.FTLViolations, checkAwayRestSleepOpportunity(trip, prevTrip)...)
+	} else if calledOutFromPrevStandby {
+		log.Printf("Bilgi: Trip %s, %s ID'li yedek görevinden çağrıldı. Dinlenme kontrolü yedek görevi üzerinden yapıldı.", trip.TripID, prevTrip.TripID)
 	} else {
 		log.Printf("Bilgi: Ekip %s için %s ID'li görevden önce önceki bir görev bulunamadı. Dinlenme süresi hesaplanmadı.", trip.CrewMemberID, trip.TripID)
 	}
 
-	nowInPrefLoc := trip.CalculatedDutyPeriodEnd.In(preferredLocation)
-
-	sevenDaysAgo := nowInPrefLoc.AddDate(0, 0, -7)
-	current7DayDutyMin := 0
-	for _, t := range allCrewTrips {
-		// Removed invisible characters here
-		if t.CalculatedDutyPeriodEnd.In(preferredLocation).After(sevenDaysAgo) && t.CalculatedDutyPeriodEnd.In(preferredLocation).Before(nowInPrefLoc.Add(1*time.Minute)) {
-			current7DayDutyMin += t.CalculatedDutyPeriodDurationMin
-		}
-	}
-	max7DayDutyMin := 60 * 60
-	if current7DayDutyMin > max7DayDutyMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxDutyPeriod7DaysExceeded: Ekip %s için 7 günlük görev süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(current7DayDutyMin)/60, float64(max7DayDutyMin)/60))
-	}
-
-	fourteenDaysAgo := nowInPrefLoc.AddDate(0, 0, -14)
-	current14DayDutyMin := 0
-	for _, t := range allCrewTrips {
-		// Removed invisible characters here
-		if t.CalculatedDutyPeriodEnd.In(preferredLocation).After(fourteenDaysAgo) && t.CalculatedDutyPeriodEnd.In(preferredLocation).Before(nowInPrefLoc.Add(1*time.Minute)) {
-			current14DayDutyMin += t.CalculatedDutyPeriodDurationMin
-		}
-	}
-	max14DayDutyMin := 110 * 60
-	if current14DayDutyMin > max14DayDutyMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxDutyPeriod14DaysExceeded: Ekip %s için 14 günlük görev süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(current14DayDutyMin)/60, float64(max14DayDutyMin)/60))
-	}
-
-	twentyEightDaysAgo := nowInPrefLoc.AddDate(0, 0, -28)
-	current28DayDutyMin := 0
-	for _, t := range allCrewTrips {
-		// Removed invisible characters here
-		if t.CalculatedDutyPeriodEnd.In(preferredLocation).After(twentyEightDaysAgo) && t.CalculatedDutyPeriodEnd.In(preferredLocation).Before(nowInPrefLoc.Add(1*time.Minute)) {
-			current28DayDutyMin += t.CalculatedDutyPeriodDurationMin
-		}
-	}
-	max28DayDutyMin := 190 * 60
-	if current28DayDutyMin > max28DayDutyMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxDutyPeriod28DaysExceeded: Ekip %s için 28 günlük görev süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(current28DayDutyMin)/60, float64(max28DayDutyMin)/60))
-	}
-
-	currentYear := nowInPrefLoc.Year()
-	currentYearDutyMin := 0
-	for _, t := range allCrewTrips {
-		if t.CalculatedDutyPeriodEnd.In(preferredLocation).Year() == currentYear {
-			currentYearDutyMin += t.CalculatedDutyPeriodDurationMin
-		}
-	}
-	maxYearDutyMin := 2000 * 60
-	if currentYearDutyMin > maxYearDutyMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxDutyPeriodYearExceeded: Ekip %s için yıllık görev süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(currentYearDutyMin)/60, float64(maxYearDutyMin)/60))
-	}
+	trip.FTLViolations = append(trip.FTLViolations, applyTimeZoneRecovery(trip, &state.rotation, baseLocation)...)
+	applyDisruptiveFlags(trip, baseLocation)
+	trip.FTLViolations = append(trip.FTLViolations, state.cycle.step(trip, baseLocation, ruleSet.MaxDisruptiveDutiesPerCycle)...)
+	trip.FTLViolations = append(trip.FTLViolations, state.nights.step(trip, baseLocation, ruleSet.MaxConsecutiveNightDuties)...)
 
-	current28DayFlightMin := 0
-	for _, t := range allCrewTrips {
-		// Removed invisible characters here
-		if t.LastLegArrivalTime.In(preferredLocation).After(twentyEightDaysAgo) && t.LastLegArrivalTime.In(preferredLocation).Before(nowInPrefLoc.Add(1*time.Minute)) {
-			current28DayFlightMin += t.CalculatedFlightDutyPeriodDurationMin
-		}
-	}
-	max28DayFlightMin := 100 * 60
-	if current28DayFlightMin > max28DayFlightMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxFlightTime28DaysExceeded: Ekip %s için 28 günlük uçuş süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(current28DayFlightMin)/60, float64(max28DayFlightMin)/60))
-	}
+	// Kümülatif pencereler yalnızca bu trip ve öncekiler üzerinden hesaplanır
+	state.engine.Add(trip)
+	trip.FTLViolations = append(trip.FTLViolations, state.engine.Violations(trip, ruleSet, preferredLocation)...)
+	trip.CumulativeWindows = state.engine.Windows(trip, ruleSet, preferredLocation)
 
-	twelveMonthsAgo := nowInPrefLoc.AddDate(0, -12, 0)
-	current12MonthFlightMin := 0
-	for _, t := range allCrewTrips {
-		// Removed invisible characters here
-		if t.LastLegArrivalTime.In(preferredLocation).After(twelveMonthsAgo) && t.LastLegArrivalTime.In(preferredLocation).Before(nowInPrefLoc.Add(1*time.Minute)) {
-			current12MonthFlightMin += t.CalculatedFlightDutyPeriodDurationMin
-		}
-	}
-	max12MonthFlightMin := 1000 * 60
-	if current12MonthFlightMin > max12MonthFlightMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxFlightTime12MonthsExceeded: Ekip %s için 12 aylık uçuş süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(current12MonthFlightMin)/60, float64(max12MonthFlightMin)/60))
-	}
-
-	currentYearFlightMin := 0
-	for _, t := range allCrewTrips {
-		if t.LastLegArrivalTime.In(preferredLocation).Year() == currentYear {
-			currentYearFlightMin += t.CalculatedFlightDutyPeriodDurationMin
-		}
-	}
-	maxYearFlightMin := 900 * 60
-	if currentYearFlightMin > maxYearFlightMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxFlightTimeYearExceeded: Ekip %s için yıllık uçuş süresi %.0f saat, limit %.0f saat",
-			trip.CrewMemberID, float64(currentYearFlightMin)/60, float64(maxYearFlightMin)/60))
-	}
+	trip.FlightCrewComplement, trip.RestFacilityClass = f.determineCrewComplement(trip)
+	trip.SplitDuty = detectSplitDuty(trip, referenceLocation)
+	f.ApplyMaxDailyUGSLimit(trip, state.discretionFor(trip.TripID))
 
-	f.ApplyMaxDailyUGSLimit(trip)
+	// Reçeteli limitlerin yanında, ana üs yerel saatindeki görev/dinlenme geçmişinden yorgunluk riski skoru
+	applyFatigueScore(trip, state.history, baseLocation)
 
-	trip.LastCalculatedAt = time.Now()
+	state.commit(trip)
+	trip.LastCalculatedAt = calculatedAt
+	trip.RecalcRequired = false
 	return nil
 }
 
 // --- Yardımcı Fonksiyonlar ---
 
+// newCumulativeViolation, kümülatif bir pencere limitinin aşımı için yapısal ihlal kaydı oluşturur.
+func newCumulativeViolation(ruleCode string, windowStart, windowEnd time.Time, measuredMin, limitMin int, contributingTripIDs []string) models.FTLViolation {
+	return models.FTLViolation{
+		RuleCode:            ruleCode,
+		Severity:            models.SeverityViolation,
+		WindowStart:         windowStart,
+		WindowEnd:           windowEnd,
+		MeasuredValue:       float64(measuredMin),
+		Limit:               float64(limitMin),
+		Unit:                models.UnitMinutes,
+		ContributingTripIDs: contributingTripIDs,
+	}
+}
+
 // getMaxDailyUGSTable5, intibak edilmiş ekip üyeleri için Tablo-5'ten azami UGS limitini döndürür.
 // Fonksiyonun ilk harfini büyük yaparak public yapıyoruz.
 func (f *FTLCalculator) GetMaxDailyUGSTable5(startTime time.Time, numSectors int) (int, error) {
@@ -456,45 +507,184 @@ func (f *FTLCalculator) GetMaxDailyUGSTable5(startTime time.Time, numSectors int
 	return 0, fmt.Errorf("geçerli azami UGS Tablo-5 limiti bulunamadı: Başlangıç: %v, Sektör: %d", startTime.Format("15:04"), numSectors)
 }
 
-// ApplyMaxDailyUGSLimit kontrolü: Günlük azami UGS limitini uygular.
-// Fonksiyonun ilk harfini büyük yaparak public yapıyoruz.
-func (f *FTLCalculator) ApplyMaxDailyUGSLimit(trip *models.Trip) {
+// GetMaxDailyUGSUnknownState, aklimatizasyon durumu bilinmeyen ekip üyeleri için azami UGS limitini döndürür.
+// Bu tabloda limit görev başlangıç saatinden bağımsızdır, yalnızca sektör sayısına bağlıdır.
+func (f *FTLCalculator) GetMaxDailyUGSUnknownState(numSectors int) (int, error) {
+	switch numSectors {
+	case 1, 2:
+		return 11 * 60, nil
+	case 3:
+		return 10*60 + 30, nil
+	case 4:
+		return 10 * 60, nil
+	case 5:
+		return 9*60 + 30, nil
+	case 6, 7, 8, 9, 10:
+		return 9 * 60, nil
+	}
+	return 0, fmt.Errorf("geçerli azami UGS limiti bulunamadı (aklimatizasyon durumu bilinmiyor): Sektör: %d", numSectors)
+}
+
+// acclimatisedDutyStart, aklimatize ekip için Tablo-5'te kullanılacak görev başlangıcını,
+// ekibin aklimatize olduğu referans zaman diliminde döndürür.
+func acclimatisedDutyStart(trip *models.Trip) time.Time {
+	if trip.AcclimatisationReferenceTZ == "" {
+		return trip.CalculatedDutyPeriodStart
+	}
+	loc, err := time.LoadLocation(trip.AcclimatisationReferenceTZ)
+	if err != nil {
+		return trip.CalculatedDutyPeriodStart
+	}
+	return trip.CalculatedDutyPeriodStart.In(loc)
+}
+
+// fdpSectorCount, UGS tablolarında kullanılan sektör sayısını (DH dahil tüm FLT aktiviteleri) döndürür.
+func fdpSectorCount(trip *models.Trip) int {
 	numSectors := 0
 	for _, activity := range trip.Activities {
 		if activity.GroupCode == "FLT" {
 			numSectors++
 		}
 	}
+	return numSectors
+}
+
+// ApplyMaxDailyUGSLimit kontrolü: Günlük azami UGS limitini uygular.
+// Limit trip'e atanan mevzuat çerçevesinden alınır (SHT-FTL'de: uzatılmış ekip (3-4 pilot) ve uçakta dinlenme
+// tesisi varsa uzatılmış ekip tablosu, aksi halde aklimatizasyon durumuna göre Tablo-5 veya bilinmeyen durum tablosu).
+// Uzatılmış ekip limiti kullanılmıyorsa bölünmüş görev uzatması temel limite eklenir.
+// Yedekten çağrılan triplerde yedek süresinin eşiği aşan kısmı limitten düşülür.
+// Limit aşımı trip için kaydedilmiş en son kaptan takdiri (discretion, yoksa nil) kapsamındaysa ihlal yerine
+// takdir kullanımı olarak işaretlenir.
+// Fonksiyonun ilk harfini büyük yaparak public yapıyoruz.
+func (f *FTLCalculator) ApplyMaxDailyUGSLimit(trip *models.Trip, discretion *models.CommanderDiscretion) {
+	trip.CommanderDiscretionMin = 0
+	trip.AppliedFDPLimit = nil
+	numSectors := fdpSectorCount(trip)
 	if numSectors == 0 {
 		return
 	}
 
-	maxUGSLimitMin, err := f.GetMaxDailyUGSTable5(trip.CalculatedDutyPeriodStart, numSectors)
+	fdpLimit, err := f.regulationFor(trip.Regulation).MaxFDP(trip, numSectors)
+	maxUGSLimitMin, augmented := fdpLimit.LimitMin, fdpLimit.Augmented
+	applied := &models.FDPLimitExplanation{
+		Table:          fdpLimit.Table,
+		Row:            fdpLimit.Row,
+		Sectors:        numSectors,
+		Augmented:      augmented,
+		TableLimitMin:  fdpLimit.LimitMin,
+		FDPDurationMin: trip.CalculatedFlightDutyPeriodDurationMin,
+	}
+	trip.AppliedFDPLimit = applied
 	if err != nil {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxDailyUGSLimitError: %v", err))
+		applied.Error = err.Error()
+		trip.FTLViolations = append(trip.FTLViolations, models.FTLViolation{
+			RuleCode:            models.RuleMaxDailyUGSLimitError,
+			Severity:            models.SeverityError,
+			WindowStart:         trip.CalculatedDutyPeriodStart,
+			WindowEnd:           trip.LastLegArrivalTime,
+			MeasuredValue:       float64(numSectors),
+			Unit:                models.UnitCount,
+			ContributingTripIDs: []string{trip.TripID},
+			Details:             err.Error(),
+		})
 		return
 	}
 
+	if trip.SplitDuty != nil && trip.SplitDuty.Decision == models.SplitDutyApplied {
+		if augmented {
+			trip.SplitDuty.Decision = models.SplitDutyNotWithAugmentedCrew
+			trip.SplitDuty.ExtensionMin = 0
+		} else {
+			maxUGSLimitMin += trip.SplitDuty.ExtensionMin
+			applied.SplitDutyExtensionMin = trip.SplitDuty.ExtensionMin
+		}
+	}
+
+	splitDutyApplied := trip.SplitDuty != nil && trip.SplitDuty.Decision == models.SplitDutyApplied
+	trip.StandbyFDPReductionMin = standbyFDPReduction(trip, augmented || splitDutyApplied)
+	maxUGSLimitMin -= trip.StandbyFDPReductionMin
+	applied.StandbyReductionMin = trip.StandbyFDPReductionMin
+	applied.FinalLimitMin = maxUGSLimitMin
+
+	if trip.StandbyCalledOut && trip.StandbyType == models.StandbyTypeHome {
+		awakeMin := trip.StandbyDurationMin + trip.CalculatedFlightDutyPeriodDurationMin
+		applied.HomeStandbyAwakeMin = awakeMin
+		applied.HomeStandbyAwakeLimitMin = homeStandbyMaxAwakeMin
+		if awakeMin > homeStandbyMaxAwakeMin {
+			trip.FTLViolations = append(trip.FTLViolations, models.FTLViolation{
+				RuleCode:            models.RuleMaxHomeStandbyAwakeTimeExceeded,
+				Severity:            models.SeverityViolation,
+				WindowStart:         trip.CalculatedDutyPeriodStart,
+				WindowEnd:           trip.LastLegArrivalTime,
+				MeasuredValue:       float64(awakeMin),
+				Limit:               float64(homeStandbyMaxAwakeMin),
+				Unit:                models.UnitMinutes,
+				ContributingTripIDs: []string{trip.TripID},
+			})
+		}
+	}
+
 	if trip.CalculatedFlightDutyPeriodDurationMin > maxUGSLimitMin {
-		trip.FTLViolations = append(trip.FTLViolations, fmt.Sprintf("MaxDailyUGSLimitViolated: Ekip %s için günlük UGS %.0f saat, limit %.0f saat (Sektör: %d)",
-			trip.CrewMemberID, float64(trip.CalculatedFlightDutyPeriodDurationMin)/60, float64(maxUGSLimitMin)/60, numSectors))
+		excessMin := trip.CalculatedFlightDutyPeriodDurationMin - maxUGSLimitMin
+		if coveredByCommanderDiscretion(discretion, excessMin, augmented) {
+			trip.CommanderDiscretionMin = excessMin
+			applied.CommanderDiscretionMin = excessMin
+			trip.FTLViolations = append(trip.FTLViolations, models.FTLViolation{
+				RuleCode:            models.RuleCommandersDiscretionUsed,
+				Severity:            models.SeverityDiscretion,
+				WindowStart:         trip.CalculatedDutyPeriodStart,
+				WindowEnd:           trip.LastLegArrivalTime,
+				MeasuredValue:       float64(trip.CalculatedFlightDutyPeriodDurationMin),
+				Limit:               float64(maxUGSLimitMin),
+				Unit:                models.UnitMinutes,
+				ContributingTripIDs: []string{trip.TripID},
+			})
+			return
+		}
+
+		trip.FTLViolations = append(trip.FTLViolations, models.FTLViolation{
+			RuleCode:            models.RuleMaxDailyUGSLimitViolated,
+			Severity:            models.SeverityViolation,
+			WindowStart:         trip.CalculatedDutyPeriodStart,
+			WindowEnd:           trip.LastLegArrivalTime,
+			MeasuredValue:       float64(trip.CalculatedFlightDutyPeriodDurationMin),
+			Limit:               float64(maxUGSLimitMin),
+			Unit:                models.UnitMinutes,
+			ContributingTripIDs: []string{trip.TripID},
+		})
 	}
 }
 
 // RecalculateCrewSchedule, belirli bir ekip üyesinin tüm actual kayıtlarını çekip,
 // bunları trip_id'ye göre gruplayarak FTL hesaplamalarını yapar ve trips tablosuna kaydeder.
 func (f *FTLCalculator) RecalculateCrewSchedule(crewID string) error {
+	_, err := f.RecalculateCrewScheduleWithStats(context.Background(), crewID)
+	return err
+}
+
+// CrewRecalcStats, bir ekip üyesinin tüm program yeniden hesaplamasının özetidir.
+type CrewRecalcStats struct {
+	TripsSaved      int
+	ViolationsFound int
+	Errors          []string
+}
+
+// RecalculateCrewScheduleWithStats, RecalculateCrewSchedule ile aynı hesabı yapar; kaydedilen trip ve bulunan
+// ihlal sayılarını döndürür. ctx iptal edilirse kalan tripler hesaplanmadan ctx.Err() ile döner.
+func (f *FTLCalculator) RecalculateCrewScheduleWithStats(ctx context.Context, crewID string) (CrewRecalcStats, error) {
+	var stats CrewRecalcStats
 	log.Printf("Bilgi: Ekip %s için tüm program FTL hesaplaması başlatıldı.", crewID)
 
 	// Fix: Add context.Background() and a lookbackDays (e.g., 365 for a year)
-	actuals, err := f.actualRepo.GetActualsByPersonID(context.Background(), crewID, time.Now().AddDate(-1, 0, -28), 365)
+	actuals, err := f.actualRepo.GetActualsByPersonID(ctx, crewID, time.Now().AddDate(-1, 0, -28), 365)
 	if err != nil {
-		return fmt.Errorf("ekip %s için actual kayıtları çekilemedi: %w", crewID, err)
+		return stats, fmt.Errorf("ekip %s için actual kayıtları çekilemedi: %w", crewID, err)
 	}
 
 	if len(actuals) == 0 {
 		log.Printf("Bilgi: Ekip %s için hesaplanacak actual kayıt bulunamadı.", crewID)
-		return nil
+		return stats, nil
 	}
 
 	tripsMap := make(map[string]*models.Trip)
@@ -576,7 +766,7 @@ func (f *FTLCalculator) RecalculateCrewSchedule(crewID string) error {
 			// trip.AircraftType = models.GetAircraftTypeFromCmsType(firstAct.PlaneCmsType) // <<< Bu satır kaldırılmalı
 			trip.DutyStartAirport = firstAct.DeparturePort
 			trip.DutyType = models.GetDutyTypeFromActual(firstAct) // Genel DutyType
-			trip.CrewType = models.GetCrewTypeFromFlightPosition(firstAct.FlightPosition)
+			trip.CrewType = models.GetCrewTypeFromActivities(trip.Activities)
 		} else {
 			// trip.AircraftType = "BİLİNMİYOR" // <<< Bu satır kaldırılmalı
 			trip.DutyStartAirport = "BİLİNMİYOR"
@@ -615,15 +805,28 @@ func (f *FTLCalculator) RecalculateCrewSchedule(crewID string) error {
 		return sortedTrips[i].FirstLegDepartureTime.Before(sortedTrips[j].FirstLegDepartureTime)
 	})
 
+	state := f.newCrewScheduleState(ctx, crewID, sortedTrips)
 	for _, trip := range sortedTrips {
-		if err := f.CalculateFTLForTrip(trip, sortedTrips); err != nil {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		if err := f.calculateFTLForTrip(trip, state); err != nil {
 			log.Printf("Hata: Trip %s için FTL hesaplanırken sorun: %v", trip.TripID, err)
+			stats.Errors = append(stats.Errors, fmt.Sprintf("trip %s: %v", trip.TripID, err))
 		}
 		if err := f.tripRepo.SaveTrip(trip); err != nil {
 			log.Printf("Hata: Trip %s FTL hesaplaması sonrası kaydedilirken sorun: %v", trip.TripID, err)
+			stats.Errors = append(stats.Errors, fmt.Sprintf("trip %s: %v", trip.TripID, err))
+			continue
+		}
+		stats.TripsSaved++
+		for _, v := range trip.FTLViolations {
+			if v.Severity == models.SeverityViolation {
+				stats.ViolationsFound++
+			}
 		}
 	}
 
 	log.Printf("✅ Ekip %s için tüm program FTL hesaplaması tamamlandı.", crewID)
-	return nil
+	return stats, nil
 }
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+func TestRuleSetForDate(t *testing.T) {
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	// ListActiveRuleSets sırası: en geç yürürlüğe giren, eşitlikte en yüksek versiyon önce
+	ruleSets := []models.FTLRuleSet{
+		{Version: 4, EffectiveFrom: mar, EffectiveTo: &jun},
+		{Version: 3, EffectiveFrom: mar},
+		{Version: 2, EffectiveFrom: jan},
+	}
+
+	tests := []struct {
+		name        string
+		ruleSets    []models.FTLRuleSet
+		at          time.Time
+		wantVersion int
+	}{
+		{"yürürlük başlangıcı dahildir", ruleSets, mar, 4},
+		{"yürürlük bitişi hariçtir, sonraki aday seçilir", ruleSets, jun, 3},
+		{"daha geç yürürlüğe giren set yokken eski set geçerlidir", ruleSets, jan.AddDate(0, 1, 0), 2},
+		{"yürürlükte set yoksa varsayılan limitler", ruleSets, jan.AddDate(0, 0, -1), models.DefaultFTLRuleSet().Version},
+		{"set yüklenemediyse varsayılan limitler", nil, mar, models.DefaultFTLRuleSet().Version},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleSetForDate(tt.ruleSets, tt.at); got.Version != tt.wantVersion {
+				t.Errorf("versiyon = %d, beklenen %d", got.Version, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// ExplainTrip, trip hesaplanırken kaydedilen değerlerin kaynağını (eşleşen brief/debrief kuralı, zaman dilimleri,
+// görev süresi türetmesi, uygulanan UGS limiti, kümülatif pencerelere katkı veren tripler) döndürür. Kayıt bu alanlardan
+// önce hesaplanmışsa veya yeniden hesaplama bekliyorsa trip, programda kendisinden önceki triplerle yeniden
+// hesaplanır. Hiçbir şey kaydedilmez; trip bulunamazsa nil, nil döner.
+func (f *FTLCalculator) ExplainTrip(ctx context.Context, tripID string) (*models.FTLExplanation, error) {
+	stored, err := f.tripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, nil
+	}
+	source := models.ExplanationSourceRecorded
+	trip := stored
+	if stored.CumulativeWindows == nil || stored.RecalcRequired || (stored.AppliedFDPLimit == nil && fdpSectorCount(stored) > 0) {
+		source = models.ExplanationSourceRecalculated
+		trip = copyTrip(stored)
+	}
+
+	crewTrips, err := f.tripRepo.GetTripsByCrewMemberID(trip.CrewMemberID, time.Now().AddDate(-1, 0, -28))
+	if err != nil {
+		return nil, fmt.Errorf("ekip %s tripleri çekilemedi: %w", trip.CrewMemberID, err)
+	}
+	allCrewTrips := make([]*models.Trip, 0, len(crewTrips)+1)
+	found := false
+	for i := range crewTrips {
+		if crewTrips[i].TripID == trip.TripID {
+			allCrewTrips = append(allCrewTrips, trip)
+			found = true
+			continue
+		}
+		allCrewTrips = append(allCrewTrips, &crewTrips[i])
+	}
+	if !found {
+		allCrewTrips = append(allCrewTrips, trip)
+		sort.SliceStable(allCrewTrips, func(i, j int) bool {
+			return allCrewTrips[i].FirstLegDepartureTime.Before(allCrewTrips[j].FirstLegDepartureTime)
+		})
+	}
+
+	// Yeniden hesaplamada kümülatif pencereler yalnızca bu trip ve öncekilerle kurulur
+	if source == models.ExplanationSourceRecalculated {
+		if err := f.CalculateFTLForTrip(ctx, trip, allCrewTrips); err != nil {
+			return nil, err
+		}
+	}
+
+	preferredLocation := f.preferredLocation(trip.CrewMemberID)
+	crewBaseAirport, baseLocation := f.crewBase(ctx, trip.CrewMemberID, preferredLocation)
+
+	explanation := &models.FTLExplanation{
+		TripID:         trip.TripID,
+		CrewMemberID:   trip.CrewMemberID,
+		GeneratedAt:    time.Now(),
+		Source:         source,
+		Regulation:     trip.Regulation,
+		RuleSetVersion: trip.RuleSetVersion,
+		RuleSetName:    f.ruleSetName(ctx, trip.RuleSetVersion),
+		TimeZones: models.TimeZoneExplanation{
+			PreferredTZ:                preferredLocation.String(),
+			CrewBaseAirport:            crewBaseAirport,
+			BaseTZ:                     baseLocation.String(),
+			AcclimatisationState:       trip.AcclimatisationState,
+			AcclimatisationReferenceTZ: trip.AcclimatisationReferenceTZ,
+		},
+		Violations: trip.FTLViolations,
+	}
+
+	// Brief/debrief: hesaplamada eşleşen ve trip'e kaydedilen kurallar
+	explanation.Brief = models.BriefDebriefExplanation{
+		RuleID: trip.BriefRuleID, DefaultApplied: trip.BriefRuleID == 0, CrewType: trip.CrewType, DutyType: trip.BriefTripType,
+		AircraftType: trip.BriefAircraftType, DutyStartAirport: trip.DutyStartAirport, DurationMin: trip.CalculatedBriefDurationMin,
+	}
+	explanation.Debrief = models.BriefDebriefExplanation{
+		RuleID: trip.DebriefRuleID, DefaultApplied: trip.DebriefRuleID == 0, CrewType: trip.CrewType, DutyType: trip.DebriefTripType,
+		AircraftType: trip.DebriefAircraftType, DutyStartAirport: trip.DutyStartAirport, DurationMin: trip.CalculatedDebriefDurationMin,
+	}
+
+	derivation := models.DutyDerivationBriefLegs
+	if len(trip.Activities) > 0 {
+		derivation = models.DutyDerivationActivities
+	}
+	explanation.DutyPeriod = models.DutyPeriodExplanation{
+		Derivation:            derivation,
+		FirstLegDepartureTime: trip.FirstLegDepartureTime.In(preferredLocation),
+		LastLegArrivalTime:    trip.LastLegArrivalTime.In(preferredLocation),
+		Start:                 trip.CalculatedDutyPeriodStart,
+		End:                   trip.CalculatedDutyPeriodEnd,
+		DurationMin:           trip.CalculatedDutyPeriodDurationMin,
+		FDPDurationMin:        trip.CalculatedFlightDutyPeriodDurationMin,
+		CountedDutyMin:        countedDutyMin(trip),
+		BlockTimeMin:          trip.BlockTimeMin,
+		SectorCount:           trip.SectorCount,
+	}
+
+	if trip.RestLocation != "" {
+		for i, t := range allCrewTrips {
+			if t.TripID == trip.TripID && i > 0 {
+				explanation.Rest = &models.RestExplanation{
+					PrevTripID:  allCrewTrips[i-1].TripID,
+					Start:       trip.CalculatedRestPeriodStart,
+					End:         trip.CalculatedRestPeriodEnd,
+					DurationMin: trip.CalculatedRestPeriodDurationMin,
+					Location:    trip.RestLocation,
+					RequiredMin: trip.MinRestRequiredMin,
+				}
+				break
+			}
+		}
+	}
+
+	// UGS limiti: hesaplamada uygulanan ve trip'e kaydedilen tablo satırı, düzeltmeler ve son limit
+	explanation.FDPLimit = trip.AppliedFDPLimit
+
+	// Kümülatif pencereler: hesaplamada kaydedilen toplamlar ve katkı veren tripler
+	explanation.CumulativeWindows = trip.CumulativeWindows
+
+	return explanation, nil
+}
+
+// ruleSetName, trip'e kaydedilen kural seti versiyonunun adını döndürür. Versiyon 0 yerleşik varsayılan settir;
+// versiyon bulunamazsa veya sorgu başarısız olursa boş döner.
+func (f *FTLCalculator) ruleSetName(ctx context.Context, version int) string {
+	if version == 0 || f.ruleSetRepo == nil {
+		return models.DefaultFTLRuleSet().Name
+	}
+	ruleSet, err := f.ruleSetRepo.GetRuleSetByVersion(ctx, version)
+	if err != nil {
+		log.Printf("Uyarı: FTL kural seti v%d adı alınamadı: %v", version, err)
+		return ""
+	}
+	if ruleSet == nil {
+		return ""
+	}
+	return ruleSet.Name
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+)
+
+// Toplu yeniden hesaplama işi parametreleri
+const (
+	DefaultRecalcWorkers = 4  // İstekte worker sayısı verilmezse kullanılır
+	MaxRecalcWorkers     = 16 // Veritabanı bağlantı havuzunu korumak için üst sınır
+	maxRecalcJobErrors   = 100
+)
+
+// ProgressFunc, iş ilerlemesini istemciye iletir (ör. progress.SendProgressUpdate).
+type ProgressFunc func(processID string, progressPercent int, message string)
+
+// FTLRecalcJobRunner, birden fazla ekip üyesinin FTL programını sınırlı sayıda worker ile arka planda
+// yeniden hesaplar, ilerlemeyi bildirir ve iş özetini ftl_recalc_jobs tablosuna yazar.
+// Dönem (period) kapsamlı işlerde ekip üyelerinin o aydaki boş gün uygunluğu da kontrol edilir.
+type FTLRecalcJobRunner struct {
+	ftlCalc       *FTLCalculator
+	offDayChecker *OffDayComplianceChecker
+	jobRepo       *repositories.FTLRecalcJobRepository
+	progress      ProgressFunc
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewFTLRecalcJobRunner, FTLRecalcJobRunner'ın yeni bir örneğini oluşturur. offDayChecker ve progress nil olabilir.
+func NewFTLRecalcJobRunner(ftlCalc *FTLCalculator, offDayChecker *OffDayComplianceChecker, jobRepo *repositories.FTLRecalcJobRepository, progress ProgressFunc) *FTLRecalcJobRunner {
+	return &FTLRecalcJobRunner{
+		ftlCalc:       ftlCalc,
+		offDayChecker: offDayChecker,
+		jobRepo:       jobRepo,
+		progress:      progress,
+		cancels:       map[string]context.CancelFunc{},
+	}
+}
+
+func (r *FTLRecalcJobRunner) report(jobID string, percent int, message string) {
+	if r.progress != nil {
+		r.progress(jobID, percent, message)
+	}
+}
+
+// Start, işi kaydeder ve verilen ekip üyelerini arka planda yeniden hesaplamaya başlar.
+// Kaydedilen iş hemen döner; ilerleme job.JobID process_id'si üzerinden bildirilir.
+func (r *FTLRecalcJobRunner) Start(job *models.FTLRecalcJob, crewIDs []string) error {
+	if job.Workers <= 0 {
+		job.Workers = DefaultRecalcWorkers
+	}
+	if job.Workers > MaxRecalcWorkers {
+		job.Workers = MaxRecalcWorkers
+	}
+	job.Status = models.RecalcJobRunning
+	job.TotalCrew = len(crewIDs)
+	job.StartedAt = time.Now()
+	if err := r.jobRepo.CreateJob(context.Background(), job); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.cancels[job.JobID] = cancel
+	r.mu.Unlock()
+
+	go r.run(ctx, job, crewIDs)
+	return nil
+}
+
+// Cancel, çalışan işi iptal eder. İş bu süreçte çalışmıyorsa false döner.
+func (r *FTLRecalcJobRunner) Cancel(jobID string) bool {
+	r.mu.Lock()
+	cancel, ok := r.cancels[jobID]
+	r.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func (r *FTLRecalcJobRunner) run(ctx context.Context, job *models.FTLRecalcJob, crewIDs []string) {
+	defer func() {
+		r.mu.Lock()
+		if cancel, ok := r.cancels[job.JobID]; ok {
+			cancel()
+			delete(r.cancels, job.JobID)
+		}
+		r.mu.Unlock()
+	}()
+
+	log.Printf("🔹 FTL yeniden hesaplama işi %s başladı: %s=%s, %d ekip üyesi, %d worker.", job.JobID, job.Scope, job.ScopeValue, len(crewIDs), job.Workers)
+	r.report(job.JobID, 0, fmt.Sprintf("%d ekip üyesi için FTL yeniden hesaplaması başlıyor...", len(crewIDs)))
+
+	// Dönem kapsamlı işlerde boş gün uygunluğu da kontrol edilir
+	var offDayPeriod time.Time
+	if job.Scope == models.RecalcScopePeriod && r.offDayChecker != nil {
+		period, err := ParseOffDayPeriod(job.ScopeValue)
+		if err != nil {
+			log.Printf("⚠️ İş %s: boş gün uygunluğu kontrol edilmeyecek: %v", job.JobID, err)
+		} else {
+			offDayPeriod = period
+		}
+	}
+
+	crewCh := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < job.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for crewID := range crewCh {
+				stats, err := r.ftlCalc.RecalculateCrewScheduleWithStats(ctx, crewID)
+				var offDay *models.OffDayCompliance
+				var offDayErr error
+				if !offDayPeriod.IsZero() && ctx.Err() == nil {
+					offDay, offDayErr = r.offDayChecker.CheckCrewPeriod(ctx, crewID, offDayPeriod)
+				}
+
+				mu.Lock()
+				if err != nil && ctx.Err() == nil {
+					stats.Errors = append(stats.Errors, fmt.Sprintf("ekip %s: %v", crewID, err))
+				}
+				if offDayErr != nil && ctx.Err() == nil {
+					stats.Errors = append(stats.Errors, fmt.Sprintf("ekip %s boş gün kontrolü: %v", crewID, offDayErr))
+				}
+				if offDay != nil {
+					stats.ViolationsFound += len(offDay.Violations)
+				}
+				if ctx.Err() == nil {
+					job.CrewProcessed++
+				}
+				job.TripsSaved += stats.TripsSaved
+				job.ViolationsFound += stats.ViolationsFound
+				job.ErrorCount += len(stats.Errors)
+				for _, e := range stats.Errors {
+					if len(job.Errors) < maxRecalcJobErrors {
+						job.Errors = append(job.Errors, e)
+					}
+				}
+				processed, total := job.CrewProcessed, job.TotalCrew
+				mu.Unlock()
+
+				if ctx.Err() == nil && total > 0 {
+					r.report(job.JobID, processed*100/total, fmt.Sprintf("%d/%d ekip üyesi işlendi...", processed, total))
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, crewID := range crewIDs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case crewCh <- crewID:
+		}
+	}
+	close(crewCh)
+	wg.Wait()
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	switch {
+	case ctx.Err() != nil:
+		job.Status = models.RecalcJobCancelled
+	case job.ErrorCount > 0 && job.CrewProcessed == 0:
+		job.Status = models.RecalcJobFailed
+	default:
+		job.Status = models.RecalcJobCompleted
+	}
+	if err := r.jobRepo.UpdateJob(context.Background(), job); err != nil {
+		log.Printf("❌ FTL yeniden hesaplama işi %s özeti kaydedilemedi: %v", job.JobID, err)
+	}
+
+	message := fmt.Sprintf("FTL yeniden hesaplaması %s: %d/%d ekip üyesi, %d trip, %d ihlal, %d hata.",
+		job.Status, job.CrewProcessed, job.TotalCrew, job.TripsSaved, job.ViolationsFound, job.ErrorCount)
+	r.report(job.JobID, 100, message)
+	log.Printf("✅ İş %s: %s", job.JobID, message)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Kümülatif pencere kural kodlarının headroom türü ve etiketi
+var headroomWindows = map[string][2]string{
+	models.RuleMaxDutyPeriod7DaysExceeded:    {models.HeadroomDuty, "7d"},
+	models.RuleMaxDutyPeriod14DaysExceeded:   {models.HeadroomDuty, "14d"},
+	models.RuleMaxDutyPeriod28DaysExceeded:   {models.HeadroomDuty, "28d"},
+	models.RuleMaxDutyPeriodYearExceeded:     {models.HeadroomDuty, "year"},
+	models.RuleMaxFlightTime28DaysExceeded:   {models.HeadroomFlight, "28d"},
+	models.RuleMaxFlightTime12MonthsExceeded: {models.HeadroomFlight, "12m"},
+	models.RuleMaxFlightTimeYearExceeded:     {models.HeadroomFlight, "year"},
+}
+
+// CrewHeadroom, ekip üyesinin at anında sona eren kümülatif pencerelerde kalan görev/uçuş süresini ve son
+// görevinden sonraki en erken yasal raporlama zamanını kayıtlı trip hesaplamalarından çıkarır.
+// Pencereler ekibe atanan mevzuat çerçevesine (tescil ataması trip'e bağlı olduğundan yalnızca ekip ataması) göre seçilir.
+func (f *FTLCalculator) CrewHeadroom(ctx context.Context, crewID string, at time.Time) (*models.CrewHeadroom, error) {
+	stored, err := f.tripRepo.GetTripsByCrewMemberID(crewID, at.AddDate(-1, 0, -28))
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	trips := make([]*models.Trip, len(stored))
+	for i := range stored {
+		trips[i] = &stored[i]
+	}
+
+	preferredLocation := f.preferredLocation(crewID)
+	regulationCode := selectRegulation(&models.Trip{CrewMemberID: crewID}, f.regulationAssignments(ctx))
+	regulation := f.regulationFor(regulationCode)
+	ruleSetAt := ruleSetForDate(f.activeRuleSets(ctx), at)
+	ruleSet := regulation.CumulativeLimits(ruleSetAt)
+
+	headroom := &models.CrewHeadroom{
+		CrewMemberID:          crewID,
+		At:                    at,
+		Regulation:            regulationCode,
+		MinRemainingDutyMin:   -1,
+		MinRemainingFlightMin: -1,
+	}
+
+	probe := &models.Trip{CalculatedDutyPeriodEnd: at}
+	for _, w := range NewCumulativeWindowEngine(trips).Windows(probe, ruleSet, preferredLocation) {
+		kind := headroomWindows[w.RuleCode]
+		remaining := w.LimitMin - w.TotalMin
+		if remaining < 0 {
+			remaining = 0
+		}
+		headroom.Windows = append(headroom.Windows, models.WindowHeadroom{
+			Kind:         kind[0],
+			Window:       kind[1],
+			RuleCode:     w.RuleCode,
+			WindowStart:  w.WindowStart,
+			WindowEnd:    w.WindowEnd,
+			UsedMin:      w.TotalMin,
+			LimitMin:     w.LimitMin,
+			RemainingMin: remaining,
+		})
+		minRemaining := &headroom.MinRemainingDutyMin
+		if kind[0] == models.HeadroomFlight {
+			minRemaining = &headroom.MinRemainingFlightMin
+		}
+		if *minRemaining < 0 || remaining < *minRemaining {
+			*minRemaining = remaining
+		}
+	}
+	if headroom.MinRemainingDutyMin < 0 {
+		headroom.MinRemainingDutyMin = 0
+	}
+	if headroom.MinRemainingFlightMin < 0 {
+		headroom.MinRemainingFlightMin = 0
+	}
+
+	// Son görev: at anından önce başlamış, görev bitişi en geç olan trip
+	var last *models.Trip
+	for _, t := range trips {
+		if t.CalculatedDutyPeriodStart.IsZero() || !t.CalculatedDutyPeriodStart.Before(at) {
+			continue
+		}
+		if last == nil || t.CalculatedDutyPeriodEnd.After(last.CalculatedDutyPeriodEnd) {
+			last = t
+		}
+	}
+	if last != nil {
+		crewBaseAirport, _ := f.crewBase(ctx, crewID, preferredLocation)
+		next := &models.Trip{CrewMemberID: crewID, DutyStartAirport: tripEndAirport(last)}
+		location, minRest := requiredMinRest(next, last, crewBaseAirport, f.baseAirportMap(ctx), ruleSetAt, regulation)
+		lastEnd := last.CalculatedDutyPeriodEnd.In(preferredLocation)
+		earliest := lastEnd.Add(time.Duration(minRest) * time.Minute)
+		headroom.LastDutyTripID = last.TripID
+		headroom.LastDutyEnd = &lastEnd
+		headroom.RestLocation = location
+		headroom.MinRestRequiredMin = minRest
+		headroom.EarliestReportTime = &earliest
+	}
+
+	return headroom, nil
+}
+
+// RankCrewHeadroom, verilen ekip üyelerinin at anındaki headroom'unu hesaplar ve en çok yeri kalandan en aza
+// doğru sıralar. sortBy "flight" ise uçuş pencerelerindeki, aksi halde görev pencerelerindeki en düşük kalan süre
+// esas alınır; eşitlikte en erken yasal raporlama zamanı önce gelir.
+func (f *FTLCalculator) RankCrewHeadroom(ctx context.Context, crewIDs []string, at time.Time, sortBy string) ([]models.CrewHeadroom, error) {
+	result := make([]models.CrewHeadroom, 0, len(crewIDs))
+	for _, crewID := range crewIDs {
+		headroom, err := f.CrewHeadroom(ctx, crewID, at)
+		if err != nil {
+			return nil, fmt.Errorf("ekip %s headroom hesaplanamadı: %w", crewID, err)
+		}
+		result = append(result, *headroom)
+	}
+
+	key := func(h models.CrewHeadroom) int {
+		if sortBy == models.HeadroomFlight {
+			return h.MinRemainingFlightMin
+		}
+		return h.MinRemainingDutyMin
+	}
+	earliest := func(h models.CrewHeadroom) time.Time {
+		if h.EarliestReportTime == nil {
+			return time.Time{}
+		}
+		return *h.EarliestReportTime
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if ki, kj := key(result[i]), key(result[j]); ki != kj {
+			return ki > kj
+		}
+		return earliest(result[i]).Before(earliest(result[j]))
+	})
+	return result, nil
+}
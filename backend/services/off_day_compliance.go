@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+	"mini_CMS_Desktop_App/repositories"
+)
+
+// offDayPeriodLayouts, dönem (takvim ayı) değerleri için kabul edilen biçimlerdir (actuals.period_month dahil).
+var offDayPeriodLayouts = []string{"2006-01", "01.2006", "01/2006", "2006/01", "01-2006", "200601"}
+
+// ParseOffDayPeriod, "YYYY-MM" veya actuals.period_month biçimindeki dönemi ayın ilk gününe çevirir.
+func ParseOffDayPeriod(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range offDayPeriodLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("geçersiz dönem '%s' (YYYY-MM bekleniyor)", value)
+}
+
+// OffDayComplianceChecker, ekip üyelerinin takvim ayı bazında çalışılan ve boş gün sayılarını actuals'tan
+// hesaplar, off_day_table'dan hak edişi bulur, dağılımı kontrol eder ve sonucu off_day_compliance tablosuna yazar.
+type OffDayComplianceChecker struct {
+	actualRepo     *repositories.ActualRepository
+	offDayRepo     *repositories.OffDayTableRepository
+	complianceRepo *repositories.OffDayComplianceRepository
+	crewInfoRepo   *repositories.CrewInfoRepository
+}
+
+// NewOffDayComplianceChecker, OffDayComplianceChecker'ın yeni bir örneğini oluşturur.
+func NewOffDayComplianceChecker(actualRepo *repositories.ActualRepository, offDayRepo *repositories.OffDayTableRepository, complianceRepo *repositories.OffDayComplianceRepository, crewInfoRepo *repositories.CrewInfoRepository) *OffDayComplianceChecker {
+	return &OffDayComplianceChecker{
+		actualRepo:     actualRepo,
+		offDayRepo:     offDayRepo,
+		complianceRepo: complianceRepo,
+		crewInfoRepo:   crewInfoRepo,
+	}
+}
+
+// crewBase, ekip üyesinin ana üssünü ve zaman dilimini döndürür; günler ana üs yerel saatine göre sayılır.
+// Ana üs bilinmiyorsa UTC kullanılır.
+func (o *OffDayComplianceChecker) crewBase(ctx context.Context, crewID string) (string, *time.Location) {
+	crewInfo, err := o.crewInfoRepo.GetCrewInfoByPersonID(ctx, crewID)
+	if err != nil {
+		log.Printf("Uyarı: Ekip %s için ana üs bilgisi çekilemedi: %v. Boş gün sayımında UTC kullanılıyor.", crewID, err)
+		return "", time.UTC
+	}
+	if crewInfo == nil || crewInfo.BaseLocation == "" {
+		return "", time.UTC
+	}
+	base := strings.ToUpper(strings.TrimSpace(crewInfo.BaseLocation))
+	if loc, ok := models.GetAirportLocation(base); ok {
+		return base, loc
+	}
+	return base, time.UTC
+}
+
+// CheckCrewPeriods, verilen ekip üyelerinin dönem sonuçlarını hesaplayıp kaydeder. Tek bir ekip üyesindeki hata
+// diğerlerini durdurmaz; hatalar sonuçlarla birlikte döndürülür.
+func (o *OffDayComplianceChecker) CheckCrewPeriods(ctx context.Context, crewIDs []string, period time.Time) ([]models.OffDayCompliance, []string) {
+	entitlements, err := o.offDayRepo.ListEntitlements(ctx)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	results := make([]models.OffDayCompliance, 0, len(crewIDs))
+	var errs []string
+	for _, crewID := range crewIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		result, err := o.checkCrewPeriod(ctx, crewID, period, entitlements)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("ekip %s: %v", crewID, err))
+			continue
+		}
+		results = append(results, *result)
+	}
+	return results, errs
+}
+
+// CheckCrewPeriod, ekip üyesinin verilen takvim ayındaki boş gün uygunluğunu hesaplar ve kaydeder.
+func (o *OffDayComplianceChecker) CheckCrewPeriod(ctx context.Context, crewID string, period time.Time) (*models.OffDayCompliance, error) {
+	entitlements, err := o.offDayRepo.ListEntitlements(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.checkCrewPeriod(ctx, crewID, period, entitlements)
+}
+
+func (o *OffDayComplianceChecker) checkCrewPeriod(ctx context.Context, crewID string, period time.Time, entitlements []models.OffDayTable) (*models.OffDayCompliance, error) {
+	base, loc := o.crewBase(ctx, crewID)
+	periodStart := time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, loc)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	actuals, err := o.actualRepo.GetActualsInRange(ctx, crewID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := evaluateOffDayCompliance(actuals, entitlements, periodStart, periodEnd, loc)
+	result.CrewMemberID = crewID
+	result.PeriodMonth = periodStart.Format("2006-01")
+	result.BaseLocation = base
+	if err := o.complianceRepo.UpsertCompliance(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// evaluateOffDayCompliance, dönemin her gününü aktivite kodlarına göre sınıflandırır (frontend rowRules.ts ile aynı
+// kural: hiç aktivitesi olmayan veya boş gün kodu içeren gün boş gün; aktivitesi olup çalışma dışı kod içermeyen gün
+// çalışılmış gün sayılır), hak edişi ve dağılımı kontrol eder.
+func evaluateOffDayCompliance(actuals []models.Actual, entitlements []models.OffDayTable, periodStart, periodEnd time.Time, loc *time.Location) *models.OffDayCompliance {
+	dayCount := 0
+	for d := periodStart; d.Before(periodEnd); d = d.AddDate(0, 0, 1) {
+		dayCount++
+	}
+	dayCodes := make([][]string, dayCount)
+	dayTrips := make([][]string, dayCount)
+	dayIndex := func(t time.Time) int {
+		local := t.In(loc)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		return int(day.Sub(periodStart).Hours()/24 + 0.5)
+	}
+
+	for _, act := range actuals {
+		start, end := act.DepartureTime, act.ArrivalTime
+		if start.IsZero() {
+			start, end = act.DutyStart, act.DutyEnd
+		}
+		if start.IsZero() {
+			continue
+		}
+		if end.After(start) {
+			end = end.Add(-time.Nanosecond) // Gece yarısında biten aktivite ertesi günü işaretlemez
+		} else {
+			end = start
+		}
+		first, last := dayIndex(start), dayIndex(end)
+		if first < 0 {
+			first = 0
+		}
+		if last >= dayCount {
+			last = dayCount - 1
+		}
+		for i := first; i <= last; i++ {
+			dayCodes[i] = append(dayCodes[i], act.ActivityCode)
+			if act.TripID != "" {
+				dayTrips[i] = append(dayTrips[i], act.TripID)
+			}
+		}
+	}
+
+	result := &models.OffDayCompliance{
+		PeriodStart:     periodStart,
+		PeriodEnd:       periodEnd,
+		OffDayBlocks:    []int{},
+		DistributionMet: true,
+		Violations:      []models.FTLViolation{},
+	}
+	workedTrips := map[string]bool{}
+	run := 0
+	for i, codes := range dayCodes {
+		off, worked := len(codes) == 0, len(codes) > 0
+		for _, code := range codes {
+			if models.IsOffDayActivityCode(code) {
+				off = true
+			}
+			if models.IsNonWorkingActivityCode(code) {
+				worked = false
+			}
+		}
+		if worked {
+			result.WorkedDays++
+			for _, tripID := range dayTrips[i] {
+				workedTrips[tripID] = true
+			}
+		}
+		if off {
+			result.OffDays++
+			run++
+			continue
+		}
+		if run > 0 {
+			result.OffDayBlocks = append(result.OffDayBlocks, run)
+			run = 0
+		}
+	}
+	if run > 0 {
+		result.OffDayBlocks = append(result.OffDayBlocks, run)
+	}
+
+	// Hak ediş: çalışılan gün sayısını aşmayan en büyük work_days satırı
+	for _, row := range entitlements {
+		if int(row.WorkDays) <= result.WorkedDays {
+			result.EntitlementWorkDays = int(row.WorkDays)
+			result.OffDayEntitlement = int(row.OffDayEntitlement)
+			result.Distribution = row.Distribution
+			break
+		}
+	}
+
+	contributing := make([]string, 0, len(workedTrips))
+	for tripID := range workedTrips {
+		contributing = append(contributing, tripID)
+	}
+	sort.Strings(contributing)
+
+	if result.OffDayEntitlement > result.OffDays {
+		result.ShortfallDays = result.OffDayEntitlement - result.OffDays
+		result.Violations = append(result.Violations, models.FTLViolation{
+			RuleCode:            models.RuleOffDayEntitlementShortfall,
+			Severity:            models.SeverityViolation,
+			WindowStart:         periodStart,
+			WindowEnd:           periodEnd,
+			MeasuredValue:       float64(result.OffDays),
+			Limit:               float64(result.OffDayEntitlement),
+			Unit:                models.UnitCount,
+			ContributingTripIDs: contributing,
+		})
+	}
+
+	result.RequiredBlocks = models.ParseOffDayDistribution(result.Distribution)
+	if len(result.RequiredBlocks) > 0 {
+		satisfied := matchOffDayBlocks(result.RequiredBlocks, result.OffDayBlocks)
+		if satisfied < len(result.RequiredBlocks) {
+			result.DistributionMet = false
+			result.Violations = append(result.Violations, models.FTLViolation{
+				RuleCode:            models.RuleOffDayDistributionNotMet,
+				Severity:            models.SeverityViolation,
+				WindowStart:         periodStart,
+				WindowEnd:           periodEnd,
+				MeasuredValue:       float64(satisfied),
+				Limit:               float64(len(result.RequiredBlocks)),
+				Unit:                models.UnitCount,
+				ContributingTripIDs: contributing,
+			})
+		}
+	}
+	return result
+}
+
+// matchOffDayBlocks, istenen her ardışık boş gün bloğunu en az o uzunluktaki ayrı bir gerçek blokla eşleştirir
+// (en uzun istenenden başlayarak) ve karşılanan blok sayısını döndürür.
+func matchOffDayBlocks(required, actual []int) int {
+	req := append([]int(nil), required...)
+	sort.Sort(sort.Reverse(sort.IntSlice(req)))
+	avail := append([]int(nil), actual...)
+	sort.Ints(avail)
+
+	satisfied := 0
+	for _, need := range req {
+		// İhtiyacı karşılayan en kısa bloğu kullan; uzun bloklar daha büyük ihtiyaçlara kalsın
+		idx := sort.SearchInts(avail, need)
+		if idx == len(avail) {
+			continue
+		}
+		avail = append(avail[:idx], avail[idx+1:]...)
+		satisfied++
+	}
+	return satisfied
+}
@@ -52,10 +52,12 @@ func (s *OpenTripService) GetOpenTrips(ctx context.Context, period string) ([]mo
 			continue
 		}
 
-		// 5️⃣ Atanmış ekipleri say
+		// 5️⃣ Atanmış ekipleri crew_positions'taki ihtiyaç sütununa göre say (DH ve sütunu olmayan pozisyonlar sayılmaz)
 		assigned := make(map[string]int)
 		for _, a := range group {
-			assigned[a.FlightPosition]++
+			if column := models.GetCrewNeedColumn(a.FlightPosition); column != "" {
+				assigned[column]++
+			}
 		}
 
 		// 6️⃣ Farkı hesapla
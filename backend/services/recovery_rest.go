@@ -0,0 +1,124 @@
+package services
+
+import (
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Tekrarlayan uzatılmış dinlenme (extended recovery rest) parametreleri (ORO.FTL.235(d), CS FTL.1.235(b))
+const (
+	extendedRecoveryRestMinMin       = 36 * 60  // Asgari uzatılmış dinlenme
+	extendedRecoveryRestDisruptedMin = 60 * 60  // Çok sayıda düzensiz görevden sonraki uzatılmış dinlenme
+	extendedRecoveryRestCycleMin     = 168 * 60 // İki uzatılmış dinlenme arasındaki azami süre
+	extendedRecoveryRestLocalNights  = 2        // Dinlenmenin kapsaması gereken yerel gece sayısı
+	disruptiveDutiesForExtendedRest  = 4        // Dinlenmenin 60 saate uzatılmasını gerektiren düzensiz görev sayısı
+	localNightStartHour              = 22       // Yerel gece: 22:00-08:00 arasına düşen 8 saatlik dönem
+	localNightEndHour                = 8
+	localNightMinDuration            = 8 * time.Hour
+)
+
+// countLocalNights, [start, end) dinlenme aralığının 22:00-08:00 penceresiyle en az 8 saat örtüştüğü yerel gece
+// sayısını döndürür (ör. 23:00-07:00 dinlenmesi bir yerel gecedir).
+func countLocalNights(start, end time.Time, loc *time.Location) int {
+	start, end = start.In(loc), end.In(loc)
+	nights := 0
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	for !day.After(end) {
+		nightStart := time.Date(day.Year(), day.Month(), day.Day(), localNightStartHour, 0, 0, 0, loc)
+		next := day.AddDate(0, 0, 1)
+		nightEnd := time.Date(next.Year(), next.Month(), next.Day(), localNightEndHour, 0, 0, 0, loc)
+		overlapStart, overlapEnd := nightStart, nightEnd
+		if start.After(overlapStart) {
+			overlapStart = start
+		}
+		if end.Before(overlapEnd) {
+			overlapEnd = end
+		}
+		if overlapEnd.Sub(overlapStart) >= localNightMinDuration {
+			nights++
+		}
+		day = next
+	}
+	return nights
+}
+
+// recoveryCycle, uzatılmış dinlenme döngüsünün trip sırası boyunca taşınan durumudur: döngü başlangıcı,
+// döngüdeki tripler ve düzensiz görevler.
+type recoveryCycle struct {
+	start           time.Time
+	tripIDs         []string
+	disruptiveIDs   []string
+	disruptiveCount int
+	reported        bool
+	prev            *models.Trip
+}
+
+// step, trip'i ana üs yerel saatiyle döngüye katar ve trip'in ihlallerini döndürür: önceki trip'ten bu yana
+// en az 36 saatlik, iki yerel gece içeren bir dinlenme verildiyse yeni döngü başlar; döngü 168 saati aşarsa
+// döngüyü bozan trip'e, aynı döngüde maxDisruptive sayısını aşan düzensiz görev de sınırı aşan trip'e ihlal yazılır.
+// Triplere görev başlangıç sırasıyla çağrılmalıdır.
+func (c *recoveryCycle) step(t *models.Trip, base *time.Location, maxDisruptive int) []models.FTLViolation {
+	var violations []models.FTLViolation
+
+	if c.prev == nil {
+		c.start = t.CalculatedDutyPeriodStart
+	} else {
+		restStart, restEnd := c.prev.CalculatedDutyPeriodEnd, t.CalculatedDutyPeriodStart
+		restMin := int(restEnd.Sub(restStart).Minutes())
+		if restMin >= extendedRecoveryRestMinMin && countLocalNights(restStart, restEnd, base) >= extendedRecoveryRestLocalNights {
+			if c.disruptiveCount >= disruptiveDutiesForExtendedRest && restMin < extendedRecoveryRestDisruptedMin {
+				violations = append(violations, models.FTLViolation{
+					RuleCode:            models.RuleExtendedRecoveryRestNotExtended,
+					Severity:            models.SeverityViolation,
+					WindowStart:         restStart,
+					WindowEnd:           restEnd,
+					MeasuredValue:       float64(restMin),
+					Limit:               float64(extendedRecoveryRestDisruptedMin),
+					Unit:                models.UnitMinutes,
+					ContributingTripIDs: append(append([]string(nil), c.tripIDs...), t.TripID),
+				})
+			}
+			c.start = restEnd
+			c.tripIDs = nil
+			c.disruptiveIDs = nil
+			c.disruptiveCount = 0
+			c.reported = false
+		}
+	}
+	c.prev = t
+
+	c.tripIDs = append(c.tripIDs, t.TripID)
+	if isDisruptiveDuty(t.CalculatedDutyPeriodStart, t.CalculatedDutyPeriodEnd, base) {
+		c.disruptiveCount++
+		c.disruptiveIDs = append(c.disruptiveIDs, t.TripID)
+		if maxDisruptive > 0 && c.disruptiveCount > maxDisruptive {
+			violations = append(violations, models.FTLViolation{
+				RuleCode:            models.RuleMaxDisruptiveDutiesExceeded,
+				Severity:            models.SeverityViolation,
+				WindowStart:         c.start,
+				WindowEnd:           t.CalculatedDutyPeriodEnd,
+				MeasuredValue:       float64(c.disruptiveCount),
+				Limit:               float64(maxDisruptive),
+				Unit:                models.UnitCount,
+				ContributingTripIDs: append([]string(nil), c.disruptiveIDs...),
+			})
+		}
+	}
+
+	cycleMin := int(t.CalculatedDutyPeriodEnd.Sub(c.start).Minutes())
+	if cycleMin > extendedRecoveryRestCycleMin && !c.reported {
+		c.reported = true
+		violations = append(violations, models.FTLViolation{
+			RuleCode:            models.RuleExtendedRecoveryRestMissing,
+			Severity:            models.SeverityViolation,
+			WindowStart:         c.start,
+			WindowEnd:           t.CalculatedDutyPeriodEnd,
+			MeasuredValue:       float64(cycleMin),
+			Limit:               float64(extendedRecoveryRestCycleMin),
+			Unit:                models.UnitMinutes,
+			ContributingTripIDs: append([]string(nil), c.tripIDs...),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Regulation, bir FTL mevzuat çerçevesinin UGS limitlerini, minimum dinlenme kuralını ve kümülatif limitlerini tanımlar.
+// FTLCalculator trip başına uygulanacak çerçeveyi seçer; çerçeveye özgü olmayan kontroller (bölünmüş görev,
+// yedek görev, düzensiz görevler vb.) tüm çerçevelerde ortak uygulanır.
+type Regulation interface {
+	// Code, trip sonucunda kaydedilen mevzuat kodudur (ör. models.RegulationSHTFTL).
+	Code() string
+	// MaxFDP, trip için azami UGS limitini ve limitin alındığı tablo satırını döndürür.
+	MaxFDP(trip *models.Trip, numSectors int) (FDPLimit, error)
+	// MinRest, önceki görevden sonra verilmesi gereken minimum dinlenmeyi (dakika) döndürür.
+	MinRest(prevTrip *models.Trip, atHomeBase bool, ruleSet models.FTLRuleSet) int
+	// CumulativeLimits, kümülatif görev/uçuş süresi limitlerini çerçeveye göre düzenlenmiş kural setiyle döndürür.
+	// 0 olan limitler çerçevede bulunmadığından kontrol edilmez.
+	CumulativeLimits(ruleSet models.FTLRuleSet) models.FTLRuleSet
+}
+
+// FDPLimit, mevzuat tablosundan alınan azami UGS limitidir (bölünmüş görev uzatması ve yedek düşümü öncesi).
+type FDPLimit struct {
+	LimitMin  int
+	Augmented bool   // Uzatılmış ekip tablosu uygulandı
+	Table     string // Örn: "SHT-FTL Tablo-5"
+	Row       string // Tablo satırını belirleyen girdiler, örn: "06:00-13:29 başlangıç, 3 sektör"
+}
+
+// regulationFor, koda karşılık gelen mevzuat çerçevesini döndürür; bilinmeyen kodlarda varsayılan (SHT-FTL) döner.
+func (f *FTLCalculator) regulationFor(code string) Regulation {
+	switch code {
+	case models.RegulationEASA:
+		return easaRegulation{shtFTLRegulation{f}}
+	case models.RegulationFAA117:
+		return faa117Regulation{}
+	}
+	return shtFTLRegulation{f}
+}
+
+// regulationAssignments, mevzuat atamalarını (konu tipi → konu → mevzuat kodu) getirir. Depo tanımlı değilse veya
+// sorgu başarısız olursa nil döner (tüm triplere models.DefaultRegulation uygulanır).
+func (f *FTLCalculator) regulationAssignments(ctx context.Context) map[string]map[string]string {
+	if f.regulationRepo == nil {
+		return nil
+	}
+	assignments, err := f.regulationRepo.GetAssignmentMap(ctx)
+	if err != nil {
+		log.Printf("Uyarı: Mevzuat atamaları çekilemedi: %v. %s uygulanıyor.", err, models.DefaultRegulation)
+		return nil
+	}
+	return assignments
+}
+
+// selectRegulation, trip'e uygulanacak mevzuat kodunu seçer: FLT sektörlerindeki (DH hariç) uçak tescillerinden
+// atanmış olan ilki, yoksa ekip üyesine yapılan atama, o da yoksa models.DefaultRegulation.
+func selectRegulation(trip *models.Trip, assignments map[string]map[string]string) string {
+	for _, act := range trip.Activities {
+		if !isBlockTimeSector(&act) {
+			continue
+		}
+		registration := strings.ToUpper(strings.TrimSpace(act.PlaneTailName))
+		if code, ok := assignments[models.RegulationSubjectRegistration][registration]; ok && registration != "" {
+			return code
+		}
+	}
+	if code, ok := assignments[models.RegulationSubjectCrew][trip.CrewMemberID]; ok {
+		return code
+	}
+	return models.DefaultRegulation
+}
+
+// --- SHT-FTL ---
+
+// shtFTLRegulation, Türkiye SHT-FTL çerçevesidir: Tablo-5 / bilinmeyen durum tablosu, uzatılmış ekip tablosu,
+// versiyonlu kural setindeki dinlenme minimumları ve kümülatif limitler.
+type shtFTLRegulation struct {
+	calc *FTLCalculator
+}
+
+func (shtFTLRegulation) Code() string { return models.RegulationSHTFTL }
+
+func (r shtFTLRegulation) MaxFDP(trip *models.Trip, numSectors int) (FDPLimit, error) {
+	if limitMin, ok := r.calc.GetMaxDailyUGSAugmented(trip.RestFacilityClass, trip.FlightCrewComplement, numSectors); ok {
+		return FDPLimit{
+			LimitMin:  limitMin,
+			Augmented: true,
+			Table:     "CS FTL.1.205(c) uzatılmış ekip",
+			Row:       fmt.Sprintf("dinlenme tesisi sınıf %d, %d pilot, %d sektör", trip.RestFacilityClass, trip.FlightCrewComplement, numSectors),
+		}, nil
+	}
+	if trip.AcclimatisationState == AcclimatisationUnknown {
+		limitMin, err := r.calc.GetMaxDailyUGSUnknownState(numSectors)
+		return FDPLimit{LimitMin: limitMin, Table: "Aklimatizasyon durumu bilinmeyen", Row: fmt.Sprintf("%d sektör", numSectors)}, err
+	}
+	start := acclimatisedDutyStart(trip)
+	limitMin, err := r.calc.GetMaxDailyUGSTable5(start, numSectors)
+	return FDPLimit{
+		LimitMin: limitMin,
+		Table:    "Tablo-5 (aklimatize)",
+		Row:      fmt.Sprintf("%s (%s) başlangıç, %d sektör", start.Format("15:04"), start.Location(), numSectors),
+	}, err
+}
+
+// MinRest: max(önceki görev süresi, ana üs / ana üs dışı minimumu)
+func (shtFTLRegulation) MinRest(prevTrip *models.Trip, atHomeBase bool, ruleSet models.FTLRuleSet) int {
+	minimum := ruleSet.MinRestAwayMin
+	if atHomeBase {
+		minimum = ruleSet.MinRestHomeBaseMin
+	}
+	if prevDutyMin := countedDutyMin(prevTrip); prevDutyMin > minimum {
+		minimum = prevDutyMin
+	}
+	return minimum
+}
+
+func (shtFTLRegulation) CumulativeLimits(ruleSet models.FTLRuleSet) models.FTLRuleSet {
+	return ruleSet
+}
+
+// --- EASA ORO.FTL ---
+
+// easaRegulation, EASA ORO.FTL çerçevesidir. UGS tabloları (ORO.FTL.205, CS FTL.1.205) SHT-FTL ile aynıdır;
+// dinlenme (ORO.FTL.235) ve kümülatif limitler (ORO.FTL.210) ulusal kural setinden bağımsız sabit değerlerdir.
+type easaRegulation struct {
+	shtFTLRegulation
+}
+
+func (easaRegulation) Code() string { return models.RegulationEASA }
+
+func (r easaRegulation) MinRest(prevTrip *models.Trip, atHomeBase bool, ruleSet models.FTLRuleSet) int {
+	ruleSet.MinRestHomeBaseMin = 12 * 60
+	ruleSet.MinRestAwayMin = 10 * 60
+	return r.shtFTLRegulation.MinRest(prevTrip, atHomeBase, ruleSet)
+}
+
+func (easaRegulation) CumulativeLimits(ruleSet models.FTLRuleSet) models.FTLRuleSet {
+	ruleSet.MaxDuty7DaysMin = 60 * 60
+	ruleSet.MaxDuty14DaysMin = 110 * 60
+	ruleSet.MaxDuty28DaysMin = 190 * 60
+	ruleSet.MaxDutyYearMin = 2000 * 60
+	ruleSet.MaxFlight28DaysMin = 100 * 60
+	ruleSet.MaxFlight12MonthsMin = 1000 * 60
+	ruleSet.MaxFlightYearMin = 900 * 60
+	return ruleSet
+}
+
+// --- FAA Part 117 ---
+
+// FAA Part 117 sabitleri
+const (
+	faa117MinRestMin                 = 10 * 60 // §117.25(e): her UGS öncesi 10 saat (8 saat kesintisiz uyku fırsatı dahil)
+	faa117UnacclimatisedReductionMin = 30      // §117.13(c): aklimatize olmayan ekipte Tablo B limiti 30 dk azaltılır
+)
+
+// faa117TableB, §117 Tablo B'nin (uzatılmamış ekip) satırlarıdır: başlangıç saati bandı ve
+// 1, 2, 3, 4, 5, 6, 7+ sektör için limitler (dakika).
+var faa117TableB = []struct {
+	fromHour, toHour int // [fromHour, toHour)
+	limits           [7]int
+}{
+	{0, 4, [7]int{540, 540, 540, 540, 540, 540, 540}},
+	{4, 5, [7]int{600, 600, 600, 600, 540, 540, 540}},
+	{5, 6, [7]int{720, 720, 720, 720, 690, 660, 630}},
+	{6, 7, [7]int{780, 780, 720, 720, 690, 660, 630}},
+	{7, 12, [7]int{840, 840, 780, 780, 750, 720, 690}},
+	{12, 13, [7]int{780, 780, 780, 780, 750, 720, 690}},
+	{13, 17, [7]int{720, 720, 720, 720, 690, 660, 630}},
+	{17, 22, [7]int{720, 720, 660, 660, 600, 540, 540}},
+	{22, 23, [7]int{660, 660, 600, 600, 540, 540, 540}},
+	{23, 24, [7]int{600, 600, 600, 540, 540, 540, 540}},
+}
+
+// faa117TableC, §117 Tablo C'nin (uzatılmış ekip) satırlarıdır: başlangıç saati bandı ve dinlenme tesisi
+// sınıfı 1/2/3 için 3 ve 4 pilot limitleri (dakika).
+var faa117TableC = []struct {
+	fromHour, toHour int
+	limits           map[int][2]int
+}{
+	{0, 6, map[int][2]int{models.RestFacilityClass1: {900, 1020}, models.RestFacilityClass2: {840, 930}, models.RestFacilityClass3: {780, 810}}},
+	{6, 7, map[int][2]int{models.RestFacilityClass1: {960, 1110}, models.RestFacilityClass2: {900, 990}, models.RestFacilityClass3: {840, 870}}},
+	{7, 13, map[int][2]int{models.RestFacilityClass1: {1020, 1140}, models.RestFacilityClass2: {990, 1080}, models.RestFacilityClass3: {900, 930}}},
+	{13, 17, map[int][2]int{models.RestFacilityClass1: {960, 1110}, models.RestFacilityClass2: {900, 990}, models.RestFacilityClass3: {840, 870}}},
+	{17, 24, map[int][2]int{models.RestFacilityClass1: {900, 1020}, models.RestFacilityClass2: {840, 930}, models.RestFacilityClass3: {780, 810}}},
+}
+
+// faa117Regulation, FAA 14 CFR Part 117 çerçevesidir. UGS limitleri aklimatize olunan zaman dilimindeki
+// başlangıç saatine göre Tablo B / Tablo C'den, dinlenme §117.25'ten, kümülatif limitler §117.23'ten alınır.
+type faa117Regulation struct{}
+
+func (faa117Regulation) Code() string { return models.RegulationFAA117 }
+
+func (faa117Regulation) MaxFDP(trip *models.Trip, numSectors int) (FDPLimit, error) {
+	start := acclimatisedDutyStart(trip)
+	hour := start.Hour()
+	band := func(from, to int) string {
+		return fmt.Sprintf("%02d:00-%02d:59 (%s) başlangıç", from, to-1, start.Location())
+	}
+
+	if trip.FlightCrewComplement >= 3 {
+		for _, row := range faa117TableC {
+			if hour < row.fromHour || hour >= row.toHour {
+				continue
+			}
+			if limits, ok := row.limits[trip.RestFacilityClass]; ok {
+				limit := FDPLimit{LimitMin: limits[0], Augmented: true, Table: "§117 Tablo C",
+					Row: fmt.Sprintf("%s, dinlenme tesisi sınıf %d, %d pilot", band(row.fromHour, row.toHour), trip.RestFacilityClass, trip.FlightCrewComplement)}
+				if trip.FlightCrewComplement >= 4 {
+					limit.LimitMin = limits[1]
+				}
+				return limit, nil
+			}
+		}
+	}
+
+	column := numSectors - 1
+	if column > 6 {
+		column = 6
+	}
+	for _, row := range faa117TableB {
+		if hour < row.fromHour || hour >= row.toHour {
+			continue
+		}
+		limit := FDPLimit{LimitMin: row.limits[column], Table: "§117 Tablo B",
+			Row: fmt.Sprintf("%s, %d sektör", band(row.fromHour, row.toHour), numSectors)}
+		if trip.AcclimatisationState == AcclimatisationUnknown {
+			limit.LimitMin -= faa117UnacclimatisedReductionMin
+			limit.Row += fmt.Sprintf(", aklimatize değil (-%d dk)", faa117UnacclimatisedReductionMin)
+		}
+		return limit, nil
+	}
+	return FDPLimit{}, fmt.Errorf("geçerli §117 Tablo B limiti bulunamadı: Başlangıç: %s, Sektör: %d", start.Format("15:04"), numSectors)
+}
+
+// MinRest: önceki görev süresinden bağımsız olarak 10 saat (§117.25(e)).
+func (faa117Regulation) MinRest(*models.Trip, bool, models.FTLRuleSet) int {
+	return faa117MinRestMin
+}
+
+// CumulativeLimits: §117.23. Görev: 168 saatte 60, 672 saatte 190 saat; uçuş: 672 saatte 100, 365 günde
+// (12 aylık kayan pencere) 1000 saat.
+// 14 günlük ve yıllık görev ile takvim yılı uçuş limitleri Part 117'de bulunmaz.
+func (faa117Regulation) CumulativeLimits(ruleSet models.FTLRuleSet) models.FTLRuleSet {
+	ruleSet.MaxDuty7DaysMin = 60 * 60
+	ruleSet.MaxDuty14DaysMin = 0
+	ruleSet.MaxDuty28DaysMin = 190 * 60
+	ruleSet.MaxDutyYearMin = 0
+	ruleSet.MaxFlight28DaysMin = 100 * 60
+	ruleSet.MaxFlight12MonthsMin = 1000 * 60
+	ruleSet.MaxFlightYearMin = 0
+	return ruleSet
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Ana üs dışı dinlenme (konaklama tesisinde) parametreleri (ORO.FTL.235(b))
+const (
+	awayRestSleepOpportunityMin    = 8 * 60 // Konaklama tesisinde sağlanması gereken asgari uyku fırsatı
+	awayRestAccommodationTravelMin = 30     // Meydan ile konaklama tesisi arası tek yön yolculuk süresi
+)
+
+// baseAirportMap, meydan → ana üs kodu eşlemesini getirir. Ana üsler airports.is_base ile belirlenir;
+// crew_base_airports aynı ana üsse bağlı meydanları gruplar (grubu olmayan ana üs kendi kodunu kullanır).
+// Depo tanımlı değilse veya sorgu başarısız olursa boş harita döner (hiçbir meydan ana üs sayılmaz).
+func (f *FTLCalculator) baseAirportMap(ctx context.Context) map[string]string {
+	if f.baseAirportRepo == nil {
+		return map[string]string{}
+	}
+	bases, err := f.baseAirportRepo.GetBaseAirportMap(ctx)
+	if err != nil {
+		log.Printf("Uyarı: Ana üs meydanları çekilemedi: %v. Tüm meydanlar ana üs dışı sayılıyor.", err)
+		return map[string]string{}
+	}
+	return bases
+}
+
+// isHomeBaseAirport, meydanın ekip üyesinin ana üs grubuna ait olup olmadığını döndürür.
+// Ekip üyesinin ana üssü bilinmiyorsa, crew_base_airports tablosunda tanımlı herhangi bir meydan ana üs sayılır.
+func isHomeBaseAirport(airport, crewBase string, baseAirports map[string]string) bool {
+	if airport == "" {
+		return false
+	}
+	airportBase, known := baseAirports[airport]
+	if crewBase == "" {
+		return known
+	}
+	if !known {
+		airportBase = airport
+	}
+	homeBase, ok := baseAirports[crewBase]
+	if !ok {
+		homeBase = crewBase
+	}
+	return airportBase == homeBase
+}
+
+// requiredMinRest, trip'ten önce verilmesi gereken minimum dinlenmeyi ve dinlenmenin yerini döndürür.
+// Yer, görevin ana üste başlayıp başlamadığına göre belirlenir; minimum mevzuat çerçevesinden alınır
+// (SHT-FTL'de max(önceki görev süresi, ana üs / ana üs dışı minimumu)).
+func requiredMinRest(trip, prevTrip *models.Trip, crewBase string, baseAirports map[string]string, ruleSet models.FTLRuleSet, regulation Regulation) (string, int) {
+	location := models.RestLocationAwayAccommodation
+	atHomeBase := isHomeBaseAirport(trip.DutyStartAirport, crewBase, baseAirports)
+	if atHomeBase {
+		location = models.RestLocationHomeBase
+	}
+	return location, regulation.MinRest(prevTrip, atHomeBase, ruleSet)
+}
+
+// checkAwayRestSleepOpportunity, ana üs dışı dinlenmede konaklama tesisine gidiş-dönüş süresi düşüldükten
+// sonra kalan uyku fırsatının 8 saatten az olmaması gerektiğini kontrol eder.
+func checkAwayRestSleepOpportunity(trip, prevTrip *models.Trip) []models.FTLViolation {
+	if trip.RestLocation != models.RestLocationAwayAccommodation {
+		return nil
+	}
+	sleepMin := trip.CalculatedRestPeriodDurationMin - 2*awayRestAccommodationTravelMin
+	if sleepMin >= awayRestSleepOpportunityMin {
+		return nil
+	}
+	return []models.FTLViolation{{
+		RuleCode:            models.RuleAwayRestSleepOpportunityViolated,
+		Severity:            models.SeverityViolation,
+		WindowStart:         trip.CalculatedRestPeriodStart,
+		WindowEnd:           trip.CalculatedRestPeriodEnd,
+		MeasuredValue:       float64(sleepMin),
+		Limit:               float64(awayRestSleepOpportunityMin),
+		Unit:                models.UnitMinutes,
+		ContributingTripIDs: []string{prevTrip.TripID, trip.TripID},
+	}}
+}
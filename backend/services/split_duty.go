@@ -0,0 +1,89 @@
+package services
+
+import (
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Bölünmüş görev parametreleri (CS FTL.1.220)
+const (
+	splitDutyGroundDutyMin       = 60     // Moladan düşülen uçuş sonrası + uçuş öncesi görev ve transfer süresi
+	splitDutyMinEffectiveBreak   = 3 * 60 // UGS uzatması için gereken asgari etkin mola
+	splitDutyAccommodationBreak  = 6 * 60 // Bu süre ve üzerindeki molalar için konaklama gerekir
+	splitDutyExtensionPercentage = 50     // Etkin molanın UGS limitine eklenen yüzdesi
+	woclStartHour, woclEndHour   = 2, 6   // Vücut ritminin en düşük olduğu pencere (02:00-05:59)
+)
+
+// activitySpan, bir aktivitenin yerde geçmeyen zaman aralığını döndürür. Uçuşlarda kalkış/iniş,
+// diğer aktivitelerde görev başlangıç/bitişi kullanılır.
+func activitySpan(act models.Actual) (time.Time, time.Time) {
+	if act.GroupCode == "FLT" && !act.DepartureTime.IsZero() && !act.ArrivalTime.IsZero() {
+		return act.DepartureTime, act.ArrivalTime
+	}
+	return act.DutyStart, act.DutyEnd
+}
+
+// detectSplitDuty, trip aktiviteleri arasındaki en uzun boşluğu bulur ve bölünmüş görev
+// uzatmasına uygun olup olmadığını değerlendirir. WOCL kontrolü referans zaman diliminde yapılır.
+// Aday mola (yer görevleri düşüldükten sonra pozitif boşluk) yoksa nil döner.
+func detectSplitDuty(trip *models.Trip, reference *time.Location) *models.SplitDutyDecision {
+	if len(trip.Activities) < 2 {
+		return nil
+	}
+
+	var bestStart, bestEnd time.Time
+	_, prevEnd := activitySpan(trip.Activities[0])
+	for _, act := range trip.Activities[1:] {
+		start, end := activitySpan(act)
+		if start.Sub(prevEnd) > bestEnd.Sub(bestStart) {
+			bestStart, bestEnd = prevEnd, start
+		}
+		if end.After(prevEnd) {
+			prevEnd = end
+		}
+	}
+
+	breakMin := int(bestEnd.Sub(bestStart).Minutes())
+	effectiveMin := breakMin - splitDutyGroundDutyMin
+	if effectiveMin <= 0 {
+		return nil
+	}
+
+	if reference == nil {
+		reference = time.UTC
+	}
+	decision := &models.SplitDutyDecision{
+		BreakStart:        bestStart,
+		BreakEnd:          bestEnd,
+		BreakDurationMin:  breakMin,
+		EffectiveBreakMin: effectiveMin,
+		BreakType:         models.SplitDutyBreakNonAccommodation,
+		EncroachesWOCL:    encroachesWOCL(bestStart.In(reference), bestEnd.In(reference)),
+	}
+	if effectiveMin >= splitDutyAccommodationBreak || decision.EncroachesWOCL {
+		decision.BreakType = models.SplitDutyBreakAccommodation
+	}
+
+	if effectiveMin < splitDutyMinEffectiveBreak {
+		decision.Decision = models.SplitDutyBreakTooShort
+		return decision
+	}
+	decision.Decision = models.SplitDutyApplied
+	decision.ExtensionMin = effectiveMin * splitDutyExtensionPercentage / 100
+	return decision
+}
+
+// encroachesWOCL, [start, end) aralığının herhangi bir günün 02:00-05:59 penceresiyle kesişip kesişmediğini döndürür.
+func encroachesWOCL(start, end time.Time) bool {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location()).AddDate(0, 0, -1)
+	for !day.After(end) {
+		woclStart := day.Add(woclStartHour * time.Hour)
+		woclEnd := day.Add(woclEndHour * time.Hour)
+		if start.Before(woclEnd) && end.After(woclStart) {
+			return true
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Yedek/rezerv görev parametreleri (CS FTL.1.225 ve ORO.FTL.230)
+const (
+	standbyCallOutWindow            = 3 * time.Hour // Ayrı trip olarak girilmiş yedeğin bitişi ile raporlama arasındaki azami süre
+	airportStandbyFDPThresholdMin   = 4 * 60        // Havalimanı yedeğinin bu süreyi aşan kısmı UGS limitinden düşülür
+	homeStandbyFDPThresholdMin      = 6 * 60        // Evde yedeğin bu süreyi aşan kısmı UGS limitinden düşülür
+	homeStandbyExtendedThresholdMin = 8 * 60        // Uzatılmış ekip veya bölünmüş görevde evde yedek eşiği
+	homeStandbyMaxAwakeMin          = 18 * 60       // Evde yedek + UGS toplamı için azami süre
+)
+
+// standbyDutyPercentage, yedek tipinin kümülatif görev pencerelerine sayılan yüzdesini döndürür.
+func standbyDutyPercentage(standbyType string) int {
+	switch standbyType {
+	case models.StandbyTypeAirport:
+		return 100
+	case models.StandbyTypeHome:
+		return 25
+	}
+	return 0 // Rezerv görev sayılmaz
+}
+
+// isStandbyOnlyTrip, trip'teki tüm aktivitelerin yedek/rezerv olup olmadığını döndürür.
+func isStandbyOnlyTrip(trip *models.Trip) bool {
+	if len(trip.Activities) == 0 {
+		return false
+	}
+	for _, act := range trip.Activities {
+		if models.GetStandbyTypeFromActivityCode(act.ActivityCode) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// applyStandbyAccounting, trip içindeki veya hemen önceki ayrı tripteki yedek/rezerv görevi değerlendirir:
+// trip'in yedek alanlarını, yedekten sonraki UGS süresini ve kümülatif pencerelere sayılan görev süresini günceller.
+// Yedekten çağrılan trip önceki yedek tripinden türetildiyse true döner (dinlenme kontrolü bu durumda yapılmaz).
+func applyStandbyAccounting(trip *models.Trip, prevTrip *models.Trip) bool {
+	trip.StandbyType = ""
+	trip.StandbyDurationMin = 0
+	trip.StandbyCalledOut = false
+	trip.StandbyFDPReductionMin = 0
+	trip.CountedDutyMin = trip.CalculatedDutyPeriodDurationMin
+
+	var fdpStart time.Time
+	for _, act := range trip.Activities {
+		standbyType := models.GetStandbyTypeFromActivityCode(act.ActivityCode)
+		if standbyType == "" {
+			if fdpStart.IsZero() {
+				fdpStart = act.DutyStart
+			}
+			continue
+		}
+		if trip.StandbyType == "" {
+			trip.StandbyType = standbyType
+		}
+		trip.StandbyDurationMin += int(act.DutyEnd.Sub(act.DutyStart).Minutes())
+	}
+
+	if trip.StandbyType != "" {
+		standbyCountedMin := trip.StandbyDurationMin * standbyDutyPercentage(trip.StandbyType) / 100
+		trip.CountedDutyMin = trip.CalculatedDutyPeriodDurationMin - trip.StandbyDurationMin + standbyCountedMin
+		if fdpStart.IsZero() {
+			// Yalnızca yedek: uçuş görevi yoktur
+			trip.CalculatedFlightDutyPeriodDurationMin = 0
+			return false
+		}
+		// Yedekten çağrılma: UGS yedek bitiminden sonraki raporlamayla başlar
+		trip.StandbyCalledOut = true
+		trip.CalculatedFlightDutyPeriodDurationMin = int(trip.LastLegArrivalTime.Sub(fdpStart).Minutes())
+		return false
+	}
+
+	if prevTrip == nil || !isStandbyOnlyTrip(prevTrip) {
+		return false
+	}
+	gap := trip.CalculatedDutyPeriodStart.Sub(prevTrip.CalculatedDutyPeriodEnd)
+	if gap < 0 || gap > standbyCallOutWindow {
+		return false
+	}
+	trip.StandbyType = prevTrip.StandbyType
+	trip.StandbyDurationMin = prevTrip.StandbyDurationMin
+	trip.StandbyCalledOut = true
+	return true
+}
+
+// standbyFDPReduction, yedekten çağrılan trip için UGS limitinden düşülecek süreyi döndürür.
+func standbyFDPReduction(trip *models.Trip, extended bool) int {
+	if !trip.StandbyCalledOut {
+		return 0
+	}
+	threshold := 0
+	switch trip.StandbyType {
+	case models.StandbyTypeAirport:
+		threshold = airportStandbyFDPThresholdMin
+	case models.StandbyTypeHome:
+		threshold = homeStandbyFDPThresholdMin
+		if extended {
+			threshold = homeStandbyExtendedThresholdMin
+		}
+	default:
+		return 0
+	}
+	if trip.StandbyDurationMin <= threshold {
+		return 0
+	}
+	return trip.StandbyDurationMin - threshold
+}
+
+// countedDutyMin, trip'in kümülatif görev pencerelerine sayılan süresini döndürür.
+// Yedek hesaplaması yapılmamış eski kayıtlarda görev süresinin tamamı kullanılır.
+func countedDutyMin(t *models.Trip) int {
+	if t.CountedDutyMin == 0 && t.StandbyType == "" {
+		return t.CalculatedDutyPeriodDurationMin
+	}
+	return t.CountedDutyMin
+}
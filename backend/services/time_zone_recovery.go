@@ -0,0 +1,153 @@
+package services
+
+import (
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// Saat dilimi geçişli rotasyonlardan sonra ana üste verilecek dinlenme (CS FTL.1.235(b)(3))
+const timeZoneRecoveryMinDiff = 4 * time.Hour // Bu farkın altındaki rotasyonlar için ek dinlenme gerekmez
+
+// rotationSummary, ana üsten başlayıp ana üste dönen ardışık triplerin saat dilimi özetini tutar.
+type rotationSummary struct {
+	start   time.Time
+	end     time.Time
+	maxDiff time.Duration
+	tripIDs []string
+}
+
+// tripEndAirport, trip'in son aktivitesinin varış meydanını döndürür (aktivite yoksa görev başlangıç meydanı).
+func tripEndAirport(trip *models.Trip) string {
+	for i := len(trip.Activities) - 1; i >= 0; i-- {
+		if trip.Activities[i].ArrivalPort != "" {
+			return trip.Activities[i].ArrivalPort
+		}
+	}
+	return trip.DutyStartAirport
+}
+
+// maxTimeZoneDifference, trip aktivitelerinin kalkış ve varış meydanlarının ana üs zaman dilimine göre
+// en büyük farkını döndürür. Zaman dilimi bilinmeyen meydanlar hesaba katılmaz.
+func maxTimeZoneDifference(trip *models.Trip, base *time.Location) time.Duration {
+	var maxDiff time.Duration
+	for _, act := range trip.Activities {
+		at := act.DepartureTime
+		if at.IsZero() {
+			at = act.DutyStart
+		}
+		for _, port := range []string{act.DeparturePort, act.ArrivalPort} {
+			loc, ok := models.GetAirportLocation(port)
+			if !ok {
+				continue
+			}
+			if diff := timeZoneDifference(loc, base, at); diff > maxDiff {
+				maxDiff = diff
+			}
+		}
+	}
+	return maxDiff
+}
+
+// timeZoneRecoveryNightsTable, CS FTL.1.235(b)(3) tablosudur. Satırlar en büyük saat farkı (≤6h, ≤9h, >9h),
+// sütunlar rotasyondaki ilk görevin başlangıcından itibaren geçen süredir (<48h, 48-71:59h, 72-95:59h, ≥96h).
+var timeZoneRecoveryNightsTable = [3][4]int{
+	{2, 2, 3, 3},
+	{2, 3, 3, 4},
+	{2, 3, 4, 5},
+}
+
+// requiredTimeZoneRecoveryNights, rotasyondaki en büyük saat farkı ve ana üsten uzakta geçen süreye göre
+// ana üste dönüşte verilmesi gereken yerel gece sayısını döndürür (0 = ek dinlenme gerekmez).
+func requiredTimeZoneRecoveryNights(maxDiff, away time.Duration) int {
+	if maxDiff < timeZoneRecoveryMinDiff {
+		return 0
+	}
+	row := 2
+	switch {
+	case maxDiff <= 6*time.Hour:
+		row = 0
+	case maxDiff <= 9*time.Hour:
+		row = 1
+	}
+	col := 0
+	switch {
+	case away >= 96*time.Hour:
+		col = 3
+	case away >= 72*time.Hour:
+		col = 2
+	case away >= 48*time.Hour:
+		col = 1
+	}
+	return timeZoneRecoveryNightsTable[row][col]
+}
+
+// timeZoneRotation, ana üsten başlayıp ana üste dönen rotasyonların trip sırası boyunca taşınan durumudur.
+type timeZoneRotation struct {
+	current *rotationSummary // Henüz ana üste dönmemiş rotasyon
+	closed  *rotationSummary // Son trip'in ana üste dönerek kapattığı rotasyon
+	prev    *models.Trip
+}
+
+// add, hesaplanmış trip'i rotasyona katar; trip ana üste dönüyorsa rotasyonu kapatır.
+// Triplere görev başlangıç sırasıyla çağrılmalıdır.
+func (r *timeZoneRotation) add(t *models.Trip, crewBase string, baseAirports map[string]string, base *time.Location) {
+	if r.current == nil {
+		r.current = &rotationSummary{start: t.CalculatedDutyPeriodStart}
+	}
+	r.current.end = t.CalculatedDutyPeriodEnd
+	r.current.tripIDs = append(r.current.tripIDs, t.TripID)
+	if diff := maxTimeZoneDifference(t, base); diff > r.current.maxDiff {
+		r.current.maxDiff = diff
+	}
+
+	r.closed = nil
+	if isHomeBaseAirport(tripEndAirport(t), crewBase, baseAirports) {
+		r.closed = r.current
+		r.current = nil
+	}
+	r.prev = t
+}
+
+// applyTimeZoneRecovery, trip'in saat dilimi farkını ve ana üsten uzakta geçen süreyi hesaplar; önceki trip
+// saat dilimi geçişli bir rotasyonu ana üste döndürerek kapattıysa, aradaki dinlenmenin gerekli sayıda yerel
+// gece (ana üs saatine göre 22:00-08:00 arasına düşen 8 saat) içerip içermediğini kontrol eder. rotation,
+// trip'ten önceki triplerle doldurulmuş olmalıdır; trip'in kendisi rotasyona çağıran tarafından eklenir.
+func applyTimeZoneRecovery(trip *models.Trip, rotation *timeZoneRotation, base *time.Location) []models.FTLViolation {
+	trip.MaxTimeZoneDiffMin = int(maxTimeZoneDifference(trip, base).Minutes())
+	trip.TimeAwayFromBaseMin = 0
+	trip.RequiredRecoveryNights = 0
+
+	// Trip'in kendisi açık rotasyonun devamıysa uzakta geçen süre rotasyon başından itibaren sayılır
+	rotationStart := trip.CalculatedDutyPeriodStart
+	if rotation.current != nil {
+		rotationStart = rotation.current.start
+	}
+	trip.TimeAwayFromBaseMin = int(trip.CalculatedDutyPeriodEnd.Sub(rotationStart).Minutes())
+
+	closed, prev := rotation.closed, rotation.prev
+	if closed == nil || prev == nil {
+		return nil
+	}
+	nights := requiredTimeZoneRecoveryNights(closed.maxDiff, closed.end.Sub(closed.start))
+	if nights == 0 {
+		return nil
+	}
+	trip.RequiredRecoveryNights = nights
+
+	restStart, restEnd := prev.CalculatedDutyPeriodEnd, trip.CalculatedDutyPeriodStart
+	granted := countLocalNights(restStart, restEnd, base)
+	if granted >= nights {
+		return nil
+	}
+	return []models.FTLViolation{{
+		RuleCode:            models.RuleTimeZoneRecoveryRestViolated,
+		Severity:            models.SeverityViolation,
+		WindowStart:         restStart,
+		WindowEnd:           restEnd,
+		MeasuredValue:       float64(granted),
+		Limit:               float64(nights),
+		Unit:                models.UnitCount,
+		ContributingTripIDs: append(append([]string(nil), closed.tripIDs...), trip.TripID),
+	}}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"mini_CMS_Desktop_App/models"
+)
+
+// ErrInvalidWhatIfChange, what-if değişikliği geçersiz olduğunda (eksik alan, bulunamayan trip vb.) döner.
+var ErrInvalidWhatIfChange = errors.New("geçersiz what-if değişikliği")
+
+// EvaluateSchedule, ekip üyesinin trip listesini program sırasıyla tek geçişte hesaplar; hiçbir şey kaydedilmez.
+// Liste FirstLegDepartureTime'a göre yerinde sıralanır.
+func (f *FTLCalculator) EvaluateSchedule(ctx context.Context, trips []*models.Trip) {
+	if len(trips) == 0 {
+		return
+	}
+	sort.SliceStable(trips, func(i, j int) bool {
+		return trips[i].FirstLegDepartureTime.Before(trips[j].FirstLegDepartureTime)
+	})
+	state := f.newCrewScheduleState(ctx, trips[0].CrewMemberID, trips)
+	for _, trip := range trips {
+		if err := f.calculateFTLForTrip(trip, state); err != nil {
+			log.Printf("Hata: Trip %s için FTL hesaplanırken sorun (kaydedilmeyen değerlendirme): %v", trip.TripID, err)
+		}
+	}
+}
+
+// populateTripSummary, aktivitelerden trip'in özet alanlarını (ilk/son bacak, görev başlangıç meydanı,
+// brief/debrief görev ve uçak tipleri) RecalculateCrewSchedule ile aynı kurallarla doldurur.
+func populateTripSummary(trip *models.Trip) {
+	sort.Slice(trip.Activities, func(i, j int) bool {
+		return trip.Activities[i].DutyStart.Before(trip.Activities[j].DutyStart)
+	})
+
+	var firstFLT, lastFLT *models.Actual
+	for i := range trip.Activities {
+		trip.Activities[i].AircraftType = models.GetAircraftTypeFromCmsType(trip.Activities[i].PlaneCmsType)
+		if trip.Activities[i].GroupCode == "FLT" {
+			if firstFLT == nil {
+				firstFLT = &trip.Activities[i]
+			}
+			lastFLT = &trip.Activities[i]
+		}
+	}
+	first, last := &trip.Activities[0], &trip.Activities[len(trip.Activities)-1]
+	if firstFLT != nil {
+		trip.FirstLegDepartureTime = firstFLT.DepartureTime
+		trip.LastLegArrivalTime = lastFLT.ArrivalTime
+	} else {
+		trip.FirstLegDepartureTime = first.DutyStart
+		trip.LastLegArrivalTime = last.DutyEnd
+		firstFLT, lastFLT = first, last
+	}
+
+	trip.BriefTripType = models.GetDutyTypeFromActual(firstFLT)
+	trip.DebriefTripType = models.GetDutyTypeFromActual(lastFLT)
+	trip.BriefAircraftType = models.GetAircraftTypeFromCmsType(firstFLT.PlaneCmsType)
+	trip.DebriefAircraftType = models.GetAircraftTypeFromCmsType(lastFLT.PlaneCmsType)
+	trip.DutyStartAirport = first.DeparturePort
+	trip.DutyType = models.GetDutyTypeFromActual(first)
+	trip.CrewType = models.GetCrewTypeFromActivities(trip.Activities)
+}
+
+// shiftTrip, trip'in ve aktivitelerinin tüm zamanlarını verilen süre kadar kaydırır.
+func shiftTrip(trip *models.Trip, shift time.Duration) {
+	for i := range trip.Activities {
+		act := &trip.Activities[i]
+		for _, t := range []*time.Time{&act.DutyStart, &act.DutyEnd, &act.DepartureTime, &act.ArrivalTime} {
+			if !t.IsZero() {
+				*t = t.Add(shift)
+			}
+		}
+	}
+	trip.FirstLegDepartureTime = trip.FirstLegDepartureTime.Add(shift)
+	trip.LastLegArrivalTime = trip.LastLegArrivalTime.Add(shift)
+}
+
+// copyTrip, hesaplama sırasında değiştirilecek alanları paylaşmayan bir trip kopyası döndürür.
+func copyTrip(t *models.Trip) *models.Trip {
+	c := *t
+	c.Activities = append([]models.Actual(nil), t.Activities...)
+	c.FTLViolations = nil
+	c.CumulativeWindows = nil
+	return &c
+}
+
+// EvaluateWhatIf, verilen değişiklikleri etkilenen ekip üyelerinin mevcut triplerine uygular; mevcut ve önerilen
+// programı ayrı ayrı hesaplayarak trip bazında ihlal farklarını döndürür. Veritabanına hiçbir şey yazılmaz.
+func (f *FTLCalculator) EvaluateWhatIf(ctx context.Context, changes []models.WhatIfChange) ([]models.WhatIfCrewResult, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("%w: en az bir değişiklik gerekli", ErrInvalidWhatIfChange)
+	}
+
+	current := map[string][]*models.Trip{}
+	var crewOrder []string
+	load := func(crewID string) error {
+		if crewID == "" {
+			return fmt.Errorf("%w: crew_member_id boş olamaz", ErrInvalidWhatIfChange)
+		}
+		if _, ok := current[crewID]; ok {
+			return nil
+		}
+		stored, err := f.tripRepo.GetTripsByCrewMemberID(crewID, time.Now().AddDate(-1, 0, -28))
+		if err != nil {
+			return err
+		}
+		trips := make([]*models.Trip, len(stored))
+		for i := range stored {
+			trips[i] = &stored[i]
+		}
+		current[crewID] = trips
+		crewOrder = append(crewOrder, crewID)
+		return nil
+	}
+	for _, ch := range changes {
+		if err := load(ch.CrewMemberID); err != nil {
+			return nil, err
+		}
+		if ch.Action == models.WhatIfMove {
+			if err := load(ch.FromCrewMemberID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	proposed := map[string][]*models.Trip{}
+	status := map[string]map[string]string{}
+	for crewID, trips := range current {
+		status[crewID] = map[string]string{}
+		for _, t := range trips {
+			proposed[crewID] = append(proposed[crewID], copyTrip(t))
+		}
+	}
+
+	find := func(crewID, tripID string) int {
+		for i, t := range proposed[crewID] {
+			if t.TripID == tripID {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, ch := range changes {
+		if ch.TripID == "" {
+			return nil, fmt.Errorf("%w: trip_id boş olamaz", ErrInvalidWhatIfChange)
+		}
+		switch ch.Action {
+		case models.WhatIfAdd:
+			if len(ch.Activities) == 0 {
+				return nil, fmt.Errorf("%w: %s eklemesi için activities gerekli", ErrInvalidWhatIfChange, ch.TripID)
+			}
+			if find(ch.CrewMemberID, ch.TripID) >= 0 {
+				return nil, fmt.Errorf("%w: %s zaten ekip %s programında", ErrInvalidWhatIfChange, ch.TripID, ch.CrewMemberID)
+			}
+			trip := &models.Trip{TripID: ch.TripID, CrewMemberID: ch.CrewMemberID, Activities: append([]models.Actual(nil), ch.Activities...)}
+			populateTripSummary(trip)
+			proposed[ch.CrewMemberID] = append(proposed[ch.CrewMemberID], trip)
+			status[ch.CrewMemberID][ch.TripID] = models.WhatIfTripAdded
+
+		case models.WhatIfRemove:
+			i := find(ch.CrewMemberID, ch.TripID)
+			if i < 0 {
+				return nil, fmt.Errorf("%w: %s ekip %s programında bulunamadı", ErrInvalidWhatIfChange, ch.TripID, ch.CrewMemberID)
+			}
+			proposed[ch.CrewMemberID] = append(proposed[ch.CrewMemberID][:i], proposed[ch.CrewMemberID][i+1:]...)
+			status[ch.CrewMemberID][ch.TripID] = models.WhatIfTripRemoved
+
+		case models.WhatIfMove:
+			i := find(ch.FromCrewMemberID, ch.TripID)
+			if i < 0 {
+				return nil, fmt.Errorf("%w: %s ekip %s programında bulunamadı", ErrInvalidWhatIfChange, ch.TripID, ch.FromCrewMemberID)
+			}
+			if find(ch.CrewMemberID, ch.TripID) >= 0 {
+				return nil, fmt.Errorf("%w: %s zaten ekip %s programında", ErrInvalidWhatIfChange, ch.TripID, ch.CrewMemberID)
+			}
+			trip := proposed[ch.FromCrewMemberID][i]
+			proposed[ch.FromCrewMemberID] = append(proposed[ch.FromCrewMemberID][:i], proposed[ch.FromCrewMemberID][i+1:]...)
+			trip.CrewMemberID = ch.CrewMemberID
+			for k := range trip.Activities {
+				trip.Activities[k].PersonID = ch.CrewMemberID
+			}
+			proposed[ch.CrewMemberID] = append(proposed[ch.CrewMemberID], trip)
+			status[ch.FromCrewMemberID][ch.TripID] = models.WhatIfTripRemoved
+			status[ch.CrewMemberID][ch.TripID] = models.WhatIfTripAdded
+
+		case models.WhatIfShift:
+			i := find(ch.CrewMemberID, ch.TripID)
+			if i < 0 {
+				return nil, fmt.Errorf("%w: %s ekip %s programında bulunamadı", ErrInvalidWhatIfChange, ch.TripID, ch.CrewMemberID)
+			}
+			if ch.ShiftMin == 0 {
+				return nil, fmt.Errorf("%w: %s kaydırması için shift_min gerekli", ErrInvalidWhatIfChange, ch.TripID)
+			}
+			shiftTrip(proposed[ch.CrewMemberID][i], time.Duration(ch.ShiftMin)*time.Minute)
+			if status[ch.CrewMemberID][ch.TripID] == "" {
+				status[ch.CrewMemberID][ch.TripID] = models.WhatIfTripModified
+			}
+
+		default:
+			return nil, fmt.Errorf("%w: bilinmeyen action '%s'", ErrInvalidWhatIfChange, ch.Action)
+		}
+	}
+
+	var results []models.WhatIfCrewResult
+	for _, crewID := range crewOrder {
+		before := make([]*models.Trip, len(current[crewID]))
+		for i, t := range current[crewID] {
+			before[i] = copyTrip(t)
+		}
+		after := proposed[crewID]
+		f.EvaluateSchedule(ctx, before)
+		f.EvaluateSchedule(ctx, after)
+		results = append(results, compareWhatIf(crewID, before, after, status[crewID]))
+	}
+	return results, nil
+}
+
+// compareWhatIf, mevcut ve önerilen programın trip bazında ihlal farklarını çıkarır.
+// Değişmemiş ve ihlalsiz trip'ler sonuca eklenmez.
+func compareWhatIf(crewID string, before, after []*models.Trip, status map[string]string) models.WhatIfCrewResult {
+	result := models.WhatIfCrewResult{CrewMemberID: crewID, Trips: []models.WhatIfTripResult{}}
+
+	violationsOf := func(trips []*models.Trip) map[string][]models.FTLViolation {
+		m := map[string][]models.FTLViolation{}
+		for _, t := range trips {
+			m[t.TripID] = t.FTLViolations
+		}
+		return m
+	}
+	beforeViolations, afterViolations := violationsOf(before), violationsOf(after)
+
+	var tripIDs []string
+	seen := map[string]bool{}
+	for _, list := range [][]*models.Trip{after, before} {
+		for _, t := range list {
+			if !seen[t.TripID] {
+				seen[t.TripID] = true
+				tripIDs = append(tripIDs, t.TripID)
+			}
+		}
+	}
+
+	for _, tripID := range tripIDs {
+		cur, prop := beforeViolations[tripID], afterViolations[tripID]
+		result.CurrentViolationCount += len(cur)
+		result.ProposedViolationCount += len(prop)
+
+		tripStatus := status[tripID]
+		if tripStatus == "" {
+			tripStatus = models.WhatIfTripUnchanged
+		}
+		if tripStatus == models.WhatIfTripUnchanged && len(cur) == 0 && len(prop) == 0 {
+			continue
+		}
+		result.Trips = append(result.Trips, models.WhatIfTripResult{
+			TripID:             tripID,
+			Status:             tripStatus,
+			CurrentViolations:  nonNilViolations(cur),
+			ProposedViolations: nonNilViolations(prop),
+			NewViolations:      violationDifference(prop, cur),
+			ResolvedViolations: violationDifference(cur, prop),
+		})
+	}
+	return result
+}
+
+// violationDifference, a'da olup b'de (kural koduna göre, tekrar sayısı dikkate alınarak) olmayan ihlalleri döndürür.
+func violationDifference(a, b []models.FTLViolation) []models.FTLViolation {
+	remaining := map[string]int{}
+	for _, v := range b {
+		remaining[v.RuleCode]++
+	}
+	diff := []models.FTLViolation{}
+	for _, v := range a {
+		if remaining[v.RuleCode] > 0 {
+			remaining[v.RuleCode]--
+			continue
+		}
+		diff = append(diff, v)
+	}
+	return diff
+}
+
+func nonNilViolations(v []models.FTLViolation) []models.FTLViolation {
+	if v == nil {
+		return []models.FTLViolation{}
+	}
+	return v
+}